@@ -0,0 +1,92 @@
+package main
+
+import (
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(archiveCmd)
+	rootCmd.AddCommand(unarchiveCmd)
+}
+
+// Archive command
+var archiveCmd = &cobra.Command{
+	Use:   "archive [file...]",
+	Short: "Retire assignments without deleting them",
+	Long: `Mark assignments as archived. Archived assignments stay on disk but are
+hidden from 'list' (unless --include-archived is given) and skipped by
+batch and queue syncs. 'sync' refuses them until they are unarchived.`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeAssignmentFiles,
+	RunE:              runArchive,
+}
+
+// Unarchive command
+var unarchiveCmd = &cobra.Command{
+	Use:               "unarchive [file...]",
+	Short:             "Bring archived assignments back into use",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeAssignmentFiles,
+	RunE:              runUnarchive,
+}
+
+func runArchive(cmd *cobra.Command, args []string) error {
+	return setArchived(args, true)
+}
+
+func runUnarchive(cmd *cobra.Command, args []string) error {
+	return setArchived(args, false)
+}
+
+// setArchived archives or unarchives each file, carrying on past files
+// that can't be loaded or saved
+func setArchived(files []string, archived bool) error {
+	state := "archived"
+	if !archived {
+		state = "unarchived"
+	}
+
+	failed := 0
+	for _, file := range files {
+		pkg, err := toolkit.LoadPackage(file)
+		if err != nil {
+			printError("Failed to load %s: %v", file, err)
+			failed++
+			continue
+		}
+		if pkg.Metadata.Archived == archived {
+			printMessage(iconNote, "%s is already %s", file, state)
+			continue
+		}
+
+		pkg.Metadata.Archived = archived
+		pkg.Metadata.Modified = toolkit.Now()
+		if err := toolkit.SavePackage(pkg, file); err != nil {
+			printError("Failed to save %s: %v", file, err)
+			failed++
+			continue
+		}
+		printSuccess("%s %s", file, state)
+	}
+
+	if failed > 0 {
+		return errFailed
+	}
+	return nil
+}
+
+// withoutArchived drops archived assignments from files, returning how
+// many were dropped. Files that fail to load are kept so the error shows.
+func withoutArchived(files []string) ([]string, int) {
+	var active []string
+	hidden := 0
+	for _, file := range files {
+		if pkg, err := toolkit.LoadPackage(file); err == nil && pkg.Metadata.Archived {
+			hidden++
+			continue
+		}
+		active = append(active, file)
+	}
+	return active, hidden
+}
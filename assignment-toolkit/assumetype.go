@@ -0,0 +1,32 @@
+package main
+
+import (
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+// assumeTypeUsage is shared by the commands that accept --assume-type
+const assumeTypeUsage = "Treat files with no type as this type, e.g. for legacy files"
+
+// assumedType returns the canonical type given with --assume-type, or ""
+func assumedType(cmd *cobra.Command) (string, error) {
+	value, _ := cmd.Flags().GetString("assume-type")
+	if value == "" {
+		return "", nil
+	}
+	canonical, ok := toolkit.GetTypeManager().CanonicalType(value)
+	if !ok {
+		return "", usageErrorf("Unknown assignment type %q for --assume-type; run 'assignment-toolkit types' to list them", value)
+	}
+	return canonical, nil
+}
+
+// assumeType gives a package with no type the assumed one, reporting
+// whether it did
+func assumeType(pkg *toolkit.AssignmentPackage, assumed string) bool {
+	if assumed == "" || pkg.Assignment.Type != "" {
+		return false
+	}
+	pkg.Assignment.Type = assumed
+	return true
+}
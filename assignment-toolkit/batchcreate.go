@@ -0,0 +1,260 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	batchCreateCmd.Flags().String("template", "", "Template for rows without a template column (defaults to base_template)")
+	batchCreateCmd.Flags().Bool("stable-id", false, "Derive each package ID from its type and title")
+	batchCreateCmd.Flags().Bool("compress", false, "Save the assignments gzip-compressed (.yaml.gz)")
+	batchCreateCmd.Flags().String("output-dir", "", outputDirUsage)
+	batchCreateCmd.Flags().String("layout", "", layoutUsage)
+	batchCreateCmd.MarkFlagDirname("output-dir")
+	batchCreateCmd.Flags().Bool("force", false, "Overwrite assignment files that already exist")
+	batchCreateCmd.RegisterFlagCompletionFunc("template", completeTemplates)
+	rootCmd.AddCommand(batchCreateCmd)
+}
+
+// Batch-create command
+var batchCreateCmd = &cobra.Command{
+	Use:   "batch-create [file.csv]",
+	Short: "Create one assignment per row of a spreadsheet",
+	Long: `Create assignments without the wizard from a CSV file with a header row.
+Each row becomes one assignment file; title and type are required:
+
+  title,type,points,quarter,tags,questions
+  Fractions 1,multiple-choice,5,Q1,maths;fractions,questions/fractions-1.md
+  Fractions essay,essay,10,Q1,maths,
+
+Columns: ` + strings.Join(batchColumns, ", ") + `.
+tags are separated by semicolons and due_date is YYYY-MM-DD. questions
+names a Markdown question file (as used by 'create --from-md') or a YAML
+or JSON file of questions, relative to the CSV file; a cell spanning
+several lines is read as Markdown questions itself. Rows that fail are
+reported by line number and the rest are still created.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeCSVFile,
+	RunE:              runBatchCreate,
+}
+
+// batchColumns are the CSV columns batch-create understands
+var batchColumns = []string{
+	"title", "type", "description", "instructions", "criteria", "category",
+	"difficulty", "points", "quarter", "tags", "due_date", "template",
+	"questions", "id",
+}
+
+func runBatchCreate(cmd *cobra.Command, args []string) error {
+	file, err := os.Open(args[0])
+	if err != nil {
+		return failf("Failed to open %s: %v", args[0], err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return failf("Failed to read the header row of %s: %v", args[0], err)
+	}
+	for i, column := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(column))
+		if !containsString(batchColumns, header[i]) {
+			return usageErrorf("Unknown column %q in %s (known: %s)", column, args[0], strings.Join(batchColumns, ", "))
+		}
+	}
+	if !containsString(header, "title") {
+		return usageErrorf("%s needs a title column", args[0])
+	}
+
+	config := getConfig()
+	baseDir := filepath.Dir(args[0])
+	created, rows, failed := 0, 0, 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if parseErr, ok := err.(*csv.ParseError); ok {
+			printError("Line %d: %v", parseErr.StartLine, parseErr.Err)
+			rows++
+			failed++
+			continue
+		} else if err != nil {
+			return failf("Failed to read %s: %v", args[0], err)
+		}
+		line, _ := reader.FieldPos(0)
+		rows++
+
+		row := make(map[string]string, len(header))
+		for i, value := range record {
+			row[header[i]] = strings.TrimSpace(value)
+		}
+
+		filename, err := createFromRow(cmd, config, row, baseDir)
+		if err != nil {
+			printError("Line %d: %v", line, err)
+			failed++
+			continue
+		}
+		printSuccess("Line %d: created %s", line, filename)
+		created++
+	}
+
+	fmt.Println()
+	fmt.Printf("Created %d of %d assignment(s)\n", created, rows)
+	if failed > 0 {
+		return errFailed
+	}
+	return nil
+}
+
+// createFromRow builds and saves the assignment one CSV row describes,
+// returning the file it wrote
+func createFromRow(cmd *cobra.Command, config toolkit.Config, row map[string]string, baseDir string) (string, error) {
+	seed := defaultAssignment()
+	templateName := row["template"]
+	if templateName == "" {
+		templateName = flagOrDefault(cmd, "template", config.BaseTemplate)
+	}
+	if templateName != "" {
+		template, err := loadTemplate(templateName, config)
+		if err != nil {
+			return "", err
+		}
+		seed = template.Template
+		seed.Type = template.Type
+		if license := template.Metadata["license"]; license != "" {
+			config.License = license
+		}
+		if language := template.Metadata["language"]; language != "" {
+			config.Language = language
+		}
+	}
+
+	assignment := seed
+	if row["type"] != "" {
+		assignment.Type = row["type"]
+	}
+	assignmentType, ok := toolkit.GetTypeManager().CanonicalType(assignment.Type)
+	if !ok {
+		if assignment.Type == "" {
+			return "", fmt.Errorf("type is required")
+		}
+		return "", fmt.Errorf("unknown assignment type %q", assignment.Type)
+	}
+	assignment.Type = assignmentType
+
+	assignment.Title = row["title"]
+	if assignment.Title == "" {
+		return "", fmt.Errorf("title is required")
+	}
+	for column, field := range map[string]*string{
+		"description":  &assignment.Description,
+		"instructions": &assignment.Instructions,
+		"criteria":     &assignment.Criteria,
+		"category":     &assignment.Category,
+		"difficulty":   &assignment.Difficulty,
+		"quarter":      &assignment.Quarter,
+	} {
+		if row[column] != "" {
+			*field = row[column]
+		}
+	}
+
+	if assignment.Points == defaultAssignment().Points {
+		assignment.Points = defaultPointsFor(config, assignment.Type, assignment.Points)
+	}
+	if value := row["points"]; value != "" {
+		points, err := strconv.Atoi(value)
+		if err != nil {
+			return "", fmt.Errorf("points must be a whole number, got %q", value)
+		}
+		assignment.Points = points
+	}
+	if value := row["tags"]; value != "" {
+		assignment.Tags = nil
+		for _, tag := range strings.Split(value, ";") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				assignment.Tags = append(assignment.Tags, tag)
+			}
+		}
+	}
+	if value := row["due_date"]; value != "" {
+		due, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return "", fmt.Errorf("due_date must be YYYY-MM-DD, got %q", value)
+		}
+		assignment.DueDate = &due
+	}
+
+	if value := row["questions"]; value != "" {
+		questions, err := loadRowQuestions(value, baseDir)
+		if err != nil {
+			return "", err
+		}
+		assignment.Questions = questions
+	}
+
+	// Match the wizard: these types are always graded by hand
+	switch assignment.Type {
+	case "writing", "writing-short", "writing-long", "essay", "code-submission":
+		assignment.AutoGrade = false
+	}
+
+	pkg := newAssignmentPackage(cmd, config, assignment, nil)
+	if row["id"] != "" {
+		pkg.Metadata.ID = row["id"]
+	}
+
+	filename, err := outputPath(cmd, config, assignment)
+	if err != nil {
+		return "", err
+	}
+	if force, _ := cmd.Flags().GetBool("force"); !force {
+		if _, err := os.Stat(filename); err == nil {
+			return "", fmt.Errorf("%s already exists (use --force to overwrite)", filename)
+		}
+	}
+	if err := saveNewPackage(pkg, filename); err != nil {
+		return "", fmt.Errorf("failed to save %s: %v", filename, err)
+	}
+	return filename, nil
+}
+
+// loadRowQuestions reads the questions cell of a row: Markdown questions
+// written in the cell itself, or a question file relative to baseDir
+func loadRowQuestions(value, baseDir string) (interface{}, error) {
+	if strings.Contains(value, "\n") {
+		questions, problems, err := parseMarkdownQuestions(strings.NewReader(value))
+		if err != nil {
+			return nil, err
+		}
+		if len(problems) > 0 {
+			return nil, fmt.Errorf("inline questions have %d problem(s): %s", len(problems), strings.Join(problems, "; "))
+		}
+		return questions, nil
+	}
+
+	path := value
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return loadMarkdownQuestions(path)
+	default:
+		return toolkit.LoadQuestions(path)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadRowQuestions(t *testing.T) {
+	dir := t.TempDir()
+	data := "questions:\n  - question: Is 2/4 equal to 1/2?\n    options: [Yes, No]\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "fractions.yaml"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	questions, err := loadRowQuestions("fractions.yaml", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, ok := questions.([]interface{})
+	if !ok || len(list) != 1 {
+		t.Fatalf("got %#v, want one question", questions)
+	}
+	if _, ok := list[0].(map[string]interface{}); !ok {
+		t.Errorf("question is %T, want a JSON-encodable map", list[0])
+	}
+
+	inline, err := loadRowQuestions("## 2+2?\n- [x] 4\n- [ ] 5", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list, ok := inline.([]interface{}); !ok || len(list) != 1 {
+		t.Errorf("inline Markdown gave %#v, want one question", inline)
+	}
+
+	if _, err := loadRowQuestions("missing.md", dir); err == nil {
+		t.Error("expected an error for a missing question file")
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	bundleCmd.Flags().StringP("output", "o", "bundle.zip", "Bundle file to write")
+	bundleCmd.Flags().String("dir", "", "Bundle every assignment in this directory")
+	bundleCmd.MarkFlagFilename("output", "zip")
+	bundleCmd.MarkFlagDirname("dir")
+	unbundleCmd.Flags().String("dir", ".", "Workspace directory to extract into")
+	unbundleCmd.MarkFlagDirname("dir")
+	unbundleCmd.Flags().Bool("force", false, "Overwrite files that already exist")
+	rootCmd.AddCommand(bundleCmd)
+	rootCmd.AddCommand(unbundleCmd)
+}
+
+// Bundle command
+var bundleCmd = &cobra.Command{
+	Use:   "bundle [file...]",
+	Short: "Bundle several assignments into one archive",
+	Long: `Write several assignments and their resource files to one .zip, with a
+manifest listing every assignment, to hand a whole unit to a colleague.
+Resource files used by more than one assignment are stored once.`,
+	ValidArgsFunction: completeAssignmentFiles,
+	RunE:              runBundle,
+}
+
+// Unbundle command
+var unbundleCmd = &cobra.Command{
+	Use:   "unbundle [bundle.zip]",
+	Short: "Extract a bundle or package zip into the workspace",
+	Long: `Extract the assignments in a bundle into the workspace and their files into
+resources/. Existing files are left alone unless --force is given.
+
+A zip made by 'package --zip' is extracted the same way: the assignment is
+saved as <name>.yaml and its files go in resources/.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeZipFile,
+	RunE:              runUnbundle,
+}
+
+func runBundle(cmd *cobra.Command, args []string) error {
+	output, _ := cmd.Flags().GetString("output")
+	dir, _ := cmd.Flags().GetString("dir")
+
+	files := args
+	switch {
+	case dir != "" && len(args) > 0:
+		return usageErrorf("Give assignment files or --dir, not both")
+	case dir != "":
+		found, err := findAssignmentFiles(dir)
+		if err != nil {
+			return failf("Error finding files: %v", err)
+		}
+		if len(found) == 0 {
+			return failf("No assignment files found in %s", dir)
+		}
+		files = found
+	case len(args) == 0:
+		return usageErrorf("Give the assignment files to bundle, or --dir")
+	}
+
+	manifest, err := toolkit.WriteBundle(output, files)
+	if err != nil {
+		return failf("Failed to create bundle: %v", err)
+	}
+
+	printSuccess("Bundled %d assignment(s) and %d resource file(s) into %s",
+		len(manifest.Assignments), len(manifest.Resources), output)
+	for _, entry := range manifest.Assignments {
+		printBullet("%s (%s)", entry.Title, entry.Type)
+	}
+	return nil
+}
+
+func runUnbundle(cmd *cobra.Command, args []string) error {
+	dir, _ := cmd.Flags().GetString("dir")
+	force, _ := cmd.Flags().GetBool("force")
+
+	bundle, err := toolkit.IsBundle(args[0])
+	if err != nil {
+		return failf("Failed to read %s: %v", args[0], err)
+	}
+	if !bundle {
+		return unpackPackageZip(args[0], dir, force)
+	}
+
+	manifest, err := toolkit.ExtractBundle(args[0], dir, force)
+	if err != nil {
+		return failf("Failed to extract %s: %v", args[0], err)
+	}
+
+	printSuccess("Extracted %d assignment(s) from %s", len(manifest.Assignments), args[0])
+	for _, entry := range manifest.Assignments {
+		printBullet("%s (%s)", entry.Title, path.Base(entry.File))
+	}
+	fmt.Println()
+	printHint("Run 'assignment-toolkit validate --all' to check them")
+	return nil
+}
+
+// unpackPackageZip extracts a zip made by 'package --zip' into the workspace
+func unpackPackageZip(archive, dir string, force bool) error {
+	pkg, file, err := toolkit.ExtractPackageZip(archive, dir, toolkit.PackageZipName(archive), force)
+	if err != nil {
+		return failf("Failed to extract %s: %v", archive, err)
+	}
+
+	printSuccess("Extracted %s from %s to %s", pkg.Assignment.Title, archive, file)
+	if len(pkg.Resources) > 0 {
+		printBullet("%d resource(s) in %s", len(pkg.Resources), filepath.Join(dir, "resources"))
+	}
+	fmt.Println()
+	printHint("Run 'assignment-toolkit validate %s' to check it", file)
+	return nil
+}
@@ -2,16 +2,17 @@ package main
 
 import (
 	"bufio"
-	"crypto/sha256"
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"mime"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"assignment-toolkit/pkg/toolkit"
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
@@ -27,6 +28,64 @@ func init() {
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(typesCmd)
+
+	createCmd.Flags().String("author", "", "Author for this assignment (overrides config)")
+	createCmd.Flags().String("license", "", "License for this assignment (overrides config)")
+	createCmd.Flags().String("language", "", "Language code for this assignment (overrides config)")
+	createCmd.Flags().String("id", "", "Use this package ID instead of generating one")
+	createCmd.Flags().Bool("stable-id", false, "Derive the package ID from the assignment type and title so re-created assignments keep their identity")
+
+	createCmd.Flags().Bool("compress", false, "Save the assignment gzip-compressed (.yaml.gz)")
+	createCmd.Flags().String("from-md", "", "Import multiple-choice questions from a Markdown file")
+	createCmd.Flags().String("template", "", "Start from this template (name in config, templates/<name>.yaml, or a path) instead of base_template")
+	createCmd.Flags().String("output-dir", "", outputDirUsage)
+	createCmd.Flags().String("layout", "", layoutUsage)
+	createCmd.Flags().Bool("edit-after", false, "Open the new file in $EDITOR and re-validate it when the editor exits")
+	packageCmd.Flags().Bool("compress", false, "Store the packaged assignment gzip-compressed (assignment.yaml.gz)")
+	packageCmd.Flags().Bool("allow-missing", false, "Package even if resource files are missing, leaving them out")
+	packageCmd.Flags().Bool("zip", false, "Compress the package into <name>-package.zip instead of leaving a directory")
+	packageCmd.Flags().String("readme-template", "", "Render README.md from this text/template file instead of readme_template or the built-in layout")
+
+	listCmd.Flags().Bool("json", false, "Print the listing as a JSON array")
+	listCmd.Flags().String("since", "", "Only list assignments modified within this long (e.g. 24h, 7d), newest first")
+	listCmd.Flags().String("fields", "", "Comma-separated fields to include in --json output (e.g. title,type,version)")
+	listCmd.Flags().Bool("include-archived", false, "Also list archived assignments")
+
+	validateCmd.Flags().Bool("all", false, "Validate every assignment file in the current directory")
+	validateCmd.Flags().String("report", "", "Write an HTML validation report to this file")
+	validateCmd.Flags().Bool("list-rules", false, "List every validation rule with its severity and the types it applies to")
+	validateCmd.Flags().StringSlice("disable", nil, "Skip these validation rules (IDs from --list-rules)")
+	validateCmd.Flags().Bool("fail-fast", false, "With --all, stop at the first invalid file")
+	validateCmd.Flags().Bool("collect-all", false, "With --all, validate every file and report all failures (the default)")
+	validateCmd.Flags().String("assume-type", "", assumeTypeUsage)
+	validateCmd.Flags().Bool("check-urls", false, "Check that resource URLs respond, reporting dead links as errors")
+	validateCmd.Flags().Duration("url-timeout", 10*time.Second, "With --check-urls, give up on a URL after this long")
+	validateCmd.Flags().Int("url-concurrency", toolkit.DefaultURLCheckConcurrency, "With --check-urls, check at most this many URLs at once")
+	validateCmd.Flags().Bool("resources", false, "Check local resource files exist and match their recorded checksums; 'package' records them only in the packaged copy, so for an assignment file only existence is checked")
+	validateCmd.Flags().Bool("fix", false, "Renumber resources with duplicate or gapped orders and tidy learning objectives and prerequisites before validating")
+
+	syncCmd.Flags().Bool("validate-first", true, "Validate the assignment and refuse to sync it if invalid (--validate-first=false to skip)")
+	syncCmd.Flags().Int("min-score", 0, "Refuse to sync assignments whose validation score is below this")
+	syncCmd.Flags().Bool("only-changed-resources", false, "Link resources the LMS already has (same checksum) instead of re-uploading them")
+	syncCmd.Flags().Duration("timeout-per-resource", 0, timeoutPerResourceUsage)
+	syncCmd.Flags().StringSlice("only", nil, "Update just these fields of the LMS assignment (e.g. due_date,points) with a PATCH")
+	syncCmd.Flags().Bool("strict-types", false, strictTypesUsage)
+	syncCmd.Flags().BoolP("yes", "y", false, yesUsage)
+	syncCmd.Flags().String("assume-type", "", assumeTypeUsage)
+	syncCmd.Flags().Bool("changed", false, "Update just the fields that differ from the LMS copy with a PATCH")
+	syncCmd.Flags().Bool("print-curl", false, "Print a curl command reproducing the upload, with the API key redacted, instead of syncing")
+	syncCmd.Flags().Bool("force", false, "Sync even if the LMS already has an assignment with the same source hash")
+	syncCmd.Flags().Bool("dry-run", false, "Print the payload and the files that would be uploaded without contacting the LMS")
+	syncCmd.Flags().String("payload-file", "", "Where --print-curl writes the request body (default: <name>.payload.json in the current directory)")
+	syncCmd.Flags().Bool("resources-only", false, "Retry just the resources that failed to upload in the last sync of this file, using the assignment it created")
+
+	createCmd.RegisterFlagCompletionFunc("template", completeTemplates)
+	createCmd.MarkFlagFilename("from-md", "md", "markdown")
+	createCmd.MarkFlagDirname("output-dir")
+	listCmd.RegisterFlagCompletionFunc("fields", completeListFields)
+	validateCmd.RegisterFlagCompletionFunc("disable", completeRuleIDs)
+	validateCmd.MarkFlagFilename("report", "html")
+	packageCmd.MarkFlagFilename("readme-template")
 }
 
 // Create command
@@ -35,43 +94,58 @@ var createCmd = &cobra.Command{
 	Short: "Create a new assignment interactively",
 	Long: `Create a new assignment using an interactive wizard.
 Supported types: multiple-choice, matching, drag-and-drop, writing, code-submission, speaking, listening`,
-	Args: cobra.MaximumNArgs(1),
-	Run:  runCreate,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeCreateType,
+	RunE:              runCreate,
 }
 
 // Validate command
 var validateCmd = &cobra.Command{
-	Use:   "validate [file]",
-	Short: "Validate an assignment package",
-	Long:  "Validate the structure and content of an assignment package",
-	Args:  cobra.ExactArgs(1),
-	Run:   runValidate,
+	Use:               "validate [file]",
+	Short:             "Validate an assignment package",
+	Long:              "Validate the structure and content of an assignment package",
+	Args:              validateArgs,
+	ValidArgsFunction: completeAssignmentFile,
+	RunE:              runValidate,
 }
 
 // List command
 var listCmd = &cobra.Command{
-	Use:   "list",
+	Use:   "list [git-source]",
 	Short: "List all assignments in the current directory",
-	Long:  "List all assignment packages in the current directory with their metadata",
-	Run:   runList,
+	Long: `List all assignment packages in the current directory with their metadata.
+
+` + gitSourceUsage,
+	Args: gitSourceArgs,
+	RunE: runList,
 }
 
 // Package command
 var packageCmd = &cobra.Command{
-	Use:   "package [assignment-file]",
-	Short: "Package an assignment with its resources",
-	Long:  "Create a distributable package containing the assignment and all its resources",
-	Args:  cobra.ExactArgs(1),
-	Run:   runPackage,
+	Use:               "package [assignment-file]",
+	Short:             "Package an assignment with its resources",
+	Long:              "Create a distributable package containing the assignment and all its resources",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAssignmentFile,
+	RunE:              runPackage,
 }
 
 // Sync command
 var syncCmd = &cobra.Command{
 	Use:   "sync [file]",
 	Short: "Sync assignment with remote LMS",
-	Long:  "Upload assignment to the configured LMS endpoint",
-	Args:  cobra.MaximumNArgs(1),
-	Run:   runSync,
+	Long: `Upload assignment to the configured LMS endpoint.
+
+` + gitSourceUsage + `
+
+When the source is a folder, you choose which of its assignments to sync.
+
+When some resources fail to upload, the sync is reported as partial and
+the failed files are recorded; 'sync --resources-only [file]' uploads just
+those to the assignment already created.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeAssignmentFile,
+	RunE:              runSync,
 }
 
 // Template command
@@ -93,7 +167,7 @@ var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize assignment workspace",
 	Long:  "Initialize the current directory as an assignment workspace with default configuration",
-	Run:   runInit,
+	RunE:  runInit,
 }
 
 // Types command
@@ -101,28 +175,52 @@ var typesCmd = &cobra.Command{
 	Use:   "types",
 	Short: "List all available assignment types",
 	Long:  "Display all supported assignment types with their descriptions and LMS mappings",
-	Run:   runTypes,
+	RunE:  runTypes,
 }
 
 // Implementation functions
 
-func runCreate(cmd *cobra.Command, args []string) {
-	typeManager := GetTypeManager()
-	var assignmentType string
+func runCreate(cmd *cobra.Command, args []string) error {
+	typeManager := toolkit.GetTypeManager()
+	config := getConfig()
 
+	// An explicit --template wins over the workspace base_template
+	seed := defaultAssignment()
+	inputType := ""
+	if name := flagOrDefault(cmd, "template", config.BaseTemplate); name != "" {
+		template, err := loadTemplate(name, config)
+		if err != nil {
+			return failf("Failed to load template: %v", err)
+		}
+		seed = template.Template
+		inputType = template.Type
+		// Template metadata sits between the config and the command-line flags
+		if license := template.Metadata["license"]; license != "" {
+			config.License = license
+		}
+		if language := template.Metadata["language"]; language != "" {
+			config.Language = language
+		}
+		printMessage(iconNote, "Using template %s", name)
+	}
 	if len(args) > 0 {
-		inputType := args[0]
+		inputType = args[0]
+	}
+
+	var assignmentType string
+	if inputType != "" {
 		if !typeManager.ValidatePortableType(inputType) {
 			suggestions := typeManager.GetSuggestedTypes(inputType)
-			fmt.Printf("❌ Unknown assignment type: %s\n", inputType)
+			printError("Unknown assignment type: %s", inputType)
 			if len(suggestions) > 0 {
-				fmt.Printf("📝 Did you mean one of these?\n")
+				printMessage(iconNote, "Did you mean one of these?")
 				for _, suggestion := range suggestions {
-					fmt.Printf("  • %s - %s\n", suggestion, typeManager.GetTypeDescription(suggestion))
+					printBullet("%s - %s", suggestion, typeManager.GetTypeDescription(suggestion))
 				}
 			}
-			fmt.Printf("\n💡 Use 'assignment-toolkit types' to see all available types\n")
-			return
+			fmt.Println()
+			printHint("Use 'assignment-toolkit types' to see all available types")
+			return errFailed
 		}
 		assignmentType = inputType
 	} else {
@@ -141,107 +239,478 @@ func runCreate(cmd *cobra.Command, args []string) {
 	// Resolve to LMS format for validation
 	lmsType, lmsSubtype, err := typeManager.ConvertToLMSFormat(assignmentType)
 	if err != nil {
-		fmt.Printf("❌ Error resolving assignment type: %v\n", err)
-		return
+		return failf("Error resolving assignment type: %v", err)
+	}
+
+	// Import questions before the wizard so parse errors stop early
+	var imported interface{}
+	if mdFile, _ := cmd.Flags().GetString("from-md"); mdFile != "" {
+		if resolveQuestionType(assignmentType) != "multiple-choice" {
+			return failf("--from-md only supports multiple-choice assignments")
+		}
+		questions, err := loadMarkdownQuestions(mdFile)
+		if err != nil {
+			return failf("%v", err)
+		}
+		printMessage(iconNote, "Imported %d question(s) from %s", len(questions), mdFile)
+		imported = questions
 	}
 
 	fmt.Printf("Creating new %s assignment...\n", assignmentType)
 	if lmsType != assignmentType {
-		fmt.Printf("📋 Will be imported to LMS as: %s", lmsType)
+		lmsInfo := lmsType
 		if lmsSubtype != "" {
-			fmt.Printf(" (%s)", lmsSubtype)
+			lmsInfo += fmt.Sprintf(" (%s)", lmsSubtype)
 		}
-		fmt.Println()
+		printMessage(iconInfo, "Will be imported to LMS as: %s (recorded in metadata.type_mapping)", lmsInfo)
 	}
 	fmt.Println()
 
+	// Propose the configured points for this type unless a template
+	// already chose its own
+	if seed.Points == defaultAssignment().Points {
+		seed.Points = defaultPointsFor(config, assignmentType, seed.Points)
+	}
+
 	// Create assignment through interactive wizard
-	assignment := createAssignmentWizard(assignmentType)
+	assignment := createAssignmentWizard(assignmentType, seed, imported)
+	resources := promptResources(config.ResourcePublic)
+
+	pkg := newAssignmentPackage(cmd, config, assignment, resources)
+	filename, err := outputPath(cmd, config, assignment)
+	if err != nil {
+		return failf("%v", err)
+	}
+	if err := saveNewPackage(pkg, filename); err != nil {
+		return failf("Failed to save assignment: %v", err)
+	}
+
+	printSuccess("Assignment created successfully: %s", filename)
 
-	// Generate package
-	pkg := AssignmentPackage{
-		Metadata: PackageMetadata{
-			ID:       uuid.New().String(),
+	if editAfterCreate(cmd, config) {
+		return editAndRevalidate(filename)
+	}
+	printCreatedValidation(filename)
+	return nil
+}
+
+// newAssignmentPackage wraps a new assignment in package metadata from the
+// flags and config, with a fresh source hash
+func newAssignmentPackage(cmd *cobra.Command, config toolkit.Config, assignment toolkit.Assignment, resources []toolkit.Resource) toolkit.AssignmentPackage {
+	now := toolkit.Now()
+	pkg := toolkit.AssignmentPackage{
+		Metadata: toolkit.PackageMetadata{
+			ID:       packageIDFor(cmd, config, assignment),
 			Version:  "1.0.0",
-			Created:  time.Now(),
-			Modified: time.Now(),
-			Author:   getConfig().Author,
-			License:  getConfig().License,
-			Language: getConfig().Language,
+			Created:  now,
+			Modified: now,
+			Author:   flagOrDefault(cmd, "author", config.Author),
+			License:  flagOrDefault(cmd, "license", config.License),
+			Language: flagOrDefault(cmd, "language", config.Language),
 		},
 		Assignment: assignment,
+		Resources:  resources,
 	}
+	pkg.Metadata.TypeMapping, _ = toolkit.ResolveTypeMapping(assignment.Type)
+	pkg.Metadata.SourceHash = toolkit.CalculateHash(pkg)
+	return pkg
+}
 
-	// Calculate source hash
-	pkg.Metadata.SourceHash = calculateHash(pkg)
-
-	// Save to file
+// createdFilename names the file for a new assignment after its title
+func createdFilename(cmd *cobra.Command, assignment toolkit.Assignment) string {
 	filename := strings.ReplaceAll(strings.ToLower(assignment.Title), " ", "-") + ".yaml"
-	saveAssignmentPackage(pkg, filename)
+	if compress, _ := cmd.Flags().GetBool("compress"); compress {
+		filename += ".gz"
+	}
+	return filename
+}
+
+// printCreatedValidation validates a newly created file and reports its
+// score and anything still missing. The file is kept either way, so
+// problems are reported without failing the command.
+func printCreatedValidation(filename string) {
+	pkg, err := toolkit.LoadPackage(filename)
+	if err != nil {
+		printWarning("Could not re-load %s to validate it: %v", filename, err)
+		return
+	}
+
+	validation := toolkit.ValidateAssignmentPackage(pkg)
+	if validation.IsValid {
+		printMessage(iconInfo, "Validation: valid (Score: %d/100)", validation.Score)
+	} else {
+		printWarning("Validation: not yet valid (Score: %d/100)", validation.Score)
+		for _, err := range validation.Errors {
+			printBullet("%s", err)
+		}
+	}
+	for _, warning := range validation.Warnings {
+		printBullet("%s", warning)
+	}
+	if !validation.IsValid || len(validation.Warnings) > 0 {
+		printHint("Edit %s, then run 'assignment-toolkit validate %s'", filename, filename)
+	}
+}
+
+// missingResourceFiles returns the local resource files and question media
+// of pkg that don't exist
+func missingResourceFiles(pkg toolkit.AssignmentPackage) []string {
+	var files []string
+	for _, resource := range pkg.Resources {
+		if resource.LocalPath != "" {
+			files = append(files, resource.LocalPath)
+		}
+	}
+	files = append(files, toolkit.QuestionMediaFiles(pkg)...)
+
+	var missing []string
+	for _, file := range files {
+		if _, err := os.Stat(file); err != nil {
+			missing = append(missing, file)
+		}
+	}
+	return missing
+}
 
-	fmt.Printf("✅ Assignment created successfully: %s\n", filename)
+// fileOrAllArgs requires a file unless --all is given
+func fileOrAllArgs(cmd *cobra.Command, args []string) error {
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		return cobra.NoArgs(cmd, args)
+	}
+	return cobra.ExactArgs(1)(cmd, args)
 }
 
-func runValidate(cmd *cobra.Command, args []string) {
+// urlChecker checks resource URLs during validation when --check-urls is
+// given
+var urlChecker *toolkit.URLChecker
+
+// checkResources verifies local resource files during validation when
+// --resources is given
+var checkResources bool
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	if listRules, _ := cmd.Flags().GetBool("list-rules"); listRules {
+		return runListRules()
+	}
+	if err := disableRules(cmd); err != nil {
+		return err
+	}
+	if checkURLs, _ := cmd.Flags().GetBool("check-urls"); checkURLs {
+		timeout, _ := cmd.Flags().GetDuration("url-timeout")
+		concurrency, _ := cmd.Flags().GetInt("url-concurrency")
+		urlChecker = toolkit.NewURLChecker(timeout, concurrency)
+		if verboseHTTP {
+			urlChecker.EnableHTTPLog(os.Stderr, maxBodyLog)
+		}
+	}
+
+	checkResources, _ = cmd.Flags().GetBool("resources")
+
+	reportPath, _ := cmd.Flags().GetString("report")
+	fix, _ := cmd.Flags().GetBool("fix")
+	assumed, err := assumedType(cmd)
+	if err != nil {
+		return err
+	}
+	failFast, _ := cmd.Flags().GetBool("fail-fast")
+	collectAll, _ := cmd.Flags().GetBool("collect-all")
+	if failFast && collectAll {
+		return usageErrorf("--fail-fast and --collect-all can't be used together")
+	}
+	if len(args) == 1 && isGitSource(args[0]) {
+		return runValidateGitSource(args[0], reportPath, fix, failFast, assumed)
+	}
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		return runValidateAll(reportPath, fix, failFast, assumed)
+	} else if failFast || collectAll {
+		return usageErrorf("--fail-fast and --collect-all require --all")
+	}
+
 	filename := args[0]
+	if isPackageDirOrZip(filename) {
+		if fix {
+			return usageErrorf("--fix can't change a package; fix the assignment and package it again")
+		}
+		return runValidatePackage(filename, reportPath, assumed)
+	}
+	if fix {
+		fixAssignment(filename)
+	}
 
-	pkg, err := loadAssignmentPackage(filename)
+	pkg, err := toolkit.LoadPackage(filename)
 	if err != nil {
-		fmt.Printf("❌ Failed to load assignment: %v\n", err)
-		return
+		return failf("Failed to load assignment: %v", err)
+	}
+	return reportValidation(filename, pkg, reportPath, assumed)
+}
+
+// runValidatePackage validates a directory made by 'package', or a zip of
+// one, with its resource paths pointed at the packaged copies
+func runValidatePackage(path, reportPath, assumed string) error {
+	pkg, cleanup, err := loadPackageDirOrZip(path)
+	if err != nil {
+		return failf("Failed to load package: %v", err)
 	}
+	defer cleanup()
+	return reportValidation(path, pkg, reportPath, assumed)
+}
 
-	validation := validateAssignmentPackage(pkg)
+// reportValidation validates pkg, loaded from filename, and prints the
+// result, writing it to reportPath as well when that is set
+func reportValidation(filename string, pkg toolkit.AssignmentPackage, reportPath, assumed string) error {
+	if assumeType(&pkg, assumed) {
+		printMessage(iconNote, "%s has no type; validating it as %s", filename, assumed)
+	}
+
+	validation := toolkit.ValidateAssignmentPackage(pkg)
+	if urlChecker != nil {
+		urlChecker.CheckPackage(pkg, &validation)
+	}
+	if checkResources {
+		toolkit.CheckResourceFiles(pkg, &validation)
+	}
 
 	if validation.IsValid {
-		fmt.Printf("✅ Assignment is valid (Score: %d/100)\n", validation.Score)
+		printSuccess("Assignment is valid (Score: %d/100)", validation.Score)
 	} else {
-		fmt.Printf("❌ Assignment validation failed\n")
+		printError("Assignment validation failed")
 		for _, err := range validation.Errors {
-			fmt.Printf("  • %s\n", err)
+			printBullet("%s", err)
+		}
+		if pkg.Assignment.Type == "" {
+			printHint("Validate files with no type using --assume-type <type>, then fix them with 'assignment-toolkit normalize-types --assume-type <type>'")
 		}
 	}
 
 	if len(validation.Warnings) > 0 {
-		fmt.Println("\n⚠️  Warnings:")
+		fmt.Println()
+		printWarning("Warnings:")
 		for _, warning := range validation.Warnings {
-			fmt.Printf("  • %s\n", warning)
+			printBullet("%s", warning)
 		}
 	}
+
+	if reportPath != "" {
+		entry := validationReportEntry{
+			File:       filename,
+			Title:      pkg.Assignment.Title,
+			Type:       pkg.Assignment.Type,
+			Validation: validation,
+		}
+		if err := writeValidationReport(reportPath, []validationReportEntry{entry}); err != nil {
+			return failf("Failed to write report: %v", err)
+		}
+		printSuccess("Report written to %s", reportPath)
+	}
+
+	if !validation.IsValid {
+		return errFailed
+	}
+	return nil
 }
 
-func runList(cmd *cobra.Command, args []string) {
-	files, err := filepath.Glob("*.yaml")
+// runValidateAll validates every workspace assignment. With failFast it
+// stops at the first file that fails to load or is invalid; files with no
+// type are validated as assumed when it is set.
+func runValidateAll(reportPath string, fix, failFast bool, assumed string) error {
+	files, err := findWorkspaceAssignmentFiles()
 	if err != nil {
-		fmt.Printf("Error listing files: %v\n", err)
-		return
+		return failf("Error listing files: %v", err)
+	}
+	if len(files) == 0 {
+		fmt.Println("No assignment files found in current directory.")
+		return nil
 	}
 
-	yamlFiles, err := filepath.Glob("*.yml")
-	if err == nil {
-		files = append(files, yamlFiles...)
+	if fix {
+		for _, file := range files {
+			fixAssignment(file)
+		}
 	}
+	return validateFileList(files, "", reportPath, failFast, assumed)
+}
 
+// runValidateGitSource validates the assignments of a git+ source
+func runValidateGitSource(source, reportPath string, fix, failFast bool, assumed string) error {
+	if fix {
+		return usageErrorf("--fix can't change a git source; clone the repository and fix the files there")
+	}
+
+	dir, target, cleanup, err := cloneGitSource(source)
+	if err != nil {
+		return failf("Failed to read the repository: %v", err)
+	}
+	defer cleanup()
+
+	files, err := gitSourceFiles(target)
+	if err != nil {
+		return failf("Error listing files: %v", err)
+	}
 	if len(files) == 0 {
-		fmt.Println("No assignment files found in current directory.")
+		fmt.Println("No assignment files found in the repository.")
+		return nil
+	}
+	return validateFileList(files, dir, reportPath, failFast, assumed)
+}
+
+// validateFileList validates files and prints a line for each, showing
+// them relative to root when it is set
+func validateFileList(files []string, root, reportPath string, failFast bool, assumed string) error {
+	var entries []validationReportEntry
+	invalid := 0
+	for _, file := range files {
+		entry := validateFile(file, assumed)
+		entry.File = relativeTo(root, file)
+		entries = append(entries, entry)
+		switch {
+		case entry.LoadError != "":
+			printError("%s: failed to load: %s", entry.File, entry.LoadError)
+			invalid++
+		case !entry.Validation.IsValid:
+			printError("%s: invalid (Score: %d/100, %d error(s))", entry.File, entry.Validation.Score, len(entry.Validation.Errors))
+			invalid++
+		case len(entry.Validation.Warnings) > 0:
+			printWarning("%s: valid with %d warning(s) (Score: %d/100)", entry.File, len(entry.Validation.Warnings), entry.Validation.Score)
+		default:
+			printSuccess("%s: valid (Score: %d/100)", entry.File, entry.Validation.Score)
+		}
+		if entry.AssumedType {
+			printMessage(iconNote, "%s has no type; validated as %s", entry.File, assumed)
+		}
+		if failFast && invalid > 0 {
+			break
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("Validated %d assignment(s): %d valid, %d invalid\n", len(entries), len(entries)-invalid, invalid)
+	if skipped := len(files) - len(entries); skipped > 0 {
+		printMessage(iconInfo, "Stopped at the first invalid file (--fail-fast); %d file(s) not checked", skipped)
+	}
+
+	if reportPath != "" {
+		if err := writeValidationReport(reportPath, entries); err != nil {
+			return failf("Failed to write report: %v", err)
+		}
+		printSuccess("Report written to %s", reportPath)
+	}
+
+	if invalid > 0 {
+		return errFailed
+	}
+	return nil
+}
+
+// fixAssignment renumbers a file's resources contiguously, cleans its
+// learning objectives and prerequisites, and saves it when anything changed
+func fixAssignment(filename string) {
+	pkg, err := toolkit.LoadPackage(filename)
+	if err != nil {
+		return // reported by validation
+	}
+
+	var fixes []string
+	if toolkit.RenumberResources(&pkg) {
+		fixes = append(fixes, "renumbered resources")
+	}
+	if toolkit.CleanEducationalMetadata(&pkg) {
+		fixes = append(fixes, "cleaned learning objectives and prerequisites")
+	}
+	if toolkit.NormalizeLineEndings(&pkg) {
+		fixes = append(fixes, "normalized line endings")
+	}
+	if toolkit.MigrateCodeSubmissionConfig(&pkg) {
+		fixes = append(fixes, "moved code_submission_config out of questions")
+	}
+	if len(fixes) == 0 {
+		return
+	}
+
+	pkg.Metadata.Modified = toolkit.Now()
+	if err := toolkit.SavePackage(pkg, filename); err != nil {
+		printError("Failed to save %s: %v", filename, err)
 		return
 	}
+	printMessage(iconSettings, "%s: %s", filename, strings.Join(fixes, ", "))
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	asJSON, _ := cmd.Flags().GetBool("json")
+	fieldList, _ := cmd.Flags().GetString("fields")
+	if fieldList != "" && !asJSON {
+		return usageErrorf("--fields requires --json")
+	}
+
+	since, _ := cmd.Flags().GetString("since")
+	var window time.Duration
+	if since != "" {
+		var err error
+		if window, err = parseSince(since); err != nil {
+			return usageErrorf("Invalid --since: %v", err)
+		}
+	}
+
+	var root string
+	var files []string
+	var err error
+	if len(args) == 1 {
+		var target string
+		var cleanup func()
+		root, target, cleanup, err = cloneGitSource(args[0])
+		if err != nil {
+			return failf("Failed to read the repository: %v", err)
+		}
+		defer cleanup()
+		files, err = gitSourceFiles(target)
+	} else {
+		files, err = findWorkspaceAssignmentFiles()
+	}
+	if err != nil {
+		return failf("Error listing files: %v", err)
+	}
+	if since != "" {
+		files = recentlyModified(files, toolkit.Now().Add(-window))
+	}
+	hidden := 0
+	if includeArchived, _ := cmd.Flags().GetBool("include-archived"); !includeArchived {
+		files, hidden = withoutArchived(files)
+	}
+
+	if asJSON {
+		return printListJSON(files, root, fieldList)
+	}
+
+	if len(files) == 0 {
+		switch {
+		case since != "":
+			fmt.Printf("No assignments modified in the last %s.\n", since)
+		case hidden > 0:
+			fmt.Println("No active assignments found.")
+		case root != "":
+			fmt.Println("No assignment files found in the repository.")
+		default:
+			fmt.Println("No assignment files found in current directory.")
+		}
+		printArchivedHidden(hidden)
+		return nil
+	}
 
 	fmt.Printf("Found %d assignment(s):\n\n", len(files))
 	fmt.Printf("%-30s %-15s %-10s %-20s\n", "TITLE", "TYPE", "VERSION", "MODIFIED")
 	fmt.Println(strings.Repeat("-", 75))
 
 	for _, file := range files {
-		pkg, err := loadAssignmentPackage(file)
+		pkg, err := toolkit.LoadPackage(file)
 		if err != nil {
-			fmt.Printf("%-30s %-15s %-10s %-20s\n", file, "ERROR", "-", "-")
+			fmt.Printf("%-30s %-15s %-10s %-20s\n", relativeTo(root, file), "ERROR", "-", "-")
 			continue
 		}
 
 		title := pkg.Assignment.Title
-		if len(title) > 28 {
-			title = title[:28] + "..."
+		if pkg.Metadata.Archived {
+			title = "(archived) " + title
 		}
+		title = fitColumn(title, 30)
 
 		fmt.Printf("%-30s %-15s %-10s %-20s\n",
 			title,
@@ -250,114 +719,376 @@ func runList(cmd *cobra.Command, args []string) {
 			pkg.Metadata.Modified.Format("2006-01-02 15:04"),
 		)
 	}
+	printArchivedHidden(hidden)
+	return nil
 }
 
-func runPackage(cmd *cobra.Command, args []string) {
+// printArchivedHidden notes how many archived assignments list left out
+func printArchivedHidden(hidden int) {
+	if hidden > 0 {
+		fmt.Println()
+		printHint("%d archived assignment(s) hidden; pass --include-archived to show them", hidden)
+	}
+}
+
+func runPackage(cmd *cobra.Command, args []string) error {
 	filename := args[0]
 
-	pkg, err := loadAssignmentPackage(filename)
+	pkg, err := toolkit.LoadPackage(filename)
 	if err != nil {
-		fmt.Printf("❌ Failed to load assignment: %v\n", err)
-		return
+		return failf("Failed to load assignment: %v", err)
+	}
+
+	// Record duration and codec of attached audio for the README and the
+	// LMS. Missing files are reported below.
+	for file, err := range toolkit.HydrateAudioMetadata(&pkg) {
+		if !os.IsNotExist(err) {
+			printWarning("Could not read audio metadata from %s: %v", file, err)
+		}
+	}
+
+	// Render the README first so a broken template doesn't leave a
+	// half-built package behind
+	readmeTemplate := flagOrDefault(cmd, "readme-template", getConfig().ReadmeTemplate)
+	readme, err := renderReadme(pkg, readmeTemplate)
+	if err != nil {
+		return failf("Failed to render README from %s: %v", readmeTemplate, err)
+	}
+
+	// Check the media exists before building anything
+	allowMissing, _ := cmd.Flags().GetBool("allow-missing")
+	missing := missingResourceFiles(pkg)
+	if len(missing) > 0 {
+		if !allowMissing {
+			printError("Resource files are missing; not packaging")
+			for _, file := range missing {
+				printBullet("%s", file)
+			}
+			printHint("Restore the files, fix their paths in %s, or pass --allow-missing to package without them", filename)
+			return errFailed
+		}
+		printWarning("Packaging without %d missing resource file(s):", len(missing))
+		for _, file := range missing {
+			printBullet("%s", file)
+		}
+	}
+
+	// Record the checksum, size and type of each file being packaged so
+	// 'validate --resources' and sync-package can spot a changed copy
+	for i, resource := range pkg.Resources {
+		if resource.LocalPath == "" || containsString(missing, resource.LocalPath) {
+			continue
+		}
+		if err := toolkit.DescribeResourceFile(&pkg.Resources[i]); err != nil {
+			return failf("Failed to read %s: %v", resource.LocalPath, err)
+		}
 	}
 
 	// Create package directory
-	packageName := strings.TrimSuffix(filename, filepath.Ext(filename))
+	packageName := assignmentBaseName(filename)
 	packageDir := packageName + "-package"
 
 	os.RemoveAll(packageDir) // Clean up if exists
-	os.MkdirAll(packageDir, 0755)
+	if err := os.MkdirAll(packageDir, 0755); err != nil {
+		return failf("Failed to create %s: %v", packageDir, err)
+	}
 
 	// Copy assignment file
-	saveAssignmentPackage(pkg, filepath.Join(packageDir, "assignment.yaml"))
+	assignmentFile := "assignment.yaml"
+	if compress, _ := cmd.Flags().GetBool("compress"); compress {
+		assignmentFile += ".gz"
+	}
+	if err := toolkit.SavePackage(pkg, filepath.Join(packageDir, assignmentFile)); err != nil {
+		return failf("Failed to write %s: %v", assignmentFile, err)
+	}
 
-	// Copy resources
-	if len(pkg.Resources) > 0 {
+	// Copy resources, including images and audio attached to options and
+	// matching items
+	questionMedia := toolkit.QuestionMediaFiles(pkg)
+	if len(pkg.Resources) > 0 || len(questionMedia) > 0 {
 		resourceDir := filepath.Join(packageDir, "resources")
-		os.MkdirAll(resourceDir, 0755)
+		if err := os.MkdirAll(resourceDir, 0755); err != nil {
+			return failf("Failed to create %s: %v", resourceDir, err)
+		}
 
+		files := append([]string{}, questionMedia...)
 		for _, resource := range pkg.Resources {
 			if resource.LocalPath != "" {
-				// Copy local file
-				copyFile(resource.LocalPath, filepath.Join(resourceDir, filepath.Base(resource.LocalPath)))
+				files = append(files, resource.LocalPath)
+			}
+		}
+		for _, file := range files {
+			if containsString(missing, file) {
+				continue
+			}
+			if err := copyFile(file, filepath.Join(resourceDir, filepath.Base(file))); err != nil {
+				return failf("Failed to copy %s: %v", file, err)
 			}
 		}
 	}
 
-	// Create README
-	readme := fmt.Sprintf(`# %s
-
-%s
-
-## Assignment Details
-- **Type**: %s
-- **Version**: %s
-- **Author**: %s
-- **Created**: %s
-
-## Installation
-1. Import assignment.yaml into your LMS
-2. Upload resources from the resources/ directory if present
-
-## Resources
-`, pkg.Assignment.Title, pkg.Assignment.Description, pkg.Assignment.Type,
-		pkg.Metadata.Version, pkg.Metadata.Author, pkg.Metadata.Created.Format("2006-01-02"))
+	// Write README
+	if err := ioutil.WriteFile(filepath.Join(packageDir, "README.md"), readme, 0644); err != nil {
+		return failf("Failed to write README.md: %v", err)
+	}
 
+	if zipPackage, _ := cmd.Flags().GetBool("zip"); zipPackage {
+		archive := packageDir + ".zip"
+		if err := toolkit.ZipPackageDir(packageDir, archive); err != nil {
+			return failf("Failed to write %s: %v", archive, err)
+		}
+		os.RemoveAll(packageDir)
+		printSuccess("Package created: %s", archive)
+	} else {
+		printSuccess("Package created: %s/", packageDir)
+	}
 	for _, resource := range pkg.Resources {
-		readme += fmt.Sprintf("- %s (%s)\n", resource.Title, resource.Type)
+		if summary := toolkit.AudioSummary(resource); summary != "" {
+			printBullet("%s: %s", resource.Title, summary)
+		}
 	}
-
-	ioutil.WriteFile(filepath.Join(packageDir, "README.md"), []byte(readme), 0644)
-
-	fmt.Printf("✅ Package created: %s/\n", packageDir)
+	return nil
 }
 
-func runSync(cmd *cobra.Command, args []string) {
+func runSync(cmd *cobra.Command, args []string) error {
 	config := getConfig()
+	applyStrictTypesFlag(cmd, &config)
 	if config.LMSEndpoint == "" {
-		fmt.Println("❌ LMS endpoint not configured. Run 'assignment-toolkit config set lms-endpoint <url>'")
-		return
+		return failf("LMS endpoint not configured. Run 'assignment-toolkit config set lms-endpoint <url>'")
+	}
+	if resourcesOnly, _ := cmd.Flags().GetBool("resources-only"); resourcesOnly {
+		return runResourcesOnlySync(cmd, config, args)
+	}
+	assumed, err := assumedType(cmd)
+	if err != nil {
+		return err
 	}
 
-	var filename string
-	if len(args) > 0 {
+	var filename, shown string
+	if len(args) > 0 && isGitSource(args[0]) {
+		dir, target, cleanup, err := cloneGitSource(args[0])
+		if err != nil {
+			return failf("Failed to read the repository: %v", err)
+		}
+		defer cleanup()
+
+		files, err := gitSourceFiles(target)
+		if err != nil {
+			return failf("Error listing files: %v", err)
+		}
+		switch len(files) {
+		case 0:
+			return failf("No assignment files found in the repository")
+		case 1:
+			filename = files[0]
+		default:
+			names := make([]string, len(files))
+			for i, file := range files {
+				names[i] = relativeTo(dir, file)
+			}
+			filename = filepath.Join(dir, filepath.FromSlash(promptSelect("Select assignment to sync:", names)))
+		}
+		shown = relativeTo(dir, filename)
+	} else if len(args) > 0 {
 		filename = args[0]
 	} else {
 		// List available assignments
-		files, _ := filepath.Glob("*.yaml")
-		yamlFiles, _ := filepath.Glob("*.yml")
-		files = append(files, yamlFiles...)
+		files, _ := findWorkspaceAssignmentFiles()
 
 		if len(files) == 0 {
-			fmt.Println("❌ No assignment files found")
-			return
+			return failf("No assignment files found")
 		}
 
 		filename = promptSelect("Select assignment to sync:", files)
 	}
 
-	fmt.Printf("🔄 Syncing %s with %s...\n", filename, config.LMSEndpoint)
+	if shown == "" {
+		shown = filename
+	}
+	printMessage(iconSync, "Syncing %s with %s...", shown, config.LMSEndpoint)
 
 	// Load assignment
-	_, err := loadAssignmentPackage(filename)
+	pkg, err := toolkit.LoadPackage(filename)
+	if err != nil {
+		return failf("Failed to load assignment: %v", err)
+	}
+	if pkg.Metadata.Archived {
+		printError("%s is archived; not syncing", shown)
+		printHint("Run 'assignment-toolkit unarchive %s' to sync it again", filename)
+		return errFailed
+	}
+	if assumeType(&pkg, assumed) {
+		printMessage(iconNote, "%s has no type; syncing it as %s", shown, assumed)
+	}
+
+	if validateFirst, _ := cmd.Flags().GetBool("validate-first"); validateFirst {
+		minScore, _ := cmd.Flags().GetInt("min-score")
+		if !syncValidationPassed(pkg, minScore) {
+			return errFailed
+		}
+	}
+
+	if printCurl, _ := cmd.Flags().GetBool("print-curl"); printCurl {
+		payloadFile, _ := cmd.Flags().GetString("payload-file")
+		return runPrintCurl(config, pkg, filename, payloadFile)
+	}
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		return runDrySync(config, pkg)
+	}
+
+	if err := confirmSync(cmd, config); err != nil {
+		return err
+	}
+
+	only, _ := cmd.Flags().GetStringSlice("only")
+	if changed, _ := cmd.Flags().GetBool("changed"); changed || len(only) > 0 {
+		return runPatchSync(config, pkg, only, changed)
+	}
+
+	if config.StrictTypes {
+		if err := toolkit.CheckLMSType(pkg.Assignment.Type); err != nil {
+			return failf("Not syncing: %v", err)
+		}
+	}
+	reportTypeMapping(pkg)
+	warnMediaResources(&pkg)
+
+	client, err := newLMSClientFromConfig(config)
+	if err != nil {
+		return failf("Invalid sync configuration: %v", err)
+	}
+	client.OnlyChangedResources, _ = cmd.Flags().GetBool("only-changed-resources")
+	client.ResourceTimeout, _ = cmd.Flags().GetDuration("timeout-per-resource")
+
+	if err := client.TestConnection(); err != nil {
+		return networkErrorf("LMS is not reachable: %v", err)
+	}
+
+	if force, _ := cmd.Flags().GetBool("force"); !force {
+		existing, err := client.ExistingAssignment(pkg)
+		if err != nil {
+			printError("Could not check the LMS for an existing copy: %v", err)
+			printHint("Pass --force to sync without checking")
+			return errNetwork
+		}
+		if existing != nil {
+			printMessage(iconNote, "Already on the LMS with the same content; not syncing")
+			fmt.Printf("   Existing assignment ID: %s\n", existing.AssignmentID)
+			if shown == filename && toolkit.LMSAssignmentID(pkg) == "" {
+				recordLMSID(filename, existing.AssignmentID)
+			}
+			printHint("Pass --force to sync it again anyway")
+			return nil
+		}
+		if pkg.Metadata.SourceHash != toolkit.CalculateHash(pkg) {
+			printMessage(iconNote, "The source hash is out of date, so the LMS wasn't checked for an existing copy; run 'assignment-toolkit reindex %s' to update it", shown)
+		}
+	}
+
+	result, err := client.SyncAssignment(pkg)
+	if err != nil {
+		if result != nil && len(result.Conflicts) > 0 {
+			printError("Sync rejected: the assignment conflicts with existing LMS content")
+			printConflicts(result)
+			if result.AssignmentID != "" {
+				fmt.Printf("   Existing assignment ID: %s\n", result.AssignmentID)
+			}
+			return errFailed
+		}
+		return networkErrorf("Sync failed: %v", err)
+	}
+
+	if shown == filename && result.AssignmentID != "" {
+		recordLMSID(filename, result.AssignmentID)
+	}
+	if result.Status == "partial" {
+		printWarning("%s", result.Message)
+		if shown == filename {
+			recordFailedResources(filename, result)
+		}
+	} else {
+		printSuccess("Assignment synced successfully!")
+	}
+	fmt.Printf("   Assignment ID: %s\n", result.AssignmentID)
+	fmt.Printf("   Status: %s\n", result.Status)
+	if result.Message != "" && result.Status != "partial" {
+		fmt.Printf("   Message: %s\n", result.Message)
+	}
+	if len(result.ResourceIDs) > 0 {
+		fmt.Printf("   Resources uploaded: %d\n", len(result.ResourceIDs))
+	}
+	if len(result.Conflicts) > 0 {
+		printWarning("The LMS reported conflicts:")
+		printConflicts(result)
+	}
+	return nil
+}
+
+// describeTypeMapping formats a mapping as "essay → writing-long (subtype)"
+func describeTypeMapping(mapping toolkit.ResolvedType) string {
+	description := fmt.Sprintf("%s %s %s", mapping.Portable, icon(iconArrow), mapping.LMSType)
+	if mapping.LMSSubtype != "" {
+		description += fmt.Sprintf(" (%s)", mapping.LMSSubtype)
+	}
+	return description
+}
+
+// reportTypeMapping shows the LMS type an assignment is synced as and warns
+// when it differs from the mapping recorded when the file was created.
+// Unknown types are left to validation and --strict-types.
+func reportTypeMapping(pkg toolkit.AssignmentPackage) {
+	current, err := toolkit.ResolveTypeMapping(pkg.Assignment.Type)
 	if err != nil {
-		fmt.Printf("❌ Failed to load assignment: %v\n", err)
 		return
 	}
+	printMessage(iconInfo, "Type: %s", describeTypeMapping(*current))
+	if recorded := pkg.Metadata.TypeMapping; recorded != nil && *recorded != *current {
+		printWarning("This differs from the mapping recorded at creation (%s); the type or the workspace mappings have changed", describeTypeMapping(*recorded))
+		printHint("Run 'assignment-toolkit reindex' to record the current mapping if the change is intended")
+	}
+}
+
+// warnMediaResources warns about audio and video files the LMS is likely
+// to reject, before time is spent uploading them. Audio is probed first so
+// the check sees the real format, not just the extension.
+func warnMediaResources(pkg *toolkit.AssignmentPackage) {
+	toolkit.HydrateAudioMetadata(pkg)
+	for _, warning := range toolkit.CheckMediaResources(*pkg) {
+		printWarning("%s", warning)
+	}
+}
 
-	// TODO: Implement actual sync with LMS API
-	// For now, just simulate
-	time.Sleep(2 * time.Second)
+// syncValidationPassed validates pkg before upload, printing the reasons
+// when it is invalid or scores below minScore
+func syncValidationPassed(pkg toolkit.AssignmentPackage, minScore int) bool {
+	validation := toolkit.ValidateAssignmentPackage(pkg)
 
-	fmt.Printf("✅ Assignment synced successfully!\n")
-	fmt.Printf("   Assignment ID: %s\n", uuid.New().String())
+	if !validation.IsValid {
+		printError("Assignment is invalid; not syncing")
+		for _, err := range validation.Errors {
+			printBullet("%s", err)
+		}
+		printHint("Fix the errors or pass --validate-first=false to sync anyway")
+		return false
+	}
+
+	if validation.Score < minScore {
+		printError("Assignment score %d/100 is below the minimum of %d; not syncing", validation.Score, minScore)
+		for _, warning := range validation.Warnings {
+			printBullet("%s", warning)
+		}
+		return false
+	}
+
+	return true
 }
 
-func runInit(cmd *cobra.Command, args []string) {
-	fmt.Println("🚀 Initializing assignment workspace...")
+func runInit(cmd *cobra.Command, args []string) error {
+	printMessage(iconStart, "Initializing assignment workspace...")
 
 	// Create config file
-	config := Config{
+	config := toolkit.Config{
 		Author:   promptString("Author name:", ""),
 		Email:    promptString("Email:", ""),
 		License:  "CC-BY-SA-4.0",
@@ -368,23 +1099,29 @@ func runInit(cmd *cobra.Command, args []string) {
 			"published":  "true",
 			"quarter":    "Q1",
 		},
+		OutputDir:    "packages",
+		OutputLayout: "{quarter}/{type}",
 	}
 
 	// Save config
 	configData, _ := yaml.Marshal(config)
-	ioutil.WriteFile(".assignment-config.yaml", configData, 0644)
+	if err := ioutil.WriteFile(".assignment-config.yaml", configData, 0644); err != nil {
+		return failf("Failed to write config: %v", err)
+	}
 
 	// Create directories
-	os.MkdirAll("templates", 0755)
-	os.MkdirAll("resources", 0755)
-	os.MkdirAll("packages", 0755)
+	for _, dir := range []string{"templates", "resources", "packages"} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return failf("Failed to create %s/: %v", dir, err)
+		}
+	}
 
 	// Create sample template
-	sampleTemplate := Template{
+	sampleTemplate := toolkit.Template{
 		Name:        "Multiple Choice Template",
 		Description: "Basic multiple choice question template",
 		Type:        "multiple-choice",
-		Template: Assignment{
+		Template: toolkit.Assignment{
 			Type:         "multiple-choice",
 			Points:       1,
 			AutoGrade:    true,
@@ -395,117 +1132,160 @@ func runInit(cmd *cobra.Command, args []string) {
 	}
 
 	templateData, _ := yaml.Marshal(sampleTemplate)
-	ioutil.WriteFile("templates/multiple-choice.yaml", templateData, 0644)
+	if err := ioutil.WriteFile("templates/multiple-choice.yaml", templateData, 0644); err != nil {
+		return failf("Failed to write sample template: %v", err)
+	}
 
-	fmt.Println("✅ Workspace initialized!")
-	fmt.Println("   📁 Created directories: templates/, resources/, packages/")
-	fmt.Println("   ⚙️  Created config: .assignment-config.yaml")
-	fmt.Println("   📝 Created sample template: templates/multiple-choice.yaml")
+	printSuccess("Workspace initialized!")
+	fmt.Print("  ")
+	printMessage(iconFolder, "Created directories: templates/, resources/, packages/")
+	fmt.Print("  ")
+	printMessage(iconSettings, "Created config: .assignment-config.yaml")
+	fmt.Print("  ")
+	printMessage(iconNote, "Created sample template: templates/multiple-choice.yaml")
+	fmt.Print("  ")
+	printMessage(iconArrow, "New assignments are saved under packages/<quarter>/<type>/ (output_dir, output_layout)")
+	return nil
 }
 
-func runTypes(cmd *cobra.Command, args []string) {
-	typeManager := GetTypeManager()
+func runTypes(cmd *cobra.Command, args []string) error {
+	typeManager := toolkit.GetTypeManager()
 
-	fmt.Println("📋 Available Assignment Types")
+	printMessage(iconInfo, "Available Assignment Types")
 	fmt.Println("=" + strings.Repeat("=", 50))
 	fmt.Println()
 
-	// Get all types with descriptions
-	typesWithDesc := typeManager.ListTypesWithDescriptions()
+	// Get all type mappings
+	typesWithDesc := typeManager.ListMappings()
 
 	// Group by category for better display
-	categories := map[string][]string{
-		"📝 Quiz & Assessment": {
+	categories := []struct {
+		icon  Icon
+		title string
+		types []string
+	}{
+		{iconQuiz, "Quiz & Assessment", []string{
 			"multiple-choice", "true-false", "matching", "quiz",
-		},
-		"✍️  Writing & Essays": {
+		}},
+		{iconWriting, "Writing & Essays", []string{
 			"writing-short", "writing-long", "essay",
-		},
-		"🎯 Interactive": {
+		}},
+		{iconInteractive, "Interactive", []string{
 			"drag-drop-ordering", "drag-drop-categorization", "drag-drop-fill-blank",
 			"drag-drop-labeling", "drag-drop-image-caption",
-		},
-		"🗣️  Speaking & Listening": {
+		}},
+		{iconSpeaking, "Speaking & Listening", []string{
 			"speaking", "listening", "presentation", "comprehension",
-		},
-		"💻 Programming": {
+		}},
+		{iconCode, "Programming", []string{
 			"code-submission", "programming",
-		},
-		"📸 Media & Uploads": {
+		}},
+		{iconMedia, "Media & Uploads", []string{
 			"image-upload",
-		},
-		"🎓 Specialized (LMS-specific)": {
+		}},
+		{iconSpecialized, "Specialized (LMS-specific)", []string{
 			"line-match", "phoneme-build", "generic-assignment",
-		},
+		}},
 	}
 
-	for category, types := range categories {
-		fmt.Printf("%s\n", category)
-		fmt.Println(strings.Repeat("-", len(category)-4)) // Account for emoji
+	listed := make(map[string]bool)
+	for _, category := range categories {
+		for _, pType := range category.types {
+			listed[pType] = true
+		}
+	}
+	var customTypes []string
+	for pType := range typesWithDesc {
+		if typeManager.IsCustomType(pType) || (typeManager.IsFileType(pType) && !listed[pType]) {
+			customTypes = append(customTypes, pType)
+		}
+	}
+	if len(customTypes) > 0 {
+		sort.Strings(customTypes)
+		categories = append(categories, struct {
+			icon  Icon
+			title string
+			types []string
+		}{iconSettings, "Custom (this workspace)", customTypes})
+	}
+
+	for _, category := range categories {
+		printMessage(category.icon, "%s", category.title)
+		fmt.Println(strings.Repeat("-", len(category.title)))
 
-		for _, pType := range types {
-			if desc, exists := typesWithDesc[pType]; exists {
-				fmt.Printf("  %-20s %s\n", pType, desc)
+		for _, pType := range category.types {
+			if mapping, exists := typesWithDesc[pType]; exists {
+				lmsInfo := mapping.LMSType
+				if mapping.LMSSubtype != "" {
+					lmsInfo += " (" + mapping.LMSSubtype + ")"
+				}
+				fmt.Printf("  %-20s %s %s %s\n", pType, mapping.Description, icon(iconArrow), lmsInfo)
 			}
 		}
 		fmt.Println()
 	}
 
-	fmt.Println("💡 Usage Examples:")
+	printHint("Usage Examples:")
 	fmt.Println("  assignment-toolkit create multiple-choice")
 	fmt.Println("  assignment-toolkit create essay")
 	fmt.Println("  assignment-toolkit create drag-drop-ordering")
 	fmt.Println()
-	fmt.Println("🔄 Type Aliases (shortcuts):")
-	fmt.Println("  mcq, mc       → multiple-choice")
-	fmt.Println("  tf, t/f       → true-false")
-	fmt.Println("  match         → matching")
-	fmt.Println("  code          → code-submission")
-	fmt.Println("  dnd           → drag-drop-ordering")
-	fmt.Println("  oral          → speaking")
-	fmt.Println("  audio         → listening")
+	printMessage(iconSync, "Type Aliases (shortcuts):")
+	aliases := [][2]string{
+		{"mcq, mc", "multiple-choice"},
+		{"tf, t/f", "true-false"},
+		{"match", "matching"},
+		{"code", "code-submission"},
+		{"dnd", "drag-drop-ordering"},
+		{"oral", "speaking"},
+		{"audio", "listening"},
+	}
+	for _, alias := range aliases {
+		fmt.Printf("  %-13s %s %s\n", alias[0], icon(iconArrow), alias[1])
+	}
+	return nil
 }
 
 // Helper functions
 
-func createAssignmentWizard(assignmentType string) Assignment {
-	assignment := Assignment{
-		Type:             assignmentType,
-		Points:           1,
-		AutoGrade:        true,
-		ShowFeedback:     true,
-		ShuffleQuestions: false,
-		AllowReview:      true,
-		TrackAttempts:    true,
-		TrackConfidence:  true,
-		TrackTimeSpent:   true,
-		Published:        true,
-		Quarter:          "Q1",
-	}
+// createAssignmentWizard prompts for a new assignment, starting from seed
+// (the defaults or a template) for everything it does not ask about.
+// questions, when non-nil, replaces the question prompts.
+func createAssignmentWizard(assignmentType string, seed toolkit.Assignment, questions interface{}) toolkit.Assignment {
+	assignment := seed
+	assignment.Type = assignmentType
 
 	// Basic information
 	assignment.Title = promptString("Assignment title:", "")
-	assignment.Description = promptString("Description (optional):", "")
-	assignment.Category = promptString("Category (optional):", "")
-	assignment.Difficulty = promptSelect("Difficulty:", []string{"beginner", "intermediate", "advanced"})
+	assignment.Description = promptString("Description (optional):", seed.Description)
+	assignment.Category = promptString("Category (optional):", seed.Category)
+	assignment.Difficulty = promptSelect("Difficulty:", toolkit.AllowedDifficulties())
 
-	pointsStr := promptString("Points (default: 1):", "1")
+	pointsStr := promptString(fmt.Sprintf("Points (default: %d):", seed.Points), strconv.Itoa(seed.Points))
 	if points, err := strconv.Atoi(pointsStr); err == nil {
 		assignment.Points = points
 	}
 
 	// Type-specific questions
+	assignment.Questions = questions
 	switch assignmentType {
 	case "multiple-choice":
-		assignment.Questions = createMultipleChoiceQuestions()
+		if assignment.Questions == nil {
+			assignment.Questions = createMultipleChoiceQuestions()
+		}
+		assignment.ScoringMode = promptSelect("Scoring mode:", []string{toolkit.ScoringAllOrNothing, toolkit.ScoringPartial})
 	case "matching":
 		assignment.Questions = createMatchingQuestions()
-	case "writing", "writing-long":
+		// Partial credit per correct pair is the usual choice for matching
+		assignment.ScoringMode = promptSelect("Scoring mode:", []string{toolkit.ScoringPartial, toolkit.ScoringAllOrNothing})
+	case "writing", "writing-short", "writing-long", "essay":
 		assignment.Instructions = promptString("Instructions:", "")
 		assignment.Criteria = promptString("Grading criteria:", "")
+		assignment.MinWords = promptOptionalInt("Minimum words (optional):")
+		assignment.MaxWords = promptOptionalInt("Maximum words (optional):")
 		assignment.AutoGrade = false
 	case "code-submission":
-		assignment.Questions = createCodeSubmissionConfig()
+		assignment.CodeSubmissionConfig = createCodeSubmissionConfig()
 		assignment.AutoGrade = false
 	}
 
@@ -525,20 +1305,99 @@ func createMultipleChoiceQuestions() interface{} {
 		options = append(options, option)
 	}
 
+	var entries [][]string
+	for _, option := range options {
+		entries = append(entries, []string{option})
+	}
+	options = nil
+	for _, entry := range reviewEntries([]string{"Option"}, entries) {
+		options = append(options, entry[0])
+	}
+
 	correctAnswer := promptSelect("Correct answer:", options)
 	explanation := promptString("Explanation (optional):", "")
 
 	return map[string]interface{}{
 		"question":      question,
-		"options":       options,
+		"options":       promptOptionImages(options),
 		"correctAnswer": correctAnswer,
 		"explanation":   explanation,
 	}
 }
 
+// promptOptionImages optionally attaches an image to each option. Options
+// without an image stay plain text.
+func promptOptionImages(options []string) interface{} {
+	if answer := promptString("Add images to options? (y/N):", "n"); !strings.HasPrefix(strings.ToLower(answer), "y") {
+		return options
+	}
+
+	withImages := make([]interface{}, len(options))
+	for i, option := range options {
+		image := promptString(fmt.Sprintf("Image for %q (resource ID or file path, optional):", option), "")
+		if image == "" {
+			withImages[i] = option
+			continue
+		}
+		withImages[i] = map[string]interface{}{"text": option, "image": image}
+	}
+	return withImages
+}
+
+// promptResources attaches resource files to a new assignment. public is
+// the default answer to the visibility prompt (config resource_public).
+func promptResources(public bool) []toolkit.Resource {
+	if answer := promptString("Attach resource files? (y/N):", "n"); !strings.HasPrefix(strings.ToLower(answer), "y") {
+		return nil
+	}
+
+	visibilityPrompt, visibilityDefault := "Public, visible without signing in? (y/N):", "n"
+	if public {
+		visibilityPrompt, visibilityDefault = "Public, visible without signing in? (Y/n):", "y"
+	}
+
+	var resources []toolkit.Resource
+	for {
+		path := promptString(fmt.Sprintf("Resource %d file (or Enter to finish):", len(resources)+1), "")
+		if path == "" {
+			break
+		}
+		if _, err := os.Stat(path); err != nil {
+			printWarning("Resource not added: %v", err)
+			continue
+		}
+
+		order := len(resources) + 1
+		resources = append(resources, toolkit.Resource{
+			ID:        fmt.Sprintf("resource-%d", order),
+			Title:     promptString("Title:", filepath.Base(path)),
+			Type:      resourceType(path),
+			LocalPath: path,
+			Order:     order,
+			IsPublic:  strings.HasPrefix(strings.ToLower(promptString(visibilityPrompt, visibilityDefault)), "y"),
+		})
+	}
+	return resources
+}
+
+// resourceType guesses a resource's type from its file extension
+func resourceType(path string) string {
+	mimeType := mime.TypeByExtension(filepath.Ext(path))
+	for _, kind := range []string{"image", "video", "audio"} {
+		if strings.HasPrefix(mimeType, kind+"/") {
+			return kind
+		}
+	}
+	return "document"
+}
+
 func createMatchingQuestions() interface{} {
 	fmt.Println("Create matching pairs:")
 
+	if answer := promptString("Can several left items match the same right item? (y/N):", "n"); strings.HasPrefix(strings.ToLower(answer), "y") {
+		return createManyToOneMatching()
+	}
+
 	var leftItems, rightItems []string
 
 	for i := 0; i < 10; i++ {
@@ -552,10 +1411,121 @@ func createMatchingQuestions() interface{} {
 		rightItems = append(rightItems, right)
 	}
 
+	var entries [][]string
+	for i := range leftItems {
+		entries = append(entries, []string{leftItems[i], rightItems[i]})
+	}
+	leftItems, rightItems = nil, nil
+	for _, entry := range reviewEntries([]string{"Left item", "Right item"}, entries) {
+		leftItems = append(leftItems, entry[0])
+		rightItems = append(rightItems, entry[1])
+	}
+
+	if !promptWantsMatchingMedia() {
+		return map[string]interface{}{
+			"leftItems":  leftItems,
+			"rightItems": rightItems,
+		}
+	}
+	var leftWithMedia, rightWithMedia []interface{}
+	for i := range leftItems {
+		leftWithMedia = append(leftWithMedia, promptMatchingItemMedia(leftItems[i]))
+		rightWithMedia = append(rightWithMedia, promptMatchingItemMedia(rightItems[i]))
+	}
 	return map[string]interface{}{
-		"leftItems":  leftItems,
-		"rightItems": rightItems,
+		"leftItems":  leftWithMedia,
+		"rightItems": rightWithMedia,
+	}
+}
+
+// promptWantsMatchingMedia asks whether matching items get images or audio
+func promptWantsMatchingMedia() bool {
+	answer := promptString("Add images or audio to items? (y/N):", "n")
+	return strings.HasPrefix(strings.ToLower(answer), "y")
+}
+
+// promptMatchingItemMedia optionally attaches an image or audio clip to a
+// matching item. Audio and image files are recognized by extension;
+// resource IDs prompt for which kind they are.
+func promptMatchingItemMedia(text string) interface{} {
+	ref := promptString(fmt.Sprintf("Image or audio for %q (resource ID or file path, optional):", text), "")
+	if ref == "" {
+		return text
+	}
+
+	kind := resourceType(ref)
+	if kind != "audio" && kind != "image" {
+		kind = "image"
+		if answer := promptString("Is it an image or audio? (image/audio):", "image"); strings.HasPrefix(strings.ToLower(answer), "a") {
+			kind = "audio"
+		}
+	}
+	return map[string]interface{}{"text": text, kind: ref}
+}
+
+// createManyToOneMatching collects explicit pairs, e.g. words classified by
+// part of speech, where a right item may be reused
+func createManyToOneMatching() interface{} {
+	var entries [][]string
+	var rightItems []string
+
+	for i := 0; i < 20; i++ {
+		left := promptString(fmt.Sprintf("Left item %d (or Enter to finish):", i+1), "")
+		if left == "" {
+			break
+		}
+
+		prompt := fmt.Sprintf("Matches %q:", left)
+		if len(rightItems) > 0 {
+			prompt = fmt.Sprintf("Matches %q (so far: %s):", left, strings.Join(rightItems, ", "))
+		}
+		right := promptString(prompt, "")
+		if !containsString(rightItems, right) {
+			rightItems = append(rightItems, right)
+		}
+
+		entries = append(entries, []string{left, right})
+	}
+
+	entries = reviewEntries([]string{"Left item", "Right item"}, entries)
+	withMedia := promptWantsMatchingMedia()
+
+	// Right items are reused, so each one is asked about only once
+	rightMedia := make(map[string]interface{})
+	itemFor := func(text string, media map[string]interface{}) interface{} {
+		if !withMedia {
+			return text
+		}
+		if item, ok := media[text]; ok {
+			return item
+		}
+		media[text] = promptMatchingItemMedia(text)
+		return media[text]
+	}
+
+	var pairs []interface{}
+	for _, entry := range entries {
+		pairs = append(pairs, map[string]interface{}{
+			"left":  itemFor(entry[0], map[string]interface{}{}),
+			"right": itemFor(entry[1], rightMedia),
+		})
 	}
+	question := map[string]interface{}{"pairs": pairs}
+
+	var distractors []string
+	fmt.Println("Extra right items that match nothing (press Enter to finish):")
+	for i := 0; i < 10; i++ {
+		distractor := promptString(fmt.Sprintf("Distractor %d:", i+1), "")
+		if distractor == "" {
+			break
+		}
+		distractors = append(distractors, distractor)
+	}
+	if len(distractors) > 0 {
+		question["rightItems"] = distractors
+	}
+
+	return question
 }
 
 func createCodeSubmissionConfig() interface{} {
@@ -571,21 +1541,71 @@ func createCodeSubmissionConfig() interface{} {
 	}
 }
 
+// assignmentIDNamespace scopes IDs derived by stableAssignmentID
+var assignmentIDNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/PeterNoelEvans/LMS-assignment-toolkit"))
+
+// stableAssignmentID derives a deterministic ID from content that stays
+// the same when an assignment is regenerated from the same source
+func stableAssignmentID(assignment toolkit.Assignment) string {
+	key := strings.ToLower(strings.TrimSpace(assignment.Type)) + "\n" + strings.TrimSpace(assignment.Title)
+	return uuid.NewSHA1(assignmentIDNamespace, []byte(key)).String()
+}
+
+// packageIDFor picks the ID for a new package: an explicit --id, a stable
+// ID when requested by flag or config (defaults.stable_ids), or a random one
+func packageIDFor(cmd *cobra.Command, config toolkit.Config, assignment toolkit.Assignment) string {
+	if id, _ := cmd.Flags().GetString("id"); id != "" {
+		return id
+	}
+	if stable, _ := cmd.Flags().GetBool("stable-id"); stable || config.Defaults["stable_ids"] == "true" {
+		return stableAssignmentID(assignment)
+	}
+	return uuid.New().String()
+}
+
+// flagOrDefault returns a string flag's value when it was given, otherwise
+// the fallback (usually from config)
+func flagOrDefault(cmd *cobra.Command, name, fallback string) string {
+	if value, _ := cmd.Flags().GetString(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// stdinReader is shared by all prompts so buffered input is not lost
+// between calls when answers are piped in
+var stdinReader = bufio.NewReader(os.Stdin)
+
 func promptString(prompt, defaultValue string) string {
-	reader := bufio.NewReader(os.Stdin)
+	answer, _ := promptAnswer(prompt, defaultValue)
+	return answer
+}
+
+// promptAnswer is promptString that also reports false once input has run
+// out, so callers that re-prompt don't loop forever on piped answers
+func promptAnswer(prompt, defaultValue string) (string, bool) {
 	if defaultValue != "" {
 		fmt.Printf("%s [%s]: ", prompt, defaultValue)
 	} else {
 		fmt.Printf("%s: ", prompt)
 	}
 
-	input, _ := reader.ReadString('\n')
+	input, err := stdinReader.ReadString('\n')
 	input = strings.TrimSpace(input)
 
 	if input == "" {
-		return defaultValue
+		return defaultValue, err == nil
+	}
+	return input, true
+}
+
+// promptOptionalInt returns nil when the answer is empty or not a number
+func promptOptionalInt(prompt string) *int {
+	value, err := strconv.Atoi(promptString(prompt, ""))
+	if err != nil {
+		return nil
 	}
-	return input
+	return &value
 }
 
 func promptSelect(prompt string, options []string) string {
@@ -594,10 +1614,9 @@ func promptSelect(prompt string, options []string) string {
 		fmt.Printf("  %d. %s\n", i+1, option)
 	}
 
-	reader := bufio.NewReader(os.Stdin)
 	fmt.Print("Select (1-", len(options), "): ")
 
-	input, _ := reader.ReadString('\n')
+	input, _ := stdinReader.ReadString('\n')
 	input = strings.TrimSpace(input)
 
 	if choice, err := strconv.Atoi(input); err == nil && choice >= 1 && choice <= len(options) {
@@ -607,102 +1626,132 @@ func promptSelect(prompt string, options []string) string {
 	return options[0] // Default to first option
 }
 
-func saveAssignmentPackage(pkg AssignmentPackage, filename string) error {
-	data, err := yaml.Marshal(pkg)
-	if err != nil {
-		return err
+// findAssignmentFiles returns the assignment files in dir, skipping hidden
+// files such as .assignment-config.yaml
+func findAssignmentFiles(dir string) ([]string, error) {
+	var files []string
+	for _, pattern := range assignmentFilePatterns {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if !strings.HasPrefix(filepath.Base(match), ".") {
+				files = append(files, match)
+			}
+		}
 	}
+	return files, nil
+}
 
-	return ioutil.WriteFile(filename, data, 0644)
+// findAssignmentFilesRecursive walks root for assignment files, skipping
+// hidden directories and the templates directory
+func findAssignmentFilesRecursive(root string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name := info.Name()
+		if info.IsDir() {
+			if path != root && (strings.HasPrefix(name, ".") || name == "templates") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isAssignmentFileName(name) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
 }
 
-func loadAssignmentPackage(filename string) (AssignmentPackage, error) {
-	var pkg AssignmentPackage
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
 
-	data, err := ioutil.ReadFile(filename)
+func copyFile(src, dst string) error {
+	data, err := ioutil.ReadFile(src)
 	if err != nil {
-		return pkg, err
+		return err
 	}
-
-	err = yaml.Unmarshal(data, &pkg)
-	return pkg, err
+	return ioutil.WriteFile(dst, data, 0644)
 }
 
-func validateAssignmentPackage(pkg AssignmentPackage) ValidationInfo {
-	validation := ValidationInfo{
-		IsValid:          true,
-		ValidatedAt:      time.Now(),
-		ValidatorVersion: "1.0.0",
-		Score:            100,
-	}
+// strictTypesUsage describes the --strict-types flag of the sync commands
+const strictTypesUsage = "Fail on assignment types with no LMS mapping instead of sending them unchanged (strict_types in config)"
+
+// timeoutPerResourceUsage describes the --timeout-per-resource flag of the sync commands
+const timeoutPerResourceUsage = "Give up on a resource upload that takes longer than this (e.g. 2m); the assignment is reported as partially synced"
 
-	// Basic validation
-	if pkg.Assignment.Title == "" {
-		validation.Errors = append(validation.Errors, "Assignment title is required")
-		validation.IsValid = false
-		validation.Score -= 20
+// applyStrictTypesFlag turns on config.StrictTypes when --strict-types is
+// given; the flag can only tighten the configured setting
+func applyStrictTypesFlag(cmd *cobra.Command, config *toolkit.Config) {
+	if strict, _ := cmd.Flags().GetBool("strict-types"); strict {
+		config.StrictTypes = true
 	}
+}
 
-	if pkg.Assignment.Type == "" {
-		validation.Errors = append(validation.Errors, "Assignment type is required")
-		validation.IsValid = false
-		validation.Score -= 20
+// newLMSClientFromConfig creates a client for the configured endpoint with
+// the configured payload transforms and field filter
+func newLMSClientFromConfig(config toolkit.Config) (*toolkit.LMSClient, error) {
+	transforms, err := toolkit.ResolvePayloadTransforms(config.PayloadTransforms)
+	if err != nil {
+		return nil, err
 	}
 
-	// Type-specific validation
-	switch pkg.Assignment.Type {
-	case "multiple-choice":
-		if pkg.Assignment.Questions == nil {
-			validation.Errors = append(validation.Errors, "Multiple choice questions are required")
-			validation.IsValid = false
-			validation.Score -= 30
-		}
-	case "matching":
-		if pkg.Assignment.Questions == nil {
-			validation.Errors = append(validation.Errors, "Matching items are required")
-			validation.IsValid = false
-			validation.Score -= 30
+	retry := toolkit.DefaultRetryConfig
+	if config.SyncRetries != nil {
+		if *config.SyncRetries < 0 {
+			return nil, fmt.Errorf("sync_retries must be 0 or more, got %d", *config.SyncRetries)
 		}
+		retry.MaxRetries = *config.SyncRetries
 	}
-
-	// Warnings
-	if pkg.Assignment.Description == "" {
-		validation.Warnings = append(validation.Warnings, "Assignment description is recommended")
-		validation.Score -= 5
+	if config.SyncRetryMaxSeconds > 0 {
+		retry.MaxElapsed = time.Duration(config.SyncRetryMaxSeconds) * time.Second
 	}
 
-	if pkg.Assignment.Points <= 0 {
-		validation.Warnings = append(validation.Warnings, "Assignment should have positive points")
-		validation.Score -= 10
+	client := toolkit.NewLMSClientWithRetry(config.LMSEndpoint, config.APIKey, retry)
+	client.Transforms = transforms
+	client.Fields = config.SyncFields
+	if config.FieldMappingFile != "" {
+		if client.FieldMapping, err = toolkit.LoadFieldMapping(config.FieldMappingFile); err != nil {
+			return nil, fmt.Errorf("field_mapping_file: %v", err)
+		}
 	}
-
-	return validation
-}
-
-func calculateHash(pkg AssignmentPackage) string {
-	data, _ := json.Marshal(pkg.Assignment)
-	hash := sha256.Sum256(data)
-	return fmt.Sprintf("%x", hash)
-}
-
-func copyFile(src, dst string) error {
-	data, err := ioutil.ReadFile(src)
-	if err != nil {
-		return err
+	client.GzipRequests = config.GzipUploads
+	client.StrictTypes = config.StrictTypes
+	client.ChunkSize = int64(config.UploadChunkSizeMB) << 20
+	client.MaxRateLimitWait = time.Duration(config.RateLimitMaxWaitSeconds) * time.Second
+	client.Signing = config.RequestSigning
+	if verboseHTTP {
+		client.EnableHTTPLog(os.Stderr, maxBodyLog)
 	}
-	return ioutil.WriteFile(dst, data, 0644)
+	return client, nil
 }
 
-func getConfig() Config {
-	config := Config{
-		Author:   "Unknown Author",
-		License:  "CC-BY-SA-4.0",
-		Language: "en",
-	}
-
+func getConfig() toolkit.Config {
+	var config toolkit.Config
 	if data, err := ioutil.ReadFile(".assignment-config.yaml"); err == nil {
 		yaml.Unmarshal(data, &config)
 	}
 
+	// Fallbacks also cover keys written out empty, as 'config export'
+	// does for author and email
+	if config.Author == "" {
+		config.Author = "Unknown Author"
+	}
+	if config.License == "" {
+		config.License = "CC-BY-SA-4.0"
+	}
+	if config.Language == "" {
+		config.Language = "en"
+	}
 	return config
 }
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
+
+// Completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate a shell completion script",
+	Long: `Print a script that makes your shell tab-complete commands, flags,
+assignment types, templates, and assignment files.
+
+Bash (needs the bash-completion package):
+  source <(assignment-toolkit completion bash)
+  # or permanently:
+  assignment-toolkit completion bash > /etc/bash_completion.d/assignment-toolkit
+
+Zsh:
+  assignment-toolkit completion zsh > "${fpath[1]}/_assignment-toolkit"
+  # then start a new shell (compinit must be enabled)
+
+Fish:
+  assignment-toolkit completion fish > ~/.config/fish/completions/assignment-toolkit.fish
+
+PowerShell:
+  assignment-toolkit completion powershell | Out-String | Invoke-Expression
+  # add that line to your $PROFILE to keep it`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE:                  runCompletion,
+}
+
+func runCompletion(cmd *cobra.Command, args []string) error {
+	var err error
+	switch args[0] {
+	case "bash":
+		err = rootCmd.GenBashCompletionV2(os.Stdout, true)
+	case "zsh":
+		err = rootCmd.GenZshCompletion(os.Stdout)
+	case "fish":
+		err = rootCmd.GenFishCompletion(os.Stdout, true)
+	case "powershell":
+		err = rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+	}
+	if err != nil {
+		return failf("Failed to generate %s completion: %v", args[0], err)
+	}
+	return nil
+}
+
+// isCompletionRequest reports whether cmd is the hidden command shells run
+// to ask for completions
+func isCompletionRequest(cmd *cobra.Command) bool {
+	return cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd
+}
+
+// completeTypes suggests portable assignment types with their descriptions
+func completeTypes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	typeManager := toolkit.GetTypeManager()
+	var suggestions []string
+	for _, pType := range typeManager.GetPortableTypes() {
+		if strings.HasPrefix(pType, toComplete) {
+			suggestions = append(suggestions, pType+"\t"+typeManager.GetTypeDescription(pType))
+		}
+	}
+	sort.Strings(suggestions)
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTemplates suggests template names from the config and templates/
+func completeTemplates(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names := make(map[string]bool)
+	for name := range getConfig().Templates {
+		names[name] = true
+	}
+	matches, _ := filepath.Glob(filepath.Join("templates", "*.yaml"))
+	for _, match := range matches {
+		names[strings.TrimSuffix(filepath.Base(match), ".yaml")] = true
+	}
+
+	var suggestions []string
+	for name := range names {
+		if strings.HasPrefix(name, toComplete) {
+			suggestions = append(suggestions, name)
+		}
+	}
+	sort.Strings(suggestions)
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeListFields suggests the comma-separated field names for
+// 'list --fields', continuing after the fields already typed
+func completeListFields(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	typed, current := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		typed, current = toComplete[:i+1], toComplete[i+1:]
+	}
+
+	var suggestions []string
+	for _, field := range listFields {
+		if strings.HasPrefix(field, current) && !strings.Contains(","+typed, ","+field+",") {
+			suggestions = append(suggestions, typed+field)
+		}
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completeCreateType suggests the type argument of 'create', including
+// aliases such as mcq
+func completeCreateType(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	suggestions, directive := completeTypes(cmd, args, toComplete)
+	var aliases []string
+	for alias, target := range toolkit.GetTypeManager().GetAliases() {
+		if strings.HasPrefix(alias, toComplete) {
+			aliases = append(aliases, alias+"\tAlias for "+target)
+		}
+	}
+	sort.Strings(aliases)
+	return append(suggestions, aliases...), directive
+}
+
+// completeCustomTypes suggests the workspace's custom types
+func completeCustomTypes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var suggestions []string
+	for _, mapping := range getConfig().CustomTypes {
+		if strings.HasPrefix(mapping.PortableType, toComplete) {
+			suggestions = append(suggestions, mapping.PortableType+"\t"+mapping.Description)
+		}
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRuleIDs suggests validation rule IDs with their descriptions
+func completeRuleIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var suggestions []string
+	for _, rule := range toolkit.ValidationRules() {
+		if strings.HasPrefix(rule.ID, toComplete) {
+			suggestions = append(suggestions, rule.ID+"\t"+rule.Description)
+		}
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// assignmentFileExtensions limit file completion to assignment files,
+// including compressed ones
+var assignmentFileExtensions = []string{"yaml", "yml", "gz"}
+
+// completeAssignmentFile suggests assignment files for a command whose
+// first argument is the file
+func completeAssignmentFile(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return assignmentFileExtensions, cobra.ShellCompDirectiveFilterFileExt
+}
+
+// completeAssignmentFiles suggests assignment files for every argument
+func completeAssignmentFiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return assignmentFileExtensions, cobra.ShellCompDirectiveFilterFileExt
+}
+
+// completeCSVFile suggests spreadsheets exported as CSV for the first
+// argument
+func completeCSVFile(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return []string{"csv"}, cobra.ShellCompDirectiveFilterFileExt
+}
+
+// completeZipFile suggests zip archives (and directories) for the first
+// argument
+func completeZipFile(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return []string{"zip"}, cobra.ShellCompDirectiveFilterFileExt
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompleteListFields(t *testing.T) {
+	suggestions, _ := completeListFields(listCmd, nil, "title,t")
+	if want := []string{"title,type", "title,tags"}; !reflect.DeepEqual(suggestions, want) {
+		t.Errorf("suggestions = %q, want %q", suggestions, want)
+	}
+}
+
+func TestCompleteCreateTypeIncludesAliases(t *testing.T) {
+	suggestions, _ := completeCreateType(createCmd, nil, "mc")
+	if want := []string{"mc\tAlias for multiple-choice", "mcq\tAlias for multiple-choice"}; !reflect.DeepEqual(suggestions, want) {
+		t.Errorf("suggestions = %q, want %q", suggestions, want)
+	}
+	if suggestions, _ := completeCreateType(createCmd, []string{"essay"}, ""); len(suggestions) != 0 {
+		t.Errorf("create takes one type, got more suggestions: %q", suggestions)
+	}
+}
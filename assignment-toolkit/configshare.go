@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	configExportCmd.Flags().Bool("with-secrets", false, "Include the API key and request signing secret in the exported file")
+	configImportCmd.Flags().BoolP("yes", "y", false, "Import without asking when the file changes lms_endpoint")
+	configCmd.AddCommand(configExportCmd)
+	configCmd.AddCommand(configImportCmd)
+}
+
+var configExportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Write the workspace configuration to a file for sharing",
+	Long: `Write the workspace configuration (endpoint, defaults, templates, sync
+settings) to a file other teachers can import. Your author and email are
+left out, as are the API key and signing secret unless --with-secrets is
+given. Use "-" to write to standard output.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigExport,
+}
+
+var configImportCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Replace the workspace configuration with a shared one",
+	Long: `Replace .assignment-config.yaml with the settings in a file written by
+'config export'. Your own author, email, and update_url are always kept, and
+your API key and signing secret when they are already set, so a department
+file can be imported on every machine.
+
+When the file would change lms_endpoint, the old and new endpoints are shown
+and import asks before continuing; pass --yes to skip the question.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigImport,
+}
+
+func runConfigExport(cmd *cobra.Command, args []string) error {
+	withSecrets, _ := cmd.Flags().GetBool("with-secrets")
+
+	// Read the file as-is so getConfig's fallbacks aren't exported
+	var config toolkit.Config
+	data, err := ioutil.ReadFile(".assignment-config.yaml")
+	if err != nil {
+		return failf("Failed to read .assignment-config.yaml: %v", err)
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return failf(".assignment-config.yaml is not valid: %v", err)
+	}
+
+	// Author, email, and update_url belong to the exporter, not the department
+	config.Author = ""
+	config.Email = ""
+	config.UpdateURL = ""
+	if !withSecrets {
+		config.APIKey = ""
+		config.RequestSigning.Secret = ""
+	}
+
+	data, err = yaml.Marshal(config)
+	if err != nil {
+		return failf("Failed to encode configuration: %v", err)
+	}
+
+	if args[0] == "-" {
+		os.Stdout.Write(data)
+		return nil
+	}
+	if err := ioutil.WriteFile(args[0], data, 0644); err != nil {
+		return failf("Failed to write %s: %v", args[0], err)
+	}
+
+	printSuccess("Configuration exported to %s", args[0])
+	if withSecrets && (config.APIKey != "" || config.RequestSigning.Secret != "") {
+		printWarning("%s contains your API key or signing secret; don't share it publicly", args[0])
+	}
+	return nil
+}
+
+func runConfigImport(cmd *cobra.Command, args []string) error {
+	data, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return failf("Failed to read %s: %v", args[0], err)
+	}
+
+	var imported toolkit.Config
+	if err := yaml.UnmarshalStrict(data, &imported); err != nil {
+		return failf("%s is not a valid configuration: %v", args[0], err)
+	}
+	if _, err := toolkit.ResolvePayloadTransforms(imported.PayloadTransforms); err != nil {
+		return failf("%s: %v", args[0], err)
+	}
+
+	// Personal settings stay with the machine they were set on. Author,
+	// email, and update_url are never taken from the shared file, even one
+	// exported before they were left out.
+	var local toolkit.Config
+	if existing, err := ioutil.ReadFile(".assignment-config.yaml"); err == nil {
+		yaml.Unmarshal(existing, &local)
+	}
+	if imported.UpdateURL != "" && imported.UpdateURL != local.UpdateURL {
+		printWarning("Not importing update_url %s; it is a personal setting", imported.UpdateURL)
+	}
+	imported.Author = local.Author
+	imported.Email = local.Email
+	imported.UpdateURL = local.UpdateURL
+	keepPersonal(&imported.APIKey, local.APIKey)
+	keepPersonal(&imported.RequestSigning.Secret, local.RequestSigning.Secret)
+
+	// The API key is kept, so a new endpoint would receive it on the next sync
+	if local.LMSEndpoint != "" && imported.LMSEndpoint != local.LMSEndpoint {
+		printWarning("%s changes lms_endpoint", args[0])
+		printBullet("old: %s", local.LMSEndpoint)
+		printBullet("new: %s", imported.LMSEndpoint)
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+			if answer := promptString("Import it anyway? (y/N):", "n"); !strings.HasPrefix(strings.ToLower(answer), "y") {
+				printError("Import cancelled; .assignment-config.yaml is unchanged")
+				printHint("Pass --yes to skip this question in scripts")
+				return errFailed
+			}
+		}
+	}
+
+	out, err := yaml.Marshal(imported)
+	if err != nil {
+		return failf("Failed to encode configuration: %v", err)
+	}
+	if err := ioutil.WriteFile(".assignment-config.yaml", out, 0644); err != nil {
+		return failf("Failed to write .assignment-config.yaml: %v", err)
+	}
+
+	printSuccess("Configuration imported from %s", args[0])
+	if imported.LMSEndpoint != "" && imported.APIKey == "" {
+		printHint("Add your own 'api_key' to .assignment-config.yaml before syncing")
+	}
+	fmt.Println()
+	printHint("Run 'assignment-toolkit doctor' to check the imported settings")
+	return nil
+}
+
+// keepPersonal keeps a locally set value over the imported one
+func keepPersonal(imported *string, local string) {
+	if local != "" {
+		*imported = local
+	}
+}
+
+// updateConfigFile applies change to .assignment-config.yaml. The file is
+// read as-is, so the fallbacks getConfig fills in aren't written back.
+func updateConfigFile(change func(config *toolkit.Config) error) error {
+	var config toolkit.Config
+	data, err := ioutil.ReadFile(".assignment-config.yaml")
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf(".assignment-config.yaml is not valid: %v", err)
+	}
+
+	if err := change(&config); err != nil {
+		return err
+	}
+
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(".assignment-config.yaml", out, 0644)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestConfigImportConfirmsEndpointChange(t *testing.T) {
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(dir) })
+	previous := stdinReader
+	t.Cleanup(func() { stdinReader = previous })
+
+	local := "lms_endpoint: https://lms.school.edu\napi_key: key\nupdate_url: https://mirror.school.edu/latest\n"
+	if err := ioutil.WriteFile(".assignment-config.yaml", []byte(local), 0644); err != nil {
+		t.Fatal(err)
+	}
+	shared := "lms_endpoint: https://lms.example.com\nupdate_url: https://example.com/latest\nlanguage: fr\n"
+	if err := ioutil.WriteFile("shared.yaml", []byte(shared), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Declining leaves the workspace config alone
+	stdinReader = bufio.NewReader(strings.NewReader("n\n"))
+	if err := runConfigImport(configImportCmd, []string{"shared.yaml"}); err != errFailed {
+		t.Fatalf("err = %v, want errFailed after declining", err)
+	}
+	if data, _ := ioutil.ReadFile(".assignment-config.yaml"); string(data) != local {
+		t.Errorf("config changed after declining:\n%s", data)
+	}
+
+	stdinReader = bufio.NewReader(strings.NewReader("y\n"))
+	if err := runConfigImport(configImportCmd, []string{"shared.yaml"}); err != nil {
+		t.Fatal(err)
+	}
+	config := getConfig()
+	if config.LMSEndpoint != "https://lms.example.com" || config.Language != "fr" {
+		t.Errorf("config = %+v, want the shared endpoint and language", config)
+	}
+	if config.APIKey != "key" || config.UpdateURL != "https://mirror.school.edu/latest" {
+		t.Errorf("api_key = %q, update_url = %q; want the local ones kept", config.APIKey, config.UpdateURL)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+// yesUsage describes the --yes flag of the sync commands
+const yesUsage = "Skip the confirmation production or confirm_before_sync asks for (for scripts)"
+
+// confirmSync makes the user type the endpoint host before a sync when the
+// config marks the endpoint as production or sets confirm_before_sync.
+// --yes skips the question.
+func confirmSync(cmd *cobra.Command, config toolkit.Config) error {
+	if !config.Production && !config.ConfirmBeforeSync {
+		return nil
+	}
+	if yes, _ := cmd.Flags().GetBool("yes"); yes {
+		return nil
+	}
+
+	host := config.LMSEndpoint
+	if parsed, err := url.Parse(config.LMSEndpoint); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+
+	if config.Production {
+		printWarning("%s is the production LMS; students will see what you sync", config.LMSEndpoint)
+	} else {
+		printMessage(iconSync, "About to sync with %s", config.LMSEndpoint)
+	}
+	answer := promptString(fmt.Sprintf("Type %s to continue", host), "")
+	if !strings.EqualFold(answer, host) {
+		printError("Sync cancelled: %q does not match %s", answer, host)
+		printHint("Pass --yes to skip this question in scripts")
+		return errFailed
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	convertCmd.Flags().String("to", "", "Type to convert the assignment to")
+	convertCmd.MarkFlagRequired("to")
+	convertCmd.RegisterFlagCompletionFunc("to", completeTypes)
+	rootCmd.AddCommand(convertCmd)
+}
+
+// Convert command
+var convertCmd = &cobra.Command{
+	Use:   "convert [file]",
+	Short: "Change an assignment to another type, restructuring its questions",
+	Long: `Rewrite an assignment as another type, in place. Types that map to the
+same LMS type (quiz and multiple-choice, essay and writing-long) and the
+written types convert as they are, storing the canonical type name.
+True/false questions gain True and False options when converted to
+multiple-choice, and multiple-choice questions that only offer True and
+False convert back. Other conversions are refused.
+
+The converted assignment is validated and only saved when it is valid.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAssignmentFile,
+	RunE:              runConvert,
+}
+
+func runConvert(cmd *cobra.Command, args []string) error {
+	filename := args[0]
+	target, _ := cmd.Flags().GetString("to")
+
+	pkg, err := toolkit.LoadPackage(filename)
+	if err != nil {
+		return failf("Failed to load assignment: %v", err)
+	}
+
+	oldType := pkg.Assignment.Type
+	if canonical, ok := toolkit.GetTypeManager().CanonicalType(target); ok && canonical == oldType {
+		printMessage(iconInfo, "%s is already %s", filename, oldType)
+		return nil
+	}
+
+	notes, err := toolkit.ConvertAssignmentType(&pkg, target)
+	if err != nil {
+		return failf("Not converting %s: %v", filename, err)
+	}
+
+	validation := toolkit.ValidateAssignmentPackage(pkg)
+	if !validation.IsValid {
+		printError("The converted assignment would be invalid; %s was not changed", filename)
+		for _, err := range validation.Errors {
+			printBullet("%s", err)
+		}
+		return errFailed
+	}
+
+	pkg.Metadata.TypeMapping, _ = toolkit.ResolveTypeMapping(pkg.Assignment.Type)
+	pkg.Metadata.Modified = toolkit.Now()
+	pkg.Metadata.SourceHash = toolkit.CalculateHash(pkg)
+	if err := toolkit.SavePackage(pkg, filename); err != nil {
+		return failf("Failed to save assignment: %v", err)
+	}
+
+	printSuccess("%s: %s %s %s", filename, oldType, icon(iconArrow), pkg.Assignment.Type)
+	for _, note := range notes {
+		printBullet("%s", note)
+	}
+	for _, warning := range validation.Warnings {
+		printWarning("%s", warning)
+	}
+	return nil
+}
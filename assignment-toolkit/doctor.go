@@ -0,0 +1,282 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	doctorCmd.Flags().Bool("check-endpoint", false, "Also test the connection to the configured LMS endpoint")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// Doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the workspace and environment",
+	Long: `Check the current workspace for common problems: missing or invalid
+configuration, missing directories, unparseable assignment files, missing
+resource files, and unknown assignment types.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+// doctorCheck is a single diagnostic result
+type doctorCheck struct {
+	Name   string
+	Passed bool
+	Detail string
+	Hint   string
+	Trace  []string // shown with --verbose
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	checkEndpoint, _ := cmd.Flags().GetBool("check-endpoint")
+
+	printMessage(iconDoctor, "Checking assignment workspace...")
+	fmt.Println()
+
+	var checks []doctorCheck
+	checks = append(checks, checkConfigFile()...)
+	checks = append(checks, checkWorkspaceDirs()...)
+	if checkEndpoint {
+		checks = append(checks, checkLMSEndpoint())
+	}
+	checks = append(checks, checkAssignmentFiles()...)
+
+	failed := 0
+	for _, check := range checks {
+		line := check.Name
+		if check.Detail != "" {
+			line += " - " + check.Detail
+		}
+		if check.Passed {
+			printSuccess("%s", line)
+		} else {
+			printError("%s", line)
+			failed++
+		}
+		if verboseHTTP {
+			for _, line := range check.Trace {
+				fmt.Printf("     %s\n", line)
+			}
+		}
+		if !check.Passed && check.Hint != "" {
+			fmt.Print("   ")
+			printHint("%s", check.Hint)
+		}
+	}
+
+	fmt.Println()
+	if failed == 0 {
+		printSuccess("All %d checks passed", len(checks))
+	} else {
+		printWarning("%d of %d checks failed", failed, len(checks))
+		return errFailed
+	}
+	return nil
+}
+
+func checkConfigFile() []doctorCheck {
+	data, err := ioutil.ReadFile(".assignment-config.yaml")
+	if err != nil {
+		return []doctorCheck{{
+			Name: "Configuration file",
+			Hint: "Run 'assignment-toolkit init' to create .assignment-config.yaml",
+		}}
+	}
+
+	var config toolkit.Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return []doctorCheck{{
+			Name:   "Configuration file",
+			Detail: err.Error(),
+			Hint:   "Fix the YAML syntax in .assignment-config.yaml (use spaces, not tabs)",
+		}}
+	}
+
+	checks := []doctorCheck{{Name: "Configuration file", Passed: true, Detail: ".assignment-config.yaml"}}
+
+	authorCheck := doctorCheck{Name: "Author configured", Passed: config.Author != ""}
+	if !authorCheck.Passed {
+		authorCheck.Hint = "Set 'author' in .assignment-config.yaml"
+	}
+	checks = append(checks, authorCheck)
+
+	endpointCheck := doctorCheck{Name: "LMS endpoint configured"}
+	if config.LMSEndpoint == "" {
+		endpointCheck.Hint = "Set 'lms_endpoint' in .assignment-config.yaml to enable sync"
+	} else if parsed, err := url.Parse(config.LMSEndpoint); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		endpointCheck.Detail = fmt.Sprintf("invalid URL %q", config.LMSEndpoint)
+		endpointCheck.Hint = "Use a full URL such as https://your-lms.com"
+	} else {
+		endpointCheck.Passed = true
+		endpointCheck.Detail = config.LMSEndpoint
+	}
+	checks = append(checks, endpointCheck)
+
+	return checks
+}
+
+func checkWorkspaceDirs() []doctorCheck {
+	var checks []doctorCheck
+	for _, dir := range []string{"templates", "resources", "packages"} {
+		check := doctorCheck{Name: fmt.Sprintf("Directory %s/", dir)}
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			check.Passed = true
+		} else {
+			check.Hint = "Run 'assignment-toolkit init' or create it with: mkdir " + dir
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func checkLMSEndpoint() doctorCheck {
+	config := getConfig()
+	check := doctorCheck{Name: "LMS endpoint reachable"}
+	if config.LMSEndpoint == "" {
+		check.Detail = "no endpoint configured"
+		check.Hint = "Set 'lms_endpoint' in .assignment-config.yaml"
+		return check
+	}
+
+	client, err := newLMSClientFromConfig(config)
+	if err != nil {
+		check.Detail = err.Error()
+		check.Hint = "Fix 'payload_transforms' in .assignment-config.yaml"
+		return check
+	}
+
+	diag := client.DiagnoseConnection()
+	check.Trace = connectionTrace(diag)
+	if diag.Err != nil {
+		check.Detail = diag.Err.Error()
+		check.Hint = connectionHint(diag.Stage)
+		return check
+	}
+
+	check.Passed = true
+	return check
+}
+
+// connectionTrace describes each step of a connection test
+func connectionTrace(diag *toolkit.ConnectionDiagnostics) []string {
+	lines := []string{"URL: " + diag.URL}
+	if len(diag.Addresses) > 0 {
+		lines = append(lines, fmt.Sprintf("Resolved %s: %s", diag.Host, strings.Join(diag.Addresses, ", ")))
+	} else if diag.Stage == toolkit.StageDNS {
+		lines = append(lines, fmt.Sprintf("Resolved %s: failed", diag.Host))
+	}
+	if diag.TLS {
+		switch {
+		case diag.TLSVersion != "":
+			lines = append(lines, "TLS handshake: "+diag.TLSVersion)
+		case diag.Stage == toolkit.StageTLS:
+			lines = append(lines, "TLS handshake: failed")
+		}
+	}
+	if diag.StatusCode != 0 {
+		lines = append(lines, fmt.Sprintf("Status: %d %s", diag.StatusCode, http.StatusText(diag.StatusCode)))
+	}
+	return lines
+}
+
+// connectionHint suggests a fix for the step a connection test failed at
+func connectionHint(stage string) string {
+	switch stage {
+	case toolkit.StageDNS:
+		return "Check the host name in 'lms_endpoint' and your DNS or network connection"
+	case toolkit.StageConnect:
+		return "Check your network connection and that the LMS is running on that host and port"
+	case toolkit.StageTLS:
+		return "Check the endpoint uses the right scheme (http/https) and that its certificate is trusted"
+	case toolkit.StageAuth:
+		return "Check 'api_key' in .assignment-config.yaml"
+	case toolkit.StagePath:
+		return "The host answered but has no LMS API there; check the path in 'lms_endpoint'"
+	default:
+		return "Check the LMS server logs; run with --verbose to see the full exchange"
+	}
+}
+
+func checkAssignmentFiles() []doctorCheck {
+	files, err := findWorkspaceAssignmentFiles()
+	if err != nil {
+		return []doctorCheck{{Name: "Assignment files", Detail: err.Error()}}
+	}
+	if len(files) == 0 {
+		return []doctorCheck{{
+			Name:   "Assignment files",
+			Passed: true,
+			Detail: "no assignment files found",
+		}}
+	}
+
+	typeManager := toolkit.GetTypeManager()
+	var checks []doctorCheck
+
+	for _, file := range files {
+		pkg, err := toolkit.LoadPackage(file)
+		if err != nil {
+			check := doctorCheck{
+				Name:   file,
+				Detail: fmt.Sprintf("cannot be parsed: %v", err),
+				Hint:   "Check the YAML syntax (indent with spaces, quote values containing ':')",
+			}
+			if parseErr, ok := err.(*toolkit.ParseError); ok && parseErr.Line > 0 {
+				check.Detail = fmt.Sprintf("cannot be parsed at line %d: %s", parseErr.Line, parseErr.Problem)
+				if parseErr.Hint != "" {
+					check.Hint = strings.ToUpper(parseErr.Hint[:1]) + parseErr.Hint[1:]
+				}
+			}
+			checks = append(checks, check)
+			continue
+		}
+
+		check := doctorCheck{Name: file, Passed: true}
+
+		if pkg.Assignment.Type == "" {
+			check.Passed = false
+			check.Detail = "missing assignment type"
+			check.Hint = "Add 'type' under 'assignment'; see 'assignment-toolkit types'"
+		} else if !typeManager.ValidatePortableType(pkg.Assignment.Type) {
+			check.Passed = false
+			check.Detail = fmt.Sprintf("unknown type %q", pkg.Assignment.Type)
+			check.Hint = "Use a type listed by 'assignment-toolkit types'"
+		}
+
+		var missing []string
+		for _, resource := range pkg.Resources {
+			if resource.LocalPath == "" {
+				continue
+			}
+			if _, err := os.Stat(resource.LocalPath); err != nil {
+				missing = append(missing, resource.LocalPath)
+			}
+		}
+		if len(missing) > 0 {
+			check.Passed = false
+			if check.Detail != "" {
+				check.Detail += "; "
+			}
+			check.Detail += fmt.Sprintf("missing resource(s): %v", missing)
+			if check.Hint != "" {
+				check.Hint += "; "
+			}
+			check.Hint += "Restore the resource files or update their local_path"
+		}
+
+		checks = append(checks, check)
+	}
+
+	return checks
+}
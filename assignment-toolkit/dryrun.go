@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"assignment-toolkit/pkg/toolkit"
+)
+
+// runDrySync prints the payload sync would send for pkg and the files it
+// would upload, without contacting the LMS. It fails when a local file is
+// missing, as the real sync would only partly succeed.
+func runDrySync(config toolkit.Config, pkg toolkit.AssignmentPackage) error {
+	client, err := newLMSClientFromConfig(config)
+	if err != nil {
+		return failf("Invalid sync configuration: %v", err)
+	}
+	payload, err := client.LMSPayload(pkg)
+	if err != nil {
+		return failf("Not syncing: %v", err)
+	}
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return failf("Failed to encode the payload: %v", err)
+	}
+
+	printMessage(iconInfo, "Dry run: nothing is sent to %s", config.LMSEndpoint)
+	fmt.Println(string(data))
+	fmt.Println()
+
+	missing := 0
+	var uploads []toolkit.ResourcePlan
+	for _, plan := range toolkit.PlanResources(pkg, "") {
+		if plan.Local() {
+			uploads = append(uploads, plan)
+		}
+	}
+	fmt.Printf("%d resource(s) would be uploaded\n", len(uploads))
+	for _, plan := range uploads {
+		if plan.Exists {
+			printBullet("%s (%s)", plan.Path, formatSize(plan.Size))
+			continue
+		}
+		printBullet("%s (%s)", plan.Path, plan.Error)
+		missing++
+	}
+
+	if missing > 0 {
+		printError("%d resource file(s) missing; the sync would be partial", missing)
+		return errFailed
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+// editAfterCreate reports whether create should open the new file, from
+// --edit-after or the defaults.edit_after config value
+func editAfterCreate(cmd *cobra.Command, config toolkit.Config) bool {
+	if cmd.Flags().Changed("edit-after") {
+		edit, _ := cmd.Flags().GetBool("edit-after")
+		return edit
+	}
+	return config.Defaults["edit_after"] == "true"
+}
+
+// editorCommand returns the user's editor from $VISUAL or $EDITOR, split
+// into program and arguments (e.g. "code --wait")
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return nil
+}
+
+// editAndRevalidate opens filename in the user's editor and validates it
+// again once the editor exits
+func editAndRevalidate(filename string) error {
+	if strings.HasSuffix(filename, ".gz") {
+		printWarning("Compressed files can't be edited directly; skipping --edit-after")
+		return nil
+	}
+
+	editor := editorCommand()
+	if editor == nil {
+		printHint("Set $EDITOR (or $VISUAL) to open new assignments automatically; edit %s by hand instead", filename)
+		return nil
+	}
+
+	editCmd := exec.Command(editor[0], append(editor[1:], filename)...)
+	editCmd.Stdin = os.Stdin
+	editCmd.Stdout = os.Stdout
+	editCmd.Stderr = os.Stderr
+	if err := editCmd.Run(); err != nil {
+		return failf("Editor %s failed: %v", editor[0], err)
+	}
+
+	pkg, err := toolkit.LoadPackage(filename)
+	if err != nil {
+		return failf("%s no longer loads after editing: %v", filename, err)
+	}
+
+	validation := toolkit.ValidateAssignmentPackage(pkg)
+	if !validation.IsValid {
+		printError("Edited assignment is invalid")
+		for _, err := range validation.Errors {
+			printBullet("%s", err)
+		}
+		printHint("Run 'assignment-toolkit validate %s' after fixing it", filename)
+		return errFailed
+	}
+	printSuccess("Edited assignment is valid (Score: %d/100)", validation.Score)
+	return nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// assignmentFilePatterns match the files treated as assignments
+var assignmentFilePatterns = []string{"*.yaml", "*.yml", "*.yaml.gz", "*.yml.gz", "*.json.gz"}
+
+// isAssignmentFileName reports whether a base name looks like an
+// assignment file; hidden files such as the config are excluded
+func isAssignmentFileName(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return false
+	}
+	for _, pattern := range assignmentFilePatterns {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// assignmentBaseName strips the format and compression extensions, so
+// "quiz.yaml.gz" becomes "quiz"
+func assignmentBaseName(filename string) string {
+	name := strings.TrimSuffix(filename, ".gz")
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// gitSourcePrefix marks an argument as a Git repository rather than a
+// local file, e.g. git+https://example.com/dept/assignments.git
+const gitSourcePrefix = "git+"
+
+// gitSourceUsage explains the git source syntax in command help
+const gitSourceUsage = `A git+https:// (or git+ssh://) URL reads assignments from a shared Git
+repository instead, shallow-cloned to a temporary directory that is removed
+afterwards. Add //path to use a file or folder inside the repository and
+#ref for a branch or tag:
+
+  git+https://example.com/dept/assignments.git//year-7/quiz.yaml#main`
+
+// gitSourceArgs accepts no arguments or a single git+ source
+func gitSourceArgs(cmd *cobra.Command, args []string) error {
+	if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
+		return err
+	}
+	if len(args) == 1 && !isGitSource(args[0]) {
+		return fmt.Errorf("%s reads the current directory; the only argument it takes is a git+https:// source", cmd.Name())
+	}
+	return nil
+}
+
+// gitSource is a parsed git+ argument
+type gitSource struct {
+	URL  string // repository URL passed to git clone
+	Path string // file or folder inside the repository, "" for all of it
+	Ref  string // branch or tag, "" for the default branch
+}
+
+// isGitSource reports whether arg names a Git repository
+func isGitSource(arg string) bool {
+	return strings.HasPrefix(arg, gitSourcePrefix)
+}
+
+// parseGitSource splits git+URL[//path][#ref] into its parts
+func parseGitSource(arg string) (gitSource, error) {
+	rest := strings.TrimPrefix(arg, gitSourcePrefix)
+	var source gitSource
+	if i := strings.LastIndex(rest, "#"); i >= 0 {
+		rest, source.Ref = rest[:i], rest[i+1:]
+	}
+
+	scheme := strings.Index(rest, "://")
+	if scheme < 0 {
+		return source, fmt.Errorf("%s is not a git+https:// or git+ssh:// URL", arg)
+	}
+	if i := strings.Index(rest[scheme+3:], "//"); i >= 0 {
+		split := scheme + 3 + i
+		rest, source.Path = rest[:split], strings.Trim(rest[split+2:], "/")
+	}
+	source.URL = rest
+	return source, nil
+}
+
+// String shows the source with any credentials in the URL hidden
+func (s gitSource) String() string {
+	text := s.URL
+	if parsed, err := url.Parse(s.URL); err == nil {
+		text = parsed.Redacted()
+	}
+	if s.Path != "" {
+		text += "//" + s.Path
+	}
+	if s.Ref != "" {
+		text += "#" + s.Ref
+	}
+	return text
+}
+
+// cloneGitSource shallow-clones the repository into a temporary directory.
+// It returns the clone, the file or folder the source points at inside it,
+// and a func that removes the clone.
+func cloneGitSource(arg string) (string, string, func(), error) {
+	source, err := parseGitSource(arg)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	dir, err := ioutil.TempDir("", "assignment-git-")
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to create temporary directory: %v", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	gitArgs := []string{"clone", "--depth", "1", "--quiet"}
+	if source.Ref != "" {
+		gitArgs = append(gitArgs, "--branch", source.Ref)
+	}
+	gitArgs = append(gitArgs, "--", source.URL, dir)
+
+	clone := exec.Command("git", gitArgs...)
+	// Fail instead of waiting for a password nobody will type
+	clone.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	output, err := clone.CombinedOutput()
+	if err != nil {
+		cleanup()
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", "", nil, fmt.Errorf("git is not installed or not on PATH")
+		}
+		return "", "", nil, fmt.Errorf("git clone of %s failed: %s", source, gitFailure(output, err))
+	}
+
+	target := dir
+	if source.Path != "" {
+		target = filepath.Join(dir, filepath.FromSlash(source.Path))
+		if _, err := os.Stat(target); err != nil {
+			cleanup()
+			return "", "", nil, fmt.Errorf("%s is not in %s", source.Path, source)
+		}
+	}
+	return dir, target, cleanup, nil
+}
+
+// gitFailure picks the explanation out of git's output, which ends with a
+// "fatal:" line when it gives one
+func gitFailure(output []byte, err error) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			return strings.TrimPrefix(line, "fatal: ")
+		}
+	}
+	return err.Error()
+}
+
+// gitSourceFiles returns the assignment files a source points at: the file
+// itself, or every assignment file under the folder
+func gitSourceFiles(target string) ([]string, error) {
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{target}, nil
+	}
+	return findAssignmentFilesRecursive(target)
+}
+
+// relativeTo shows file relative to the clone it came from
+func relativeTo(dir, file string) string {
+	if dir == "" {
+		return file
+	}
+	if rel, err := filepath.Rel(dir, file); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return file
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+// Usage strings shared by the create commands
+const (
+	outputDirUsage = "Save new assignments under this directory instead of output_dir"
+	layoutUsage    = "Organize new assignments into subdirectories such as {quarter}/{type} instead of output_layout"
+)
+
+// layoutPlaceholder matches the {field} placeholders of output_layout
+var layoutPlaceholder = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// layoutFields are the assignment fields output_layout may use
+var layoutFields = map[string]func(toolkit.Assignment) string{
+	"type":       func(a toolkit.Assignment) string { return a.Type },
+	"quarter":    func(a toolkit.Assignment) string { return a.Quarter },
+	"category":   func(a toolkit.Assignment) string { return a.Category },
+	"difficulty": func(a toolkit.Assignment) string { return a.Difficulty },
+}
+
+// expandLayout fills an output layout such as "{quarter}/{type}" from the
+// assignment. Empty fields become "unsorted" so every file gets a folder.
+func expandLayout(layout string, assignment toolkit.Assignment) (string, error) {
+	var unknown []string
+	expanded := layoutPlaceholder.ReplaceAllStringFunc(layout, func(placeholder string) string {
+		name := strings.Trim(placeholder, "{}")
+		field, ok := layoutFields[name]
+		if !ok {
+			unknown = append(unknown, placeholder)
+			return placeholder
+		}
+		value := strings.TrimSpace(field(assignment))
+		if value == "" {
+			return "unsorted"
+		}
+		// Keep each field to one path segment, e.g. "Maths / Algebra"
+		// becomes "Maths-Algebra"
+		return strings.Join(strings.FieldsFunc(value, func(r rune) bool {
+			return r == '/' || r == '\\' || r == ' '
+		}), "-")
+	})
+	if len(unknown) > 0 {
+		return "", fmt.Errorf("unknown field %s in output layout (use {type}, {quarter}, {category}, or {difficulty})", strings.Join(unknown, ", "))
+	}
+	if filepath.IsAbs(expanded) || strings.HasPrefix(filepath.Clean(expanded), "..") {
+		return "", fmt.Errorf("output layout %q must stay inside the output directory", layout)
+	}
+	return expanded, nil
+}
+
+// outputPath is where a new assignment is saved: its file name under
+// --output-dir (or output_dir) and the --layout (or output_layout)
+// subdirectories
+func outputPath(cmd *cobra.Command, config toolkit.Config, assignment toolkit.Assignment) (string, error) {
+	dir := flagOrDefault(cmd, "output-dir", config.OutputDir)
+	layout := flagOrDefault(cmd, "layout", config.OutputLayout)
+	if layout != "" {
+		subdir, err := expandLayout(layout, assignment)
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(dir, subdir)
+	}
+	return filepath.Join(dir, createdFilename(cmd, assignment)), nil
+}
+
+// saveNewPackage saves a new assignment, creating its directories
+func saveNewPackage(pkg toolkit.AssignmentPackage, filename string) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return toolkit.SavePackage(pkg, filename)
+}
+
+// findWorkspaceAssignmentFiles lists the assignments in the current
+// directory and, when output_dir is configured, everything under it
+func findWorkspaceAssignmentFiles() ([]string, error) {
+	files, err := findAssignmentFiles(".")
+	if err != nil {
+		return nil, err
+	}
+
+	outputDir := filepath.Clean(getConfig().OutputDir)
+	if outputDir == "." {
+		return files, nil
+	}
+	if _, err := os.Stat(outputDir); os.IsNotExist(err) {
+		return files, nil
+	}
+	organized, err := findAssignmentFilesRecursive(outputDir)
+	if err != nil {
+		return nil, err
+	}
+	return append(files, organized...), nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+
+	"assignment-toolkit/pkg/toolkit"
+)
+
+func TestExpandLayout(t *testing.T) {
+	assignment := toolkit.Assignment{Type: "multiple-choice", Quarter: "Q1", Category: "Maths / Algebra"}
+
+	for layout, want := range map[string]string{
+		"{quarter}/{type}":       "Q1/multiple-choice",
+		"by-category/{category}": "by-category/Maths-Algebra",
+		"{difficulty}":           "unsorted",
+	} {
+		got, err := expandLayout(layout, assignment)
+		if err != nil || got != want {
+			t.Errorf("expandLayout(%q) = %q, %v; want %q", layout, got, err, want)
+		}
+	}
+
+	for _, layout := range []string{"{author}", "../{type}"} {
+		if _, err := expandLayout(layout, assignment); err == nil {
+			t.Errorf("expandLayout(%q) succeeded, want an error", layout)
+		}
+	}
+}
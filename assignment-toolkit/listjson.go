@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"assignment-toolkit/pkg/toolkit"
+)
+
+// listFields are the fields of a `list --json` entry, in --fields order
+var listFields = []string{"file", "id", "title", "type", "version", "author", "modified", "points", "tags", "archived"}
+
+// listEntry returns every listable field of one assignment
+func listEntry(file string, pkg toolkit.AssignmentPackage) map[string]interface{} {
+	return map[string]interface{}{
+		"file":     file,
+		"id":       pkg.Metadata.ID,
+		"title":    pkg.Assignment.Title,
+		"type":     pkg.Assignment.Type,
+		"version":  pkg.Metadata.Version,
+		"author":   pkg.Metadata.Author,
+		"modified": pkg.Metadata.Modified,
+		"points":   pkg.Assignment.Points,
+		"tags":     pkg.Metadata.Tags,
+		"archived": pkg.Metadata.Archived,
+	}
+}
+
+// parseListFields splits a --fields value, rejecting unknown names
+func parseListFields(value string) ([]string, error) {
+	if value == "" {
+		return listFields, nil
+	}
+
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if !containsString(listFields, field) {
+			return nil, fmt.Errorf("unknown field %q (available: %s)", field, strings.Join(listFields, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// printListJSON prints the assignments as a JSON array holding only the
+// requested fields, naming files relative to root when it is set. Files
+// that fail to load are listed with an "error".
+func printListJSON(files []string, root, fieldList string) error {
+	fields, err := parseListFields(fieldList)
+	if err != nil {
+		return usageErrorf("Invalid --fields: %v", err)
+	}
+
+	entries := make([]map[string]interface{}, 0, len(files))
+	for _, file := range files {
+		pkg, err := toolkit.LoadPackage(file)
+		if err != nil {
+			entries = append(entries, map[string]interface{}{"file": relativeTo(root, file), "error": err.Error()})
+			continue
+		}
+
+		all := listEntry(relativeTo(root, file), pkg)
+		entry := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			entry[field] = all[field]
+		}
+		entries = append(entries, entry)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return failf("Failed to encode listing: %v", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// parseSince reads a --since window: a Go duration such as 90m or 24h, or
+// a number of days such as 7d
+func parseSince(value string) (time.Duration, error) {
+	if days := strings.TrimSuffix(value, "d"); days != value {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("%q is not a number of days", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	window, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if window <= 0 {
+		return 0, fmt.Errorf("%q must be positive", value)
+	}
+	return window, nil
+}
+
+// recentlyModified keeps the files whose Metadata.Modified is after
+// cutoff, newest first. Files that fail to load are left out.
+func recentlyModified(files []string, cutoff time.Time) []string {
+	modified := make(map[string]time.Time)
+	var recent []string
+	for _, file := range files {
+		pkg, err := toolkit.LoadPackage(file)
+		if err != nil || !pkg.Metadata.Modified.After(cutoff) {
+			continue
+		}
+		modified[file] = pkg.Metadata.Modified
+		recent = append(recent, file)
+	}
+
+	sort.SliceStable(recent, func(i, j int) bool {
+		return modified[recent[i]].After(modified[recent[j]])
+	})
+	return recent
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseListFields(t *testing.T) {
+	fields, err := parseListFields("title, type,version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"title", "type", "version"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %q, want %q", fields, want)
+	}
+
+	if _, err := parseListFields("title,grade"); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	cases := map[string]time.Duration{"24h": 24 * time.Hour, "90m": 90 * time.Minute, "7d": 7 * 24 * time.Hour}
+	for value, want := range cases {
+		if got, err := parseSince(value); err != nil || got != want {
+			t.Errorf("parseSince(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"", "d", "-1h", "xd"} {
+		if _, err := parseSince(value); err == nil {
+			t.Errorf("parseSince(%q) should fail", value)
+		}
+	}
+}
@@ -3,7 +3,9 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"assignment-toolkit/pkg/toolkit"
 	"github.com/spf13/cobra"
 )
 
@@ -20,11 +22,118 @@ Features:
 - Export/import assignment bundles
 - Sync with remote LMS
 - Template management`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		config := getConfig()
+		if err := configureOutput(cmd); err != nil {
+			return err
+		}
+		// Warnings would corrupt the suggestions printed for shell completion
+		err := toolkit.GetTypeManager().SetCustomMappings(config.CustomTypes)
+		if err != nil && !isCompletionRequest(cmd) {
+			printWarning("Ignoring custom_types entries: %v", err)
+		}
+		if err := toolkit.GetTypeManager().FileError(); err != nil && !isCompletionRequest(cmd) {
+			printWarning("Ignoring type mappings: %v", err)
+		}
+		if policy := config.NormalizeLineEndings; policy != "" && !containsString(toolkit.LineEndingPolicies, policy) {
+			if !isCompletionRequest(cmd) {
+				printWarning("Ignoring normalize_line_endings %q (use %s)", policy, strings.Join(toolkit.LineEndingPolicies, ", "))
+			}
+			config.NormalizeLineEndings = ""
+		}
+		if normalizeLineEndings {
+			config.NormalizeLineEndings = toolkit.LineEndingsAlways
+		}
+		toolkit.UseConfig(config)
+		toolkit.LoadWarningHandler = func(message string) {
+			printStderrWarning("%s", message)
+		}
+
+		// The command line is valid from here on; commands print their own
+		// errors and report failure through an exit code
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		return nil
+	},
+}
+
+// Exit codes shared by every command so scripts can tell failures apart
+const (
+	exitOK      = 0
+	exitFailure = 1 // invalid input or failed validation
+	exitUsage   = 2 // unknown command, bad flag, or wrong number of arguments
+	exitNetwork = 3 // the LMS could not be reached or returned an error
+)
+
+// exitError ends the program with code. main prints err; when it is nil
+// the command has already explained the failure itself, usually because
+// details such as validation errors follow the message.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit status %d", e.code)
+	}
+	return e.err.Error()
+}
+
+var (
+	errFailed  = &exitError{code: exitFailure}
+	errNetwork = &exitError{code: exitNetwork}
+)
+
+// failf reports invalid input or a failed operation
+func failf(format string, args ...interface{}) error {
+	return &exitError{code: exitFailure, err: fmt.Errorf(format, args...)}
+}
+
+// usageErrorf reports flags that are invalid or can't be combined
+func usageErrorf(format string, args ...interface{}) error {
+	return &exitError{code: exitUsage, err: fmt.Errorf(format, args...)}
+}
+
+// networkErrorf reports a failure talking to the LMS
+func networkErrorf(format string, args ...interface{}) error {
+	return &exitError{code: exitNetwork, err: fmt.Errorf(format, args...)}
+}
+
+// version is the release this binary was built from, set at build time with
+// -ldflags "-X main.version=v1.2.0"
+var version = "dev"
+
+// verboseHTTP logs LMS requests and responses to stderr (--verbose or DEBUG)
+var verboseHTTP bool
+
+// maxBodyLog caps the body bytes shown per logged request or response
+var maxBodyLog int
+
+// normalizeLineEndings turns CRLF into LF as assignments are loaded, not
+// only when they are saved (--normalize-line-endings)
+var normalizeLineEndings bool
+
+func init() {
+	rootCmd.Version = version
+	rootCmd.PersistentFlags().BoolVarP(&verboseHTTP, "verbose", "v", os.Getenv("DEBUG") == "true", "Log LMS requests and responses to stderr (API key redacted)")
+	rootCmd.PersistentFlags().BoolVar(&normalizeLineEndings, "normalize-line-endings", false, "Turn CRLF line breaks into LF when assignments are loaded as well as saved (normalize_line_endings: always)")
+	rootCmd.PersistentFlags().IntVar(&maxBodyLog, "max-body-log", toolkit.DefaultMaxBodyLog, "Maximum body bytes shown per request or response in verbose logs")
 }
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	err := rootCmd.Execute()
+	if err == nil {
+		os.Exit(exitOK)
+	}
+
+	// Anything that isn't an exitError was rejected by cobra while parsing
+	// the command line, which has already printed it with the usage
+	if exit, ok := err.(*exitError); ok {
+		if exit.err != nil {
+			printError("%v", exit.err)
+		}
+		os.Exit(exit.code)
 	}
+	os.Exit(exitUsage)
 }
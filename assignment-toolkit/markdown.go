@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// markdownOption matches "- [ ] text" and "- [x] text" option lines
+var markdownOption = regexp.MustCompile(`^[-*+]\s+\[([ xX])\]\s+(.*)$`)
+
+// markdownQuestionPrefix strips heading markers and list numbering such
+// as "## " or "1. " from question lines
+var markdownQuestionPrefix = regexp.MustCompile(`^(#+\s*|\d+[.)]\s+)`)
+
+// markdownQuestion is a question being assembled by the parser
+type markdownQuestion struct {
+	line        int
+	text        []string
+	options     []string
+	correct     []string
+	explanation []string
+}
+
+// parseMarkdownQuestions reads multiple-choice questions in the Markdown
+// convention used by `create --from-md`:
+//
+//	## What is the capital of France?
+//	- [ ] London
+//	- [x] Paris
+//	> Paris has been the capital since 987.
+//
+// Questions are separated by blank lines. Problems are returned as
+// messages prefixed with their line number.
+func parseMarkdownQuestions(r io.Reader) ([]interface{}, []string, error) {
+	var questions []interface{}
+	var problems []string
+	var current *markdownQuestion
+
+	finish := func() {
+		if current == nil {
+			return
+		}
+		problems = append(problems, current.check()...)
+		questions = append(questions, current.toMap())
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			// A blank line ends a question once it has options
+			if current != nil && len(current.options) > 0 {
+				finish()
+			}
+
+		case markdownOption.MatchString(line):
+			if current == nil {
+				problems = append(problems, fmt.Sprintf("line %d: option appears before any question", lineNumber))
+				continue
+			}
+			if len(current.explanation) > 0 {
+				problems = append(problems, fmt.Sprintf("line %d: option appears after the explanation", lineNumber))
+				continue
+			}
+			match := markdownOption.FindStringSubmatch(line)
+			option := strings.TrimSpace(match[2])
+			if option == "" {
+				problems = append(problems, fmt.Sprintf("line %d: empty option", lineNumber))
+				continue
+			}
+			current.options = append(current.options, option)
+			if match[1] != " " {
+				current.correct = append(current.correct, option)
+			}
+
+		case strings.HasPrefix(line, ">"):
+			if current == nil || len(current.options) == 0 {
+				problems = append(problems, fmt.Sprintf("line %d: explanation must follow a question's options", lineNumber))
+				continue
+			}
+			current.explanation = append(current.explanation, strings.TrimSpace(strings.TrimPrefix(line, ">")))
+
+		default:
+			// Text after options without a blank line starts a new question
+			if current != nil && len(current.options) > 0 {
+				finish()
+			}
+			if current == nil {
+				current = &markdownQuestion{line: lineNumber}
+			}
+			current.text = append(current.text, markdownQuestionPrefix.ReplaceAllString(line, ""))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	finish()
+
+	if len(questions) == 0 && len(problems) == 0 {
+		problems = append(problems, "no questions found")
+	}
+	return questions, problems, nil
+}
+
+// check reports structural problems with a parsed question
+func (q *markdownQuestion) check() []string {
+	var problems []string
+	switch {
+	case len(q.options) < 2:
+		problems = append(problems, fmt.Sprintf("line %d: question needs at least two options", q.line))
+	case len(q.correct) == 0:
+		problems = append(problems, fmt.Sprintf("line %d: no option is marked correct with [x]", q.line))
+	case len(q.correct) > 1:
+		problems = append(problems, fmt.Sprintf("line %d: only one option may be marked correct", q.line))
+	}
+	return problems
+}
+
+// toMap converts the question into the structure the wizard produces
+func (q *markdownQuestion) toMap() map[string]interface{} {
+	question := map[string]interface{}{
+		"question":    strings.Join(q.text, " "),
+		"options":     q.options,
+		"explanation": strings.Join(q.explanation, " "),
+	}
+	if len(q.correct) > 0 {
+		question["correctAnswer"] = q.correct[0]
+	}
+	return question
+}
+
+// loadMarkdownQuestions parses a Markdown question file, folding any parse
+// problems into a single error
+func loadMarkdownQuestions(filename string) ([]interface{}, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", filename, err)
+	}
+	defer file.Close()
+
+	questions, problems, err := parseMarkdownQuestions(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", filename, err)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%s has %d problem(s):\n  %s", filename, len(problems), strings.Join(problems, "\n  "))
+	}
+	return questions, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMarkdownQuestions(t *testing.T) {
+	input := `## What is the capital of France?
+- [ ] London
+- [x] Paris
+> Paris is the capital
+> and largest city.
+
+1. Which planet is largest?
+- [X] Jupiter
+- [ ] Mars
+`
+	questions, problems, err := parseMarkdownQuestions(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) > 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+
+	want := []interface{}{
+		map[string]interface{}{
+			"question":      "What is the capital of France?",
+			"options":       []string{"London", "Paris"},
+			"correctAnswer": "Paris",
+			"explanation":   "Paris is the capital and largest city.",
+		},
+		map[string]interface{}{
+			"question":      "Which planet is largest?",
+			"options":       []string{"Jupiter", "Mars"},
+			"correctAnswer": "Jupiter",
+			"explanation":   "",
+		},
+	}
+	if !reflect.DeepEqual(questions, want) {
+		t.Errorf("questions = %#v, want %#v", questions, want)
+	}
+}
+
+func TestParseMarkdownQuestionsReportsLines(t *testing.T) {
+	input := `- [x] Orphan
+
+What is 2 + 2?
+- [ ] 3
+- [ ] 5
+`
+	_, problems, err := parseMarkdownQuestions(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"line 1: option appears before any question",
+		"line 3: no option is marked correct with [x]",
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("problems = %q, want %q", problems, want)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	normalizeTypesCmd.Flags().Bool("all", false, "Normalize every assignment file in the current directory")
+	normalizeTypesCmd.Flags().String("assume-type", "", "Write this type into files that have none")
+	rootCmd.AddCommand(normalizeTypesCmd)
+}
+
+// Normalize-types command
+var normalizeTypesCmd = &cobra.Command{
+	Use:   "normalize-types [file]",
+	Short: "Rewrite alias and legacy type names to canonical types",
+	Long: `Rewrite assignment types such as "mcq" or the legacy "writing" to their
+canonical portable type names, in place. Unknown types are reported and
+left unchanged. Files with no type at all are given the --assume-type type.`,
+	Args:              fileOrAllArgs,
+	ValidArgsFunction: completeAssignmentFile,
+	RunE:              runNormalizeTypes,
+}
+
+func runNormalizeTypes(cmd *cobra.Command, args []string) error {
+	assumed, err := assumedType(cmd)
+	if err != nil {
+		return err
+	}
+
+	files := args
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		if files, err = findWorkspaceAssignmentFiles(); err != nil {
+			return failf("Error listing files: %v", err)
+		}
+	}
+
+	changed, failed := 0, 0
+	for _, file := range files {
+		pkg, err := toolkit.LoadPackage(file)
+		if err != nil {
+			printError("%s: failed to load: %v", file, err)
+			failed++
+			continue
+		}
+
+		oldType := pkg.Assignment.Type
+		newType, ok := toolkit.GetTypeManager().CanonicalType(oldType)
+		if oldType == "" && assumed != "" {
+			oldType, newType, ok = "(none)", assumed, true
+		}
+		if !ok {
+			printWarning("%s: unknown type %q left unchanged", file, oldType)
+			continue
+		}
+		if newType == oldType {
+			continue
+		}
+
+		pkg.Assignment.Type = newType
+		pkg.Metadata.TypeMapping, _ = toolkit.ResolveTypeMapping(newType)
+		pkg.Metadata.Modified = toolkit.Now()
+		pkg.Metadata.SourceHash = toolkit.CalculateHash(pkg)
+		if err := toolkit.SavePackage(pkg, file); err != nil {
+			printError("%s: failed to save: %v", file, err)
+			failed++
+			continue
+		}
+
+		printMessage(iconSync, "%s: %s %s %s", file, oldType, icon(iconArrow), newType)
+		changed++
+	}
+
+	fmt.Println()
+	fmt.Printf("Normalized %d of %d assignment(s)\n", changed, len(files))
+	if failed > 0 {
+		return errFailed
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Icon identifies a symbol used to decorate user-facing messages
+type Icon int
+
+const (
+	iconSuccess Icon = iota
+	iconError
+	iconWarning
+	iconHint
+	iconInfo
+	iconNote
+	iconSync
+	iconStart
+	iconFolder
+	iconSettings
+	iconDoctor
+	iconBullet
+	iconArrow
+
+	// Type categories shown by 'types'
+	iconQuiz
+	iconWriting
+	iconInteractive
+	iconSpeaking
+	iconCode
+	iconMedia
+	iconSpecialized
+)
+
+// Renderer decides how icons are drawn in terminal output
+type Renderer interface {
+	Name() string
+	Icon(icon Icon) string
+}
+
+// iconTheme is a Renderer backed by a fixed icon table
+type iconTheme struct {
+	name  string
+	icons map[Icon]string
+}
+
+func (t *iconTheme) Name() string { return t.name }
+
+func (t *iconTheme) Icon(icon Icon) string { return t.icons[icon] }
+
+var emojiTheme = &iconTheme{
+	name: "emoji",
+	icons: map[Icon]string{
+		iconSuccess:     "✅",
+		iconError:       "❌",
+		iconWarning:     "⚠️ ",
+		iconHint:        "💡",
+		iconInfo:        "📋",
+		iconNote:        "📝",
+		iconSync:        "🔄",
+		iconStart:       "🚀",
+		iconFolder:      "📁",
+		iconSettings:    "⚙️ ",
+		iconDoctor:      "🩺",
+		iconBullet:      "•",
+		iconArrow:       "→",
+		iconQuiz:        "📝",
+		iconWriting:     "✍️ ",
+		iconInteractive: "🎯",
+		iconSpeaking:    "🗣️ ",
+		iconCode:        "💻",
+		iconMedia:       "📸",
+		iconSpecialized: "🎓",
+	},
+}
+
+// plainTheme uses ASCII only; purely decorative icons are dropped
+var plainTheme = &iconTheme{
+	name: "plain",
+	icons: map[Icon]string{
+		iconSuccess: "[OK]",
+		iconError:   "[ERROR]",
+		iconWarning: "[WARN]",
+		iconHint:    "Tip:",
+		iconBullet:  "-",
+		iconArrow:   "->",
+	},
+}
+
+// renderers holds the themes selectable with --theme
+var renderers = map[string]Renderer{
+	emojiTheme.Name(): emojiTheme,
+	plainTheme.Name(): plainTheme,
+}
+
+// output is the active renderer
+var output Renderer = emojiTheme
+
+// iconColors are the ANSI colors applied to status icons
+var iconColors = map[Icon]string{
+	iconSuccess: "\033[32m", // green
+	iconError:   "\033[31m", // red
+	iconWarning: "\033[33m", // yellow
+	iconHint:    "\033[36m", // cyan
+}
+
+const colorReset = "\033[0m"
+
+// colorEnabled reports whether status icons are colorized
+var colorEnabled = false
+
+func init() {
+	rootCmd.PersistentFlags().String("theme", "", "Output theme: "+strings.Join(rendererNames(), ", ")+" (env ASSIGNMENT_TOOLKIT_THEME)")
+	rootCmd.PersistentFlags().Bool("no-emoji", false, "Use plain ASCII output (same as --theme plain)")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colored output (env NO_COLOR)")
+}
+
+// configureOutput selects the renderer and color mode from flags or the
+// environment
+func configureOutput(cmd *cobra.Command) error {
+	noColor, _ := cmd.Flags().GetBool("no-color")
+	colorEnabled = !noColor && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb" && isTerminal(os.Stdout)
+
+	theme := os.Getenv("ASSIGNMENT_TOOLKIT_THEME")
+	if flagTheme, _ := cmd.Flags().GetString("theme"); flagTheme != "" {
+		theme = flagTheme
+	}
+	if noEmoji, _ := cmd.Flags().GetBool("no-emoji"); noEmoji {
+		theme = plainTheme.Name()
+	}
+	if theme == "" {
+		return nil
+	}
+
+	renderer, exists := renderers[strings.ToLower(strings.TrimSpace(theme))]
+	if !exists {
+		return fmt.Errorf("unknown theme %q (available: %s)", theme, strings.Join(rendererNames(), ", "))
+	}
+	output = renderer
+	return nil
+}
+
+func rendererNames() []string {
+	var names []string
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isTerminal reports whether f is an interactive terminal rather than a
+// pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorize wraps text in the icon's color when color is enabled
+func colorize(i Icon, text string) string {
+	color, exists := iconColors[i]
+	if !colorEnabled || !exists || text == "" {
+		return text
+	}
+	return color + text + colorReset
+}
+
+// icon returns the active theme's symbol for an icon
+func icon(i Icon) string {
+	return output.Icon(i)
+}
+
+// printMessage prints a line prefixed with the given icon
+func printMessage(i Icon, format string, args ...interface{}) {
+	fmt.Println(iconLine(i, fmt.Sprintf(format, args...)))
+}
+
+// iconLine prefixes message with the given icon
+func iconLine(i Icon, message string) string {
+	if prefix := icon(i); prefix != "" {
+		message = colorize(i, prefix) + " " + message
+	}
+	return message
+}
+
+func printSuccess(format string, args ...interface{}) {
+	printMessage(iconSuccess, format, args...)
+}
+
+func printError(format string, args ...interface{}) {
+	printMessage(iconError, format, args...)
+}
+
+func printWarning(format string, args ...interface{}) {
+	printMessage(iconWarning, format, args...)
+}
+
+// printStderrWarning prints a warning to stderr, where it can't corrupt
+// output meant for other programs such as --json listings
+func printStderrWarning(format string, args ...interface{}) {
+	fmt.Fprintln(os.Stderr, iconLine(iconWarning, fmt.Sprintf(format, args...)))
+}
+
+func printHint(format string, args ...interface{}) {
+	printMessage(iconHint, format, args...)
+}
+
+// printBullet prints an indented list item
+func printBullet(format string, args ...interface{}) {
+	fmt.Printf("  %s %s\n", icon(iconBullet), fmt.Sprintf(format, args...))
+}
+
+// fitColumn shortens s to at most width characters for a table column,
+// ending it with "..." when cut. It counts runes, as fmt's padding does.
+func fitColumn(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-3]) + "..."
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"assignment-toolkit/pkg/toolkit"
+)
+
+// runPatchSync updates selected fields of an assignment that already
+// exists in the LMS, without re-uploading it or its resources. The fields
+// are those named in only, or with changed, those that differ from the
+// LMS copy (limited to only when both are given).
+func runPatchSync(config toolkit.Config, pkg toolkit.AssignmentPackage, only []string, changed bool) error {
+	lmsID := toolkit.LMSAssignmentID(pkg)
+	if lmsID == "" {
+		printError("This assignment isn't in the LMS yet, so there is nothing to update")
+		printHint("Sync it in full first, without --only or --changed; that records its LMS ID in custom.%s", toolkit.LMSIDKey)
+		return errFailed
+	}
+
+	client, err := newLMSClientFromConfig(config)
+	if err != nil {
+		return failf("Invalid sync configuration: %v", err)
+	}
+
+	payload, err := client.LMSPayload(pkg)
+	if err != nil {
+		return failf("Failed to build the LMS payload: %v", err)
+	}
+
+	// A field missing from the payload is sent as null to clear it, which
+	// is only right when the assignment doesn't set it, not when sync_fields
+	// or a payload transform leaves it out
+	converted := toolkit.ConvertToLMSFormat(pkg)
+	var fields []string
+	for _, name := range only {
+		field, ok := toolkit.PayloadKey(strings.TrimSpace(name))
+		if !ok {
+			return usageErrorf("Unknown field %q for --only", name)
+		}
+		key := client.FieldMapping.Key(field)
+		_, set := converted[field]
+		if _, sent := payload[key]; !config.SyncFields.Allows(field) || (set && !sent) {
+			return usageErrorf("%s is left out of the payload by sync_fields or payload_transforms, so --only can't update it", name)
+		}
+		fields = append(fields, key)
+	}
+
+	if changed {
+		remote, err := client.GetAssignment(lmsID)
+		if err != nil {
+			return networkErrorf("Failed to fetch assignment %s: %v", lmsID, err)
+		}
+		differing := toolkit.ChangedFields(payload, remote)
+		if len(fields) > 0 {
+			var limited []string
+			for _, field := range differing {
+				if containsString(fields, field) {
+					limited = append(limited, field)
+				}
+			}
+			differing = limited
+		}
+		fields = differing
+	}
+
+	if len(fields) == 0 {
+		printSuccess("Assignment %s is already up to date", lmsID)
+		return nil
+	}
+
+	printMessage(iconSync, "Updating %s: %s", lmsID, strings.Join(fields, ", "))
+	result, err := client.PatchAssignment(lmsID, toolkit.SelectFields(payload, fields))
+	if err != nil {
+		if result != nil && len(result.Conflicts) > 0 {
+			printError("Update rejected: the assignment conflicts with existing LMS content")
+			printConflicts(result)
+			return errFailed
+		}
+		return networkErrorf("Update failed: %v", err)
+	}
+
+	printSuccess("Updated %d field(s)", len(fields))
+	fmt.Printf("   Assignment ID: %s\n", result.AssignmentID)
+	return nil
+}
+
+// recordLMSID stores the ID of the LMS assignment a sync created in the
+// file's custom.lms_id, so later syncs update that assignment and --only
+// and --changed can patch it. The file is reloaded so nothing the sync
+// filled in for itself is saved with it.
+func recordLMSID(file, id string) {
+	pkg, err := toolkit.LoadPackage(file)
+	if err == nil && toolkit.LMSAssignmentID(pkg) == id {
+		return
+	}
+	if err == nil {
+		if pkg.Metadata.Custom == nil {
+			pkg.Metadata.Custom = make(map[string]string)
+		}
+		pkg.Metadata.Custom[toolkit.LMSIDKey] = id
+		err = toolkit.SavePackage(pkg, file)
+	}
+	if err != nil {
+		printWarning("Failed to record the LMS assignment ID in %s: %v", file, err)
+	}
+}
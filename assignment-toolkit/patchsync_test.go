@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+
+	"assignment-toolkit/pkg/toolkit"
+)
+
+func TestSyncRecordsLMSIDForChanged(t *testing.T) {
+	var mu sync.Mutex
+	var stored map[string]interface{}
+	var patched map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/auth/me":
+			fmt.Fprint(w, `{}`)
+		case r.Method == "GET" && r.URL.Path == "/api/assignments":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == "POST" && r.URL.Path == "/api/assignments":
+			json.NewDecoder(r.Body).Decode(&stored)
+			fmt.Fprint(w, `{"assignment":{"id":"asg-1"}}`)
+		case r.Method == "GET" && r.URL.Path == "/api/assignments/asg-1":
+			json.NewEncoder(w).Encode(map[string]interface{}{"assignment": stored})
+		case r.Method == "PATCH" && r.URL.Path == "/api/assignments/asg-1":
+			json.NewDecoder(r.Body).Decode(&patched)
+			fmt.Fprint(w, `{}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(dir) })
+	config := fmt.Sprintf("lms_endpoint: %s\napi_key: key\n", server.URL)
+	if err := ioutil.WriteFile(".assignment-config.yaml", []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pkg := toolkit.AssignmentPackage{Assignment: toolkit.Assignment{Title: "Quiz", Type: "essay", Points: 10}}
+	pkg.Metadata.SourceHash = toolkit.CalculateHash(pkg)
+	if err := toolkit.SavePackage(pkg, "quiz.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runSync(syncCmd, []string{"quiz.yaml"}); err != nil {
+		t.Fatal(err)
+	}
+	pkg, err = toolkit.LoadPackage("quiz.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := toolkit.LMSAssignmentID(pkg); id != "asg-1" {
+		t.Fatalf("lms_id = %q after sync, want asg-1", id)
+	}
+
+	pkg.Assignment.Points = 20
+	if err := toolkit.SavePackage(pkg, "quiz.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	syncCmd.Flags().Set("changed", "true")
+	t.Cleanup(func() { syncCmd.Flags().Set("changed", "false") })
+	if err := runSync(syncCmd, []string{"quiz.yaml"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]interface{}{"points": float64(20)}; !reflect.DeepEqual(patched, want) {
+		t.Errorf("PATCH body = %v, want %v", patched, want)
+	}
+
+	// A field sync_fields leaves out must not be sent as null
+	config += "sync_fields:\n  deny: [points]\n"
+	if err := ioutil.WriteFile(".assignment-config.yaml", []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	patched = nil
+	if err := runPatchSync(getConfig(), pkg, []string{"points"}, false); err == nil || patched != nil {
+		t.Errorf("--only points with points denied: err = %v, PATCH body = %v", err, patched)
+	}
+}
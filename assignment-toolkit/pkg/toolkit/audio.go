@@ -0,0 +1,203 @@
+package toolkit
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Resource.Metadata keys filled in by HydrateAudioMetadata
+const (
+	MetaAudioDuration = "duration_seconds"
+	MetaAudioFormat   = "audio_format"
+	MetaAudioCodec    = "audio_codec"
+)
+
+// AudioInfo describes an audio file. Duration is 0 when it couldn't be
+// determined.
+type AudioInfo struct {
+	Duration float64
+	Format   string
+	Codec    string
+}
+
+// ProbeAudio reads an audio file's duration, container format, and codec.
+// ffprobe is used when it is installed; otherwise WAV headers are parsed
+// directly and other formats only report the format from the extension.
+func ProbeAudio(path string) (AudioInfo, error) {
+	if _, err := os.Stat(path); err != nil {
+		return AudioInfo{}, err
+	}
+	if ffprobe, err := exec.LookPath("ffprobe"); err == nil {
+		if info, err := probeWithFFprobe(ffprobe, path); err == nil {
+			return info, nil
+		}
+	}
+
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if format != "wav" {
+		return AudioInfo{Format: format}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return AudioInfo{}, err
+	}
+	defer file.Close()
+	return probeWAV(file)
+}
+
+func probeWithFFprobe(ffprobe, path string) (AudioInfo, error) {
+	out, err := exec.Command(ffprobe, "-v", "error",
+		"-show_entries", "format=duration,format_name:stream=codec_name",
+		"-select_streams", "a:0", "-of", "json", path).Output()
+	if err != nil {
+		return AudioInfo{}, err
+	}
+
+	var probe struct {
+		Streams []struct {
+			CodecName string `json:"codec_name"`
+		} `json:"streams"`
+		Format struct {
+			FormatName string `json:"format_name"`
+			Duration   string `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return AudioInfo{}, err
+	}
+
+	info := AudioInfo{Format: probe.Format.FormatName}
+	info.Duration, _ = strconv.ParseFloat(probe.Format.Duration, 64)
+	if len(probe.Streams) > 0 {
+		info.Codec = probe.Streams[0].CodecName
+	}
+	return info, nil
+}
+
+// probeWAV reads the fmt and data chunks of a RIFF/WAVE file
+func probeWAV(r io.Reader) (AudioInfo, error) {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return AudioInfo{}, fmt.Errorf("not a WAV file: %v", err)
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return AudioInfo{}, fmt.Errorf("not a WAV file")
+	}
+
+	info := AudioInfo{Format: "wav"}
+	var byteRate uint32
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return info, nil // no data chunk; duration unknown
+		}
+		id, size := string(chunk[0:4]), binary.LittleEndian.Uint32(chunk[4:8])
+
+		switch id {
+		case "fmt ":
+			data := make([]byte, size)
+			if _, err := io.ReadFull(r, data); err != nil || size < 16 {
+				return info, fmt.Errorf("corrupt WAV fmt chunk")
+			}
+			info.Codec = wavCodec(binary.LittleEndian.Uint16(data[0:2]), binary.LittleEndian.Uint16(data[14:16]))
+			byteRate = binary.LittleEndian.Uint32(data[8:12])
+		case "data":
+			if byteRate > 0 {
+				info.Duration = float64(size) / float64(byteRate)
+			}
+			return info, nil
+		default:
+			if _, err := io.CopyN(io.Discard, r, int64(size)); err != nil {
+				return info, nil
+			}
+		}
+		if size%2 == 1 { // chunks are padded to an even size
+			io.CopyN(io.Discard, r, 1)
+		}
+	}
+}
+
+// wavCodec names a WAV format tag the way ffprobe does
+func wavCodec(formatTag, bitsPerSample uint16) string {
+	switch formatTag {
+	case 1:
+		return fmt.Sprintf("pcm_s%dle", bitsPerSample)
+	case 3:
+		return fmt.Sprintf("pcm_f%dle", bitsPerSample)
+	case 6:
+		return "pcm_alaw"
+	case 7:
+		return "pcm_mulaw"
+	default:
+		return fmt.Sprintf("wav format %d", formatTag)
+	}
+}
+
+// IsAudioResource reports whether a resource is an audio file, by its type
+// or its file extension
+func IsAudioResource(resource Resource) bool {
+	if resource.LocalPath == "" {
+		return false
+	}
+	return resource.Type == "audio" || strings.HasPrefix(mime.TypeByExtension(filepath.Ext(resource.LocalPath)), "audio/")
+}
+
+// HydrateAudioMetadata probes the local audio resources of pkg and records
+// their duration, format, and codec in Resource.Metadata. It returns the
+// resources that couldn't be probed.
+func HydrateAudioMetadata(pkg *AssignmentPackage) map[string]error {
+	failed := make(map[string]error)
+	for i, resource := range pkg.Resources {
+		if !IsAudioResource(resource) {
+			continue
+		}
+
+		info, err := ProbeAudio(resource.LocalPath)
+		if err != nil {
+			failed[resource.LocalPath] = err
+			continue
+		}
+
+		if pkg.Resources[i].Metadata == nil {
+			pkg.Resources[i].Metadata = make(map[string]string)
+		}
+		metadata := pkg.Resources[i].Metadata
+		if info.Duration > 0 {
+			metadata[MetaAudioDuration] = strconv.FormatFloat(info.Duration, 'f', 1, 64)
+		}
+		if info.Format != "" {
+			metadata[MetaAudioFormat] = info.Format
+		}
+		if info.Codec != "" {
+			metadata[MetaAudioCodec] = info.Codec
+		}
+	}
+	return failed
+}
+
+// AudioSummary describes a hydrated audio resource for display, e.g.
+// "1:05, wav (pcm_s16le)". It is empty for resources without audio
+// metadata.
+func AudioSummary(resource Resource) string {
+	var parts []string
+	if seconds, err := strconv.ParseFloat(resource.Metadata[MetaAudioDuration], 64); err == nil {
+		total := int(seconds + 0.5)
+		parts = append(parts, fmt.Sprintf("%d:%02d", total/60, total%60))
+	}
+	if format := resource.Metadata[MetaAudioFormat]; format != "" {
+		if codec := resource.Metadata[MetaAudioCodec]; codec != "" {
+			format += " (" + codec + ")"
+		}
+		parts = append(parts, format)
+	}
+	return strings.Join(parts, ", ")
+}
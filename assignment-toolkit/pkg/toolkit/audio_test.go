@@ -0,0 +1,69 @@
+package toolkit
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// wavFile builds a mono 16-bit PCM WAV holding seconds of silence at 8kHz
+func wavFile(seconds int) []byte {
+	const sampleRate, bytesPerSample = 8000, 2
+	dataSize := uint32(seconds * sampleRate * bytesPerSample)
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, 36+dataSize)
+	buf.WriteString("WAVEfmt ")
+	binary.Write(&buf, binary.LittleEndian, []uint32{16})
+	binary.Write(&buf, binary.LittleEndian, []uint16{1, 1})
+	binary.Write(&buf, binary.LittleEndian, []uint32{sampleRate, sampleRate * bytesPerSample})
+	binary.Write(&buf, binary.LittleEndian, []uint16{bytesPerSample, 16})
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, dataSize)
+	buf.Write(make([]byte, dataSize))
+	return buf.Bytes()
+}
+
+func TestProbeWAV(t *testing.T) {
+	info, err := probeWAV(bytes.NewReader(wavFile(65)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Duration != 65 || info.Format != "wav" || info.Codec != "pcm_s16le" {
+		t.Errorf("probeWAV = %+v, want 65s wav pcm_s16le", info)
+	}
+
+	if _, err := probeWAV(bytes.NewReader([]byte("ID3 not a wav file"))); err == nil {
+		t.Error("probeWAV accepted a non-WAV file")
+	}
+}
+
+func TestHydrateAudioMetadata(t *testing.T) {
+	dir := t.TempDir()
+	audio := filepath.Join(dir, "prompt.wav")
+	if err := ioutil.WriteFile(audio, wavFile(3), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pkg := AssignmentPackage{Resources: []Resource{
+		{Title: "Prompt", Type: "audio", LocalPath: audio},
+		{Title: "Worksheet", Type: "document", LocalPath: filepath.Join(dir, "worksheet.pdf")},
+		{Title: "Missing", Type: "audio", LocalPath: filepath.Join(dir, "missing.mp3")},
+	}}
+	failed := HydrateAudioMetadata(&pkg)
+
+	if len(failed) != 1 || failed[pkg.Resources[2].LocalPath] == nil {
+		t.Errorf("failed = %v, want only the missing file", failed)
+	}
+	if pkg.Resources[1].Metadata != nil {
+		t.Errorf("non-audio resource was probed: %v", pkg.Resources[1].Metadata)
+	}
+	// ffprobe, when installed, names the format itself
+	if got := AudioSummary(pkg.Resources[0]); !strings.HasPrefix(got, "0:03") {
+		t.Errorf("AudioSummary = %q, want it to start with 0:03", got)
+	}
+}
@@ -0,0 +1,331 @@
+package toolkit
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// A bundle is a zip archive holding several assignments and the files they
+// use, for handing a whole unit to a colleague:
+//
+//	manifest.yaml
+//	assignments/fractions-quiz.yaml
+//	assignments/fractions-essay.yaml
+//	resources/number-line.png
+//
+// Resources shared by several assignments are stored once. Bundled
+// assignments refer to their files as resources/<name>, so extracting a
+// bundle into a workspace leaves every local path valid.
+
+// BundleManifestFile is the manifest at the root of every bundle
+const BundleManifestFile = "manifest.yaml"
+
+// BundleManifest lists the contents of a bundle
+type BundleManifest struct {
+	Created     time.Time     `json:"created" yaml:"created"`
+	Assignments []BundleEntry `json:"assignments" yaml:"assignments"`
+	Resources   []string      `json:"resources,omitempty" yaml:"resources,omitempty"`
+}
+
+// BundleEntry describes one assignment in a bundle
+type BundleEntry struct {
+	File      string   `json:"file" yaml:"file"`
+	ID        string   `json:"id" yaml:"id"`
+	Title     string   `json:"title" yaml:"title"`
+	Type      string   `json:"type" yaml:"type"`
+	Version   string   `json:"version" yaml:"version"`
+	Resources []string `json:"resources,omitempty" yaml:"resources,omitempty"`
+}
+
+// WriteBundle writes the assignment files to a bundle at output. Identical
+// resource files (by SHA-256) are stored once; different files that share
+// a name are stored under a checksum prefix.
+func WriteBundle(output string, files []string) (BundleManifest, error) {
+	manifest := BundleManifest{Created: Now()}
+
+	out, err := os.Create(output)
+	if err != nil {
+		return manifest, err
+	}
+	writer := zip.NewWriter(out)
+
+	err = writeBundleEntries(writer, files, &manifest)
+	if err == nil {
+		err = writeBundleManifest(writer, manifest)
+	}
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(output)
+	}
+	return manifest, err
+}
+
+func writeBundleEntries(writer *zip.Writer, files []string, manifest *BundleManifest) error {
+	stored := make(map[string]string) // checksum -> bundle path
+	names := make(map[string]bool)
+
+	addResource := func(file string) (string, error) {
+		checksum, err := FileChecksum(file)
+		if err != nil {
+			return "", err
+		}
+		if name, ok := stored[checksum]; ok {
+			return name, nil
+		}
+
+		name := path.Join("resources", filepath.Base(file))
+		if names[name] {
+			name = path.Join("resources", checksum[:8]+"-"+filepath.Base(file))
+		}
+		if err := addZipFile(writer, name, file); err != nil {
+			return "", err
+		}
+		stored[checksum] = name
+		names[name] = true
+		manifest.Resources = append(manifest.Resources, name)
+		return name, nil
+	}
+
+	for _, file := range files {
+		pkg, err := LoadPackage(file)
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+
+		entry := BundleEntry{
+			ID:      pkg.Metadata.ID,
+			Title:   pkg.Assignment.Title,
+			Type:    pkg.Assignment.Type,
+			Version: pkg.Metadata.Version,
+		}
+
+		resourceIDs := make(map[string]bool, len(pkg.Resources))
+		for i, resource := range pkg.Resources {
+			resourceIDs[resource.ID] = true
+			if resource.LocalPath == "" {
+				continue
+			}
+			name, err := addResource(resource.LocalPath)
+			if err != nil {
+				return fmt.Errorf("%s: resource %s: %v", file, resource.Title, err)
+			}
+			pkg.Resources[i].LocalPath = name
+			entry.Resources = append(entry.Resources, name)
+		}
+		var mediaErr error
+		visitQuestionMedia(pkg.Assignment.Questions, func(item map[string]interface{}, kind string) {
+			ref := item[kind].(string)
+			if mediaErr != nil || resourceIDs[ref] {
+				return
+			}
+			name, err := addResource(ref)
+			if err != nil {
+				mediaErr = fmt.Errorf("%s: question %s: %v", file, kind, err)
+				return
+			}
+			item[kind] = name
+			entry.Resources = append(entry.Resources, name)
+		})
+		rewriteInstructionImages(&pkg.Assignment, func(ref string) string {
+			if mediaErr != nil || resourceIDs[ref] {
+				return ref
+			}
+			name, err := addResource(ref)
+			if err != nil {
+				mediaErr = fmt.Errorf("%s: instructions image: %v", file, err)
+				return ref
+			}
+			entry.Resources = append(entry.Resources, name)
+			return name
+		})
+		if mediaErr != nil {
+			return mediaErr
+		}
+
+		base := strings.TrimSuffix(filepath.Base(file), ".gz")
+		base = strings.TrimSuffix(base, filepath.Ext(base))
+		entry.File = path.Join("assignments", base+".yaml")
+		for i := 2; names[entry.File]; i++ {
+			entry.File = path.Join("assignments", fmt.Sprintf("%s-%d.yaml", base, i))
+		}
+
+		data, err := encodePackage(pkg, entry.File)
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		if err := addZipData(writer, entry.File, data); err != nil {
+			return err
+		}
+		names[entry.File] = true
+		manifest.Assignments = append(manifest.Assignments, entry)
+	}
+	return nil
+}
+
+func writeBundleManifest(writer *zip.Writer, manifest BundleManifest) error {
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return addZipData(writer, BundleManifestFile, data)
+}
+
+// createZipEntry starts a compressed entry stamped with the current time
+func createZipEntry(writer *zip.Writer, name string) (io.Writer, error) {
+	return writer.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: Now()})
+}
+
+func addZipData(writer *zip.Writer, name string, data []byte) error {
+	w, err := createZipEntry(writer, name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func addZipFile(writer *zip.Writer, name, file string) error {
+	src, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	w, err := createZipEntry(writer, name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, src)
+	return err
+}
+
+// IsBundle reports whether archive is a bundle, as opposed to a zip of a
+// single package directory
+func IsBundle(archive string) (bool, error) {
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return false, err
+	}
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		if file.Name == BundleManifestFile {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// bundleManifest reads the manifest of an open bundle
+func bundleManifest(reader *zip.Reader) (BundleManifest, error) {
+	var manifest BundleManifest
+	for _, file := range reader.File {
+		if file.Name != BundleManifestFile {
+			continue
+		}
+		src, err := file.Open()
+		if err != nil {
+			return manifest, err
+		}
+		defer src.Close()
+
+		data, err := ioutil.ReadAll(src)
+		if err != nil {
+			return manifest, err
+		}
+		if err := yaml.Unmarshal(data, &manifest); err != nil {
+			return manifest, fmt.Errorf("%s is corrupt: %v", BundleManifestFile, err)
+		}
+		return manifest, nil
+	}
+	return manifest, fmt.Errorf("not a bundle: %s is missing", BundleManifestFile)
+}
+
+// ExtractBundle unpacks a bundle into the workspace dest: assignments go in
+// dest itself and resources in dest/resources. Nothing is written if an
+// existing file would be replaced, unless overwrite is set; resource files
+// identical to the existing copy are skipped either way.
+func ExtractBundle(archive, dest string, overwrite bool) (BundleManifest, error) {
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return BundleManifest{}, err
+	}
+	defer reader.Close()
+
+	manifest, err := bundleManifest(&reader.Reader)
+	if err != nil {
+		return manifest, err
+	}
+
+	targets := make(map[*zip.File]string)
+	var conflicts []string
+	for _, file := range reader.File {
+		if file.Name == BundleManifestFile || file.FileInfo().IsDir() {
+			continue
+		}
+
+		name := filepath.FromSlash(strings.TrimPrefix(file.Name, "assignments/"))
+		if filepath.IsAbs(name) || strings.HasPrefix(filepath.Clean(name), "..") {
+			return manifest, fmt.Errorf("bundle entry %q escapes the destination", file.Name)
+		}
+		target := filepath.Join(dest, name)
+
+		if _, err := os.Stat(target); err == nil {
+			if strings.HasPrefix(file.Name, "resources/") && sameZipContents(file, target) {
+				continue
+			}
+			if !overwrite {
+				conflicts = append(conflicts, target)
+				continue
+			}
+		}
+		targets[file] = target
+	}
+	if len(conflicts) > 0 {
+		return manifest, fmt.Errorf("would overwrite existing files: %s", strings.Join(conflicts, ", "))
+	}
+
+	for _, file := range reader.File {
+		if target, ok := targets[file]; ok {
+			if err := extractZipFile(file, target); err != nil {
+				return manifest, err
+			}
+		}
+	}
+	return manifest, nil
+}
+
+// sameZipContents reports whether an archive entry matches a file on disk
+func sameZipContents(file *zip.File, target string) bool {
+	existing, err := FileChecksum(target)
+	if err != nil {
+		return false
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return false
+	}
+	defer src.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, src); err != nil {
+		return false
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)) == existing
+}
@@ -0,0 +1,66 @@
+package toolkit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	src, dest := t.TempDir(), t.TempDir()
+	shared := filepath.Join(src, "map.png")
+	other := filepath.Join(src, "other", "map.png")
+	os.MkdirAll(filepath.Dir(other), 0755)
+	ioutil.WriteFile(shared, []byte("shared"), 0644)
+	ioutil.WriteFile(other, []byte("different"), 0644)
+
+	var files []string
+	for _, title := range []string{"Quiz One", "Quiz Two"} {
+		pkg := AssignmentPackage{
+			Assignment: Assignment{Title: title, Type: "multiple-choice"},
+			Resources:  []Resource{{ID: "map", Title: "Map", LocalPath: shared}},
+		}
+		if title == "Quiz Two" {
+			pkg.Resources = append(pkg.Resources, Resource{ID: "map2", Title: "Other map", LocalPath: other})
+		}
+		file := filepath.Join(src, strings.ToLower(strings.ReplaceAll(title, " ", "-"))+".yaml")
+		if err := SavePackage(pkg, file); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+
+	archive := filepath.Join(t.TempDir(), "unit.zip")
+	manifest, err := WriteBundle(archive, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Assignments) != 2 || len(manifest.Resources) != 2 {
+		t.Fatalf("manifest = %+v, want 2 assignments and 2 resources", manifest)
+	}
+
+	if _, err := ExtractBundle(archive, dest, false); err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := LoadPackage(filepath.Join(dest, "quiz-two.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, resource := range pkg.Resources {
+		if _, err := os.Stat(filepath.Join(dest, resource.LocalPath)); err != nil {
+			t.Errorf("resource %s not extracted: %v", resource.LocalPath, err)
+		}
+	}
+	if pkg.Resources[0].LocalPath == pkg.Resources[1].LocalPath {
+		t.Errorf("different files with the same name share %s", pkg.Resources[0].LocalPath)
+	}
+
+	if _, err := ExtractBundle(archive, dest, false); err == nil {
+		t.Error("expected extracting twice to refuse to overwrite")
+	}
+	if _, err := ExtractBundle(archive, dest, true); err != nil {
+		t.Errorf("overwrite: %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+package toolkit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// gzipMagic starts every gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// packageFormat returns the serialization format implied by a file name,
+// ignoring a trailing .gz: "json" or "yaml"
+func packageFormat(filename string) string {
+	if filepath.Ext(strings.TrimSuffix(filename, ".gz")) == ".json" {
+		return "json"
+	}
+	return "yaml"
+}
+
+// isCompressedFile reports whether the file name asks for gzip compression
+func isCompressedFile(filename string) bool {
+	return strings.HasSuffix(filename, ".gz")
+}
+
+// SavePackage writes pkg to filename. The format follows the extension
+// (.yaml, .yml, or .json) and a trailing .gz compresses the file. Line
+// endings are normalized to LF unless normalize_line_endings is "off"; a
+// source hash that was current is recalculated to match. Questions and
+// custom metadata are shared with the caller, so their text is normalized
+// in place.
+func SavePackage(pkg AssignmentPackage, filename string) error {
+	if normalizeOnSave() {
+		hashed := pkg.Metadata.SourceHash != "" && pkg.Metadata.SourceHash == CalculateHash(pkg)
+		if NormalizeLineEndings(&pkg) && hashed {
+			pkg.Metadata.SourceHash = CalculateHash(pkg)
+		}
+	}
+
+	data, err := encodePackage(pkg, filename)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+// LoadPackage reads an assignment package, detecting JSON and gzip
+// content from the file name and data. A file that does not parse gives a
+// *ParseError naming the line and the likely cause. A UTF-8 BOM is
+// ignored; invalid UTF-8 and content in the wrong format for the
+// extension are reported to LoadWarningHandler. With
+// normalize_line_endings set to "always", CRLF line breaks become LF.
+func LoadPackage(filename string) (AssignmentPackage, error) {
+	var pkg AssignmentPackage
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return pkg, err
+	}
+
+	err = decodePackage(data, filename, &pkg)
+	if err != nil {
+		return pkg, err
+	}
+
+	// yaml.v2 decodes nested maps as map[interface{}]interface{}, which
+	// encoding/json cannot marshal, so convert free-form fields up front
+	pkg.Assignment.Questions = normalizeYAMLValue(pkg.Assignment.Questions)
+	pkg.Assignment.CodeSubmissionConfig = normalizeYAMLValue(pkg.Assignment.CodeSubmissionConfig)
+
+	if normalizeOnLoad() {
+		NormalizeLineEndings(&pkg)
+	}
+	return pkg, nil
+}
+
+// LoadQuestions reads a YAML or JSON file of questions: either a list of
+// questions or a document with a questions key, such as an assignment
+func LoadQuestions(filename string) (interface{}, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if isCompressedFile(filename) {
+		if data, err = gunzipBytes(data); err != nil {
+			return nil, err
+		}
+	}
+	if data, err = decodeText(filename, data); err != nil {
+		return nil, err
+	}
+
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, yamlParseError(filename, data, err)
+	}
+	document = normalizeYAMLValue(document)
+
+	if questions, ok := document.([]interface{}); ok {
+		return questions, nil
+	}
+	if m, ok := document.(map[string]interface{}); ok {
+		if questions, ok := m["questions"].([]interface{}); ok {
+			return questions, nil
+		}
+		if assignment, ok := m["assignment"].(map[string]interface{}); ok {
+			if questions, ok := assignment["questions"].([]interface{}); ok {
+				return questions, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%s has no list of questions", filename)
+}
+
+// normalizeYAMLValue recursively converts YAML-decoded maps into
+// map[string]interface{} so the value can be JSON encoded
+func normalizeYAMLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[fmt.Sprintf("%v", key)] = normalizeYAMLValue(item)
+		}
+		return result
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeYAMLValue(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeYAMLValue(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// CalculateHash returns the SHA-256 of the assignment content, used to
+// detect duplicates on the LMS
+func CalculateHash(pkg AssignmentPackage) string {
+	data, _ := json.Marshal(pkg.Assignment)
+	hash := sha256.Sum256(data)
+	return fmt.Sprintf("%x", hash)
+}
+
+// encodePackage serializes pkg in the format and compression implied by filename
+func encodePackage(pkg AssignmentPackage, filename string) ([]byte, error) {
+	var data []byte
+	var err error
+	if packageFormat(filename) == "json" {
+		data, err = json.MarshalIndent(pkg, "", "  ")
+	} else {
+		data, err = yaml.Marshal(pkg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if isCompressedFile(filename) {
+		return gzipBytes(data)
+	}
+	return data, nil
+}
+
+// LoadWarningHandler receives the problems found in files that still
+// load, such as invalid UTF-8 or JSON saved with a .yaml extension. Nothing
+// is reported while it is nil.
+var LoadWarningHandler func(message string)
+
+func loadWarning(format string, args ...interface{}) {
+	if LoadWarningHandler != nil {
+		LoadWarningHandler(fmt.Sprintf(format, args...))
+	}
+}
+
+// decodePackage parses data, transparently decompressing gzip content.
+// Syntax and type errors are returned as a *ParseError. Content in the
+// other format from the one the extension implies is read anyway, with a
+// warning: YAML parses JSON already, and a .json file that isn't JSON is
+// read as YAML.
+func decodePackage(data []byte, filename string, pkg *AssignmentPackage) error {
+	var err error
+	if bytes.HasPrefix(data, gzipMagic) {
+		if data, err = gunzipBytes(data); err != nil {
+			return err
+		}
+	}
+	if data, err = decodeText(filename, data); err != nil {
+		return err
+	}
+
+	extension := filepath.Ext(strings.TrimSuffix(filename, ".gz"))
+	if packageFormat(filename) == "json" {
+		jsonErr := json.Unmarshal(data, pkg)
+		if jsonErr == nil {
+			return nil
+		}
+		if !json.Valid(data) && looksLikeYAMLDocument(data) && yaml.Unmarshal(data, pkg) == nil {
+			loadWarning("%s: this %s file contains YAML; rename it to .yaml", filename, extension)
+			return nil
+		}
+		return jsonParseError(filename, data, jsonErr)
+	}
+	if err := yaml.Unmarshal(data, pkg); err != nil {
+		return yamlParseError(filename, data, err)
+	}
+	if looksLikeJSON(data) {
+		loadWarning("%s: this %s file contains JSON; rename it to .json", filename, extension)
+	}
+	return nil
+}
+
+// looksLikeJSON reports whether data is a JSON object or array
+func looksLikeJSON(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed)
+}
+
+// looksLikeYAMLDocument reports whether data is a YAML mapping, the shape
+// of an assignment file, rather than JSON with a syntax error
+func looksLikeYAMLDocument(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] == '{' || trimmed[0] == '[' {
+		return false
+	}
+	var document map[string]interface{}
+	return yaml.Unmarshal(data, &document) == nil && len(document) > 0
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gunzipBytes(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
@@ -0,0 +1,174 @@
+package toolkit
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompressedPackageRoundTrip(t *testing.T) {
+	useFixedClock(t)
+	original, err := LoadPackage(filepath.Join("testdata", "packages", "matching-geography.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"quiz.yaml.gz", "quiz.json.gz", "quiz.json"} {
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), name)
+			if err := SavePackage(original, filename); err != nil {
+				t.Fatal(err)
+			}
+
+			loaded, err := LoadPackage(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _ := json.Marshal(ConvertToLMSFormat(loaded))
+			want, _ := json.Marshal(ConvertToLMSFormat(original))
+			if string(got) != string(want) {
+				t.Errorf("payload changed after round trip through %s", name)
+			}
+		})
+	}
+}
+
+func TestLoadPackageReportsParseErrors(t *testing.T) {
+	cases := []struct {
+		name, content string
+		line          int
+		hint          string
+	}{
+		{"tabs.yaml", "assignment:\n  title: Quiz\n\ttype: essay\n", 3, "tabs"},
+		{"colon.yaml", "assignment:\n  title: Quiz: part 1\n", 2, "quotes"},
+		{"points.yaml", "assignment:\n  title: Quiz\n  points: lots\n", 3, "number"},
+		{"broken.json", "{\n  \"assignment\": {\n    \"title\": \"Quiz\",\n  }\n}\n", 4, "comma"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), tc.name)
+			if err := ioutil.WriteFile(filename, []byte(tc.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadPackage(filename)
+			parseErr, ok := err.(*ParseError)
+			if !ok {
+				t.Fatalf("expected a *ParseError, got %T: %v", err, err)
+			}
+			if parseErr.File != filename || parseErr.Line != tc.line {
+				t.Errorf("got %s line %d, want %s line %d", parseErr.File, parseErr.Line, filename, tc.line)
+			}
+			if !strings.Contains(parseErr.Hint, tc.hint) {
+				t.Errorf("hint %q does not mention %q", parseErr.Hint, tc.hint)
+			}
+		})
+	}
+}
+
+func TestSavePackageNormalizesLineEndings(t *testing.T) {
+	for _, policy := range []string{"", LineEndingsOff, LineEndingsAlways} {
+		t.Run("policy "+policy, func(t *testing.T) {
+			pkg := AssignmentPackage{
+				Metadata: PackageMetadata{Custom: map[string]string{"note": "one\r\ntwo"}},
+				Assignment: Assignment{
+					Title:        "Essay",
+					Instructions: "Read\r\nthen\rwrite",
+					Questions:    []interface{}{map[string]interface{}{"question": "Why?\r\n", "options": []interface{}{"a\r\nb"}}},
+				},
+			}
+			pkg.Metadata.SourceHash = CalculateHash(pkg)
+			UseConfig(Config{NormalizeLineEndings: policy})
+			t.Cleanup(func() { UseConfig(Config{}) })
+
+			filename := filepath.Join(t.TempDir(), "essay.json")
+			if err := SavePackage(pkg, filename); err != nil {
+				t.Fatal(err)
+			}
+			data, err := ioutil.ReadFile(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := strings.Contains(string(data), `\r`), policy == LineEndingsOff; got != want {
+				t.Errorf("saved file contains CR: %v, want %v", got, want)
+			}
+
+			loaded, err := LoadPackage(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if policy != LineEndingsOff {
+				if loaded.Assignment.Instructions != "Read\nthen\nwrite" || loaded.Metadata.Custom["note"] != "one\ntwo" {
+					t.Errorf("text not normalized: %q, %q", loaded.Assignment.Instructions, loaded.Metadata.Custom["note"])
+				}
+				if loaded.Metadata.SourceHash != CalculateHash(loaded) {
+					t.Error("source hash no longer matches the normalized content")
+				}
+			}
+		})
+	}
+}
+
+func TestLoadPackageHandlesEncodingProblems(t *testing.T) {
+	var warnings []string
+	LoadWarningHandler = func(message string) { warnings = append(warnings, message) }
+	defer func() { LoadWarningHandler = nil }()
+
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	bom := write("bom.json", append([]byte("\xEF\xBB\xBF"), `{"assignment":{"title":"Quiz"}}`...))
+	if pkg, err := LoadPackage(bom); err != nil || pkg.Assignment.Title != "Quiz" || len(warnings) != 0 {
+		t.Errorf("BOM file: title %q, err %v, warnings %v", pkg.Assignment.Title, err, warnings)
+	}
+
+	// "ภาษา" in TIS-620, as a Thai editor may save it
+	legacy := write("thai.yaml", []byte("assignment:\n  title: \"\xC0\xD2\xC9\xD2\"\n"))
+	pkg, err := LoadPackage(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.Assignment.Title != "\uFFFD" || len(warnings) != 1 || !strings.Contains(warnings[0], "thai.yaml: invalid UTF-8 at byte 22 (line 2, column 11)") {
+		t.Errorf("TIS-620 file: title %q, warnings %v", pkg.Assignment.Title, warnings)
+	}
+
+	utf16 := write("utf16.yaml", []byte("\xFF\xFEa\x00"))
+	if _, err := LoadPackage(utf16); err == nil || !strings.Contains(err.Error(), "UTF-16") {
+		t.Errorf("UTF-16 file: err = %v, want a UTF-16 error", err)
+	}
+}
+
+func TestLoadPackageWarnsWhenContentDoesNotMatchExtension(t *testing.T) {
+	var warnings []string
+	LoadWarningHandler = func(message string) { warnings = append(warnings, message) }
+	defer func() { LoadWarningHandler = nil }()
+
+	dir := t.TempDir()
+	jsonInYAML := filepath.Join(dir, "quiz.yaml")
+	yamlInJSON := filepath.Join(dir, "essay.json")
+	ioutil.WriteFile(jsonInYAML, []byte(`{"assignment":{"title":"Quiz"}}`), 0644)
+	ioutil.WriteFile(yamlInJSON, []byte("assignment:\n  title: Essay\n"), 0644)
+
+	for file, title := range map[string]string{jsonInYAML: "Quiz", yamlInJSON: "Essay"} {
+		pkg, err := LoadPackage(file)
+		if err != nil || pkg.Assignment.Title != title {
+			t.Errorf("%s: title %q, err %v", file, pkg.Assignment.Title, err)
+		}
+	}
+	if len(warnings) != 2 || !strings.Contains(strings.Join(warnings, "\n"), "this .yaml file contains JSON") || !strings.Contains(strings.Join(warnings, "\n"), "this .json file contains YAML") {
+		t.Errorf("warnings = %v", warnings)
+	}
+
+	broken := filepath.Join(dir, "broken.json")
+	ioutil.WriteFile(broken, []byte(`{"assignment": {"title": "Quiz",}}`), 0644)
+	if _, err := LoadPackage(broken); err == nil {
+		t.Error("expected broken JSON to fail rather than load as YAML")
+	}
+}
@@ -0,0 +1,122 @@
+package toolkit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KnownProgrammingLanguages are the programmingLanguage values accepted
+// without a warning
+var KnownProgrammingLanguages = []string{
+	"c", "cpp", "csharp", "go", "html", "java", "javascript", "kotlin",
+	"php", "python", "r", "ruby", "rust", "scala", "scratch", "sql",
+	"swift", "typescript",
+}
+
+// codeSubmissionConfig returns the code_submission_config map, or nil
+func codeSubmissionConfig(pkg AssignmentPackage) map[string]interface{} {
+	config, _ := pkg.Assignment.CodeSubmissionConfig.(map[string]interface{})
+	return config
+}
+
+// legacyCodeSubmissionConfig returns the settings older create wizards
+// stored under questions instead of code_submission_config, or nil
+func legacyCodeSubmissionConfig(pkg AssignmentPackage) map[string]interface{} {
+	if pkg.Assignment.Type != "code-submission" || pkg.Assignment.CodeSubmissionConfig != nil {
+		return nil
+	}
+	config, _ := pkg.Assignment.Questions.(map[string]interface{})
+	if _, ok := config["programmingLanguage"]; !ok {
+		return nil
+	}
+	return config
+}
+
+// MigrateCodeSubmissionConfig moves code submission settings stored under
+// questions by older create wizards to code_submission_config, where sync
+// sends them. It reports whether anything changed.
+func MigrateCodeSubmissionConfig(pkg *AssignmentPackage) bool {
+	config := legacyCodeSubmissionConfig(*pkg)
+	if config == nil {
+		return false
+	}
+	pkg.Assignment.CodeSubmissionConfig = config
+	pkg.Assignment.Questions = nil
+	return true
+}
+
+// checkCodeSubmissionConfig requires a language and positive file limits
+func checkCodeSubmissionConfig(pkg AssignmentPackage) []string {
+	if legacyCodeSubmissionConfig(pkg) != nil {
+		return []string{"code_submission_config is under questions (older create wizards put it there) and won't be synced; run 'validate --fix' to move it"}
+	}
+	if pkg.Assignment.CodeSubmissionConfig == nil {
+		return []string{"Code submission assignments need a code_submission_config"}
+	}
+	config := codeSubmissionConfig(pkg)
+	if config == nil {
+		return []string{"code_submission_config must be a map of settings"}
+	}
+
+	var findings []string
+	if language, _ := config["programmingLanguage"].(string); strings.TrimSpace(language) == "" {
+		findings = append(findings, "code_submission_config.programmingLanguage is required")
+	}
+	for _, key := range []string{"maxFiles", "maxFileSizeMb"} {
+		value, ok := config[key]
+		if !ok {
+			continue
+		}
+		if n, isNumber := numberValue(value); !isNumber || n <= 0 {
+			findings = append(findings, fmt.Sprintf("code_submission_config.%s must be a positive number (got %v)", key, value))
+		}
+	}
+	return findings
+}
+
+// checkProgrammingLanguage warns about languages outside
+// KnownProgrammingLanguages, which the LMS may not be able to run
+func checkProgrammingLanguage(pkg AssignmentPackage) []string {
+	language, _ := codeSubmissionConfig(pkg)["programmingLanguage"].(string)
+	language = strings.ToLower(strings.TrimSpace(language))
+	if language == "" || containsFold(KnownProgrammingLanguages, language) {
+		return nil
+	}
+	return []string{fmt.Sprintf("Unknown programming language %q (known: %s)", language, strings.Join(KnownProgrammingLanguages, ", "))}
+}
+
+// checkExpectedOutput warns when an auto-graded submission has nothing to
+// be graded against
+func checkExpectedOutput(pkg AssignmentPackage) []string {
+	config := codeSubmissionConfig(pkg)
+	if !pkg.Assignment.AutoGrade || config == nil {
+		return nil
+	}
+	if expected, _ := config["expectedOutput"].(string); strings.TrimSpace(expected) == "" {
+		return []string{"Auto-graded code submissions should set code_submission_config.expectedOutput"}
+	}
+	return nil
+}
+
+// numberValue returns a YAML or JSON number as a float64
+func numberValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package toolkit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckCodeSubmissionConfig(t *testing.T) {
+	pkg := AssignmentPackage{Assignment: Assignment{
+		Type: "code-submission",
+		CodeSubmissionConfig: map[string]interface{}{
+			"programmingLanguage": " ",
+			"maxFiles":            0,
+			"maxFileSizeMb":       2.5,
+		},
+	}}
+
+	want := []string{
+		"code_submission_config.programmingLanguage is required",
+		"code_submission_config.maxFiles must be a positive number (got 0)",
+	}
+	if got := checkCodeSubmissionConfig(pkg); !reflect.DeepEqual(got, want) {
+		t.Errorf("findings = %q, want %q", got, want)
+	}
+
+	pkg.Assignment.CodeSubmissionConfig = nil
+	if got := checkCodeSubmissionConfig(pkg); len(got) != 1 {
+		t.Errorf("missing config: findings = %q, want one", got)
+	}
+}
+
+func TestCheckExpectedOutput(t *testing.T) {
+	pkg := AssignmentPackage{Assignment: Assignment{
+		Type:                 "code-submission",
+		AutoGrade:            true,
+		CodeSubmissionConfig: map[string]interface{}{"programmingLanguage": "python", "expectedOutput": ""},
+	}}
+	if got := checkExpectedOutput(pkg); len(got) != 1 {
+		t.Errorf("findings = %q, want a warning for the empty expected output", got)
+	}
+
+	pkg.Assignment.AutoGrade = false
+	if got := checkExpectedOutput(pkg); len(got) != 0 {
+		t.Errorf("manually graded: findings = %q, want none", got)
+	}
+}
+
+func TestMigrateCodeSubmissionConfig(t *testing.T) {
+	settings := map[string]interface{}{"programmingLanguage": "python", "maxFiles": 5}
+	pkg := AssignmentPackage{Assignment: Assignment{Type: "code-submission", Questions: settings}}
+
+	if got := checkCodeSubmissionConfig(pkg); len(got) != 1 || !strings.Contains(got[0], "validate --fix") {
+		t.Errorf("findings = %q, want one naming validate --fix", got)
+	}
+	if !MigrateCodeSubmissionConfig(&pkg) {
+		t.Fatal("MigrateCodeSubmissionConfig reported no change")
+	}
+	if pkg.Assignment.Questions != nil || !reflect.DeepEqual(pkg.Assignment.CodeSubmissionConfig, settings) {
+		t.Errorf("assignment = %+v, want the settings moved to code_submission_config", pkg.Assignment)
+	}
+	if got := checkCodeSubmissionConfig(pkg); len(got) != 0 {
+		t.Errorf("after migrating: findings = %q, want none", got)
+	}
+	if MigrateCodeSubmissionConfig(&pkg) {
+		t.Error("second MigrateCodeSubmissionConfig reported a change")
+	}
+}
@@ -0,0 +1,18 @@
+package toolkit
+
+import "time"
+
+// Now returns the current time. Everything that stamps Created, Modified,
+// ValidatedAt, or importedAt goes through it so tests can substitute a
+// fixed clock.
+var Now = time.Now
+
+// activeConfig holds the workspace settings consulted by validation rules
+// and payload transforms
+var activeConfig Config
+
+// UseConfig sets the workspace configuration used by validation and
+// payload transforms, e.g. allowed quarters and payload fields
+func UseConfig(config Config) {
+	activeConfig = config
+}
@@ -0,0 +1,131 @@
+package toolkit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// trueFalseOptions are the options a true/false question becomes
+var trueFalseOptions = []interface{}{"True", "False"}
+
+// writtenLMSTypes are the LMS types answered with free text; an assignment
+// can move between them without touching its questions
+var writtenLMSTypes = []string{"writing", "writing-long"}
+
+// typeConversion restructures the questions of an assignment for another
+// LMS type, returning a note for each change or an error when a question
+// doesn't fit
+type typeConversion func(assignment *Assignment) ([]string, error)
+
+// typeConversions are the mechanical transforms between LMS types whose
+// questions are shaped differently, keyed by "from>to"
+var typeConversions = map[string]typeConversion{
+	"true-false>multiple-choice": trueFalseToMultipleChoice,
+	"multiple-choice>true-false": multipleChoiceToTrueFalse,
+}
+
+// ConvertAssignmentType changes the type of pkg to target, stored under its
+// canonical name. Types that share an LMS type (quiz and multiple-choice,
+// essay and writing-long) and written types convert as they are; true/false
+// and multiple-choice questions are restructured. Anything else is
+// refused. The returned notes describe what changed.
+func ConvertAssignmentType(pkg *AssignmentPackage, target string) ([]string, error) {
+	manager := GetTypeManager()
+	targetType, ok := manager.CanonicalType(target)
+	if !ok {
+		return nil, fmt.Errorf("unknown assignment type %q", target)
+	}
+	if pkg.Assignment.Type == "" {
+		return nil, fmt.Errorf("the assignment has no type to convert from")
+	}
+	from, err := manager.ResolveType(pkg.Assignment.Type)
+	if err != nil {
+		return nil, err
+	}
+	to, _ := manager.ResolveType(targetType)
+
+	var notes []string
+	switch {
+	case from.LMSType == to.LMSType && from.LMSSubtype == to.LMSSubtype:
+		// Same question structure; only the stored name changes
+	case containsFold(writtenLMSTypes, from.LMSType) && containsFold(writtenLMSTypes, to.LMSType):
+		// Both are free-text responses
+	default:
+		convert, exists := typeConversions[from.LMSType+">"+to.LMSType]
+		if !exists {
+			return nil, fmt.Errorf("%s can't be converted to %s: their questions are structured differently and there is no mechanical conversion", pkg.Assignment.Type, targetType)
+		}
+		if notes, err = convert(&pkg.Assignment); err != nil {
+			return nil, fmt.Errorf("%s can't be converted to %s: %v", pkg.Assignment.Type, targetType, err)
+		}
+	}
+
+	if containsFold(writtenLMSTypes, to.LMSType) && pkg.Assignment.AutoGrade {
+		pkg.Assignment.AutoGrade = false
+		notes = append(notes, "turned off auto_grade, which written assignments can't use")
+	}
+	pkg.Assignment.Type = targetType
+	return notes, nil
+}
+
+// trueFalseToMultipleChoice gives every question True and False options,
+// keeping the correct answer
+func trueFalseToMultipleChoice(assignment *Assignment) ([]string, error) {
+	questions := questionMaps(assignment.Questions)
+	for i, question := range questions {
+		answer, ok := trueFalseAnswer(question["correctAnswer"])
+		if !ok {
+			return nil, fmt.Errorf("question %d has no true or false correctAnswer", i+1)
+		}
+		question["options"] = append([]interface{}{}, trueFalseOptions...)
+		question["correctAnswer"] = "False"
+		if answer {
+			question["correctAnswer"] = "True"
+		}
+	}
+	return []string{fmt.Sprintf("added True/False options to %d question(s)", len(questions))}, nil
+}
+
+// multipleChoiceToTrueFalse drops the options of questions that only offer
+// True and False, making the correct answer a boolean
+func multipleChoiceToTrueFalse(assignment *Assignment) ([]string, error) {
+	questions := questionMaps(assignment.Questions)
+	for i, question := range questions {
+		options := questionOptions(question)
+		if len(options) != 2 || !isTrueFalsePair(OptionText(options[0]), OptionText(options[1])) {
+			return nil, fmt.Errorf("question %d offers options other than True and False", i+1)
+		}
+		if _, ok := trueFalseAnswer(question["correctAnswer"]); !ok {
+			return nil, fmt.Errorf("question %d has no True or False correctAnswer", i+1)
+		}
+	}
+
+	for _, question := range questions {
+		answer, _ := trueFalseAnswer(question["correctAnswer"])
+		delete(question, "options")
+		question["correctAnswer"] = answer
+	}
+	return []string{fmt.Sprintf("replaced the True/False options of %d question(s) with a true/false answer", len(questions))}, nil
+}
+
+// trueFalseAnswer reads a correct answer given as a boolean or as the
+// text "true" or "false"
+func trueFalseAnswer(value interface{}) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+	}
+	return false, false
+}
+
+func isTrueFalsePair(first, second string) bool {
+	first, second = strings.ToLower(strings.TrimSpace(first)), strings.ToLower(strings.TrimSpace(second))
+	return (first == "true" && second == "false") || (first == "false" && second == "true")
+}
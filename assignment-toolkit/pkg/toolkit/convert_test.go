@@ -0,0 +1,40 @@
+package toolkit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConvertAssignmentType(t *testing.T) {
+	pkg := AssignmentPackage{Assignment: Assignment{Type: "tf", Questions: []interface{}{
+		map[string]interface{}{"question": "The sky is blue", "correctAnswer": true},
+		map[string]interface{}{"question": "Fish fly", "correctAnswer": "False"},
+	}}}
+
+	if _, err := ConvertAssignmentType(&pkg, "mcq"); err != nil {
+		t.Fatal(err)
+	}
+	first := questionMaps(pkg.Assignment.Questions)[0]
+	if pkg.Assignment.Type != "multiple-choice" || first["correctAnswer"] != "True" || !reflect.DeepEqual(first["options"], trueFalseOptions) {
+		t.Errorf("after converting to multiple-choice: type %q, question %v", pkg.Assignment.Type, first)
+	}
+
+	if _, err := ConvertAssignmentType(&pkg, "true-false"); err != nil {
+		t.Fatal(err)
+	}
+	second := questionMaps(pkg.Assignment.Questions)[1]
+	if _, hasOptions := second["options"]; hasOptions || second["correctAnswer"] != false {
+		t.Errorf("after converting back: %v", second)
+	}
+
+	essay := AssignmentPackage{Assignment: Assignment{Type: "essay", AutoGrade: true}}
+	notes, err := ConvertAssignmentType(&essay, "writing-short")
+	if err != nil || essay.Assignment.Type != "writing-short" || essay.Assignment.AutoGrade || len(notes) != 1 {
+		t.Errorf("essay to writing-short: type %q, auto_grade %v, notes %v, err %v", essay.Assignment.Type, essay.Assignment.AutoGrade, notes, err)
+	}
+
+	if _, err := ConvertAssignmentType(&essay, "matching"); err == nil || !strings.Contains(err.Error(), "no mechanical conversion") {
+		t.Errorf("essay to matching: err = %v, want a refusal", err)
+	}
+}
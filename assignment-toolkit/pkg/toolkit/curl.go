@@ -0,0 +1,46 @@
+package toolkit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SyncCurl returns a curl command that repeats the request SyncAssignment
+// would send for pkg, and the request body, which the command reads from
+// payloadFile. The API key is redacted, so paste it in before running the
+// command. Signed requests carry a signature made now, which the LMS will
+// only accept for as long as it accepts the timestamp.
+func (c *LMSClient) SyncCurl(pkg AssignmentPackage, payloadFile string) (string, []byte, error) {
+	req, body, err := c.syncRequest(pkg)
+	if err != nil {
+		return "", nil, err
+	}
+	if c.Signing.Enabled() {
+		if err := c.Signing.signRequest(req); err != nil {
+			return "", nil, err
+		}
+	}
+
+	lines := []string{fmt.Sprintf("curl -X %s %s", req.Method, shellQuote(redactSecret(req.URL.String(), c.APIKey)))}
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := strings.Join(req.Header[name], ", ")
+		if strings.EqualFold(name, "Authorization") {
+			value = redactAuthorization(value)
+		}
+		lines = append(lines, "-H "+shellQuote(name+": "+redactSecret(value, c.APIKey)))
+	}
+	lines = append(lines, "--data-binary "+shellQuote("@"+payloadFile))
+
+	return strings.Join(lines, " \\\n  "), body, nil
+}
+
+// shellQuote quotes text for a POSIX shell
+func shellQuote(text string) string {
+	return "'" + strings.Replace(text, "'", `'\''`, -1) + "'"
+}
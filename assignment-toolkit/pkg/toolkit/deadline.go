@@ -0,0 +1,65 @@
+package toolkit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// requestDeadline is the earlier of the batch deadline and the deadline of
+// the resource being uploaded, or zero when neither is set
+func (c *LMSClient) requestDeadline() time.Time {
+	deadline := c.Deadline
+	if !c.resourceDeadline.IsZero() && (deadline.IsZero() || c.resourceDeadline.Before(deadline)) {
+		deadline = c.resourceDeadline
+	}
+	return deadline
+}
+
+// expired reports whether a deadline is set and has been reached
+func expired(deadline time.Time) bool {
+	return !deadline.IsZero() && !time.Now().Before(deadline)
+}
+
+// withDeadline attaches the client's deadline to req. The returned cancel
+// func must be called once the response body is no longer needed.
+func (c *LMSClient) withDeadline(req *http.Request) (*http.Request, context.CancelFunc) {
+	deadline := c.requestDeadline()
+	if deadline.IsZero() {
+		return req, func() {}
+	}
+	ctx, cancel := context.WithDeadline(req.Context(), deadline)
+	return req.WithContext(ctx), cancel
+}
+
+// cancelOnClose releases a request's deadline when its response body is
+// closed, so callers can read the body after do returns
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// uploadResourceWithin uploads one resource, cancelling it once
+// ResourceTimeout has passed
+func (c *LMSClient) uploadResourceWithin(assignmentID string, resource Resource) (string, error) {
+	if c.ResourceTimeout <= 0 {
+		return c.uploadResource(assignmentID, resource)
+	}
+
+	c.resourceDeadline = time.Now().Add(c.ResourceTimeout)
+	defer func() { c.resourceDeadline = time.Time{} }()
+
+	resourceID, err := c.uploadResource(assignmentID, resource)
+	if err != nil && expired(c.resourceDeadline) && !expired(c.Deadline) {
+		return "", fmt.Errorf("timed out after %s", c.ResourceTimeout)
+	}
+	return resourceID, err
+}
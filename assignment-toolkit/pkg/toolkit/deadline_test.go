@@ -0,0 +1,66 @@
+package toolkit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUploadResourcesTimesOutStuckUpload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "worksheet.pdf")
+	if err := ioutil.WriteFile(path, []byte("%PDF"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		<-r.Context().Done() // never answers
+	}))
+	defer server.Close()
+
+	client := NewLMSClient(server.URL, "key")
+	client.ResourceTimeout = 50 * time.Millisecond
+
+	start := time.Now()
+	_, _, err := client.uploadResources("a-1", []Resource{{Title: "Worksheet", LocalPath: path}})
+	if err == nil || !strings.Contains(err.Error(), "timed out after 50ms") {
+		t.Errorf("err = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("upload took %s after its timeout", elapsed)
+	}
+}
+
+func TestBatchSyncAssignmentsSkipsPastDeadline(t *testing.T) {
+	var synced int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		synced++
+		fmt.Fprintf(w, `{"assignment":{"id":"a-%d"}}`, synced)
+	}))
+	defer server.Close()
+
+	client := NewLMSClient(server.URL, "key")
+	client.Deadline = time.Now().Add(-time.Second)
+
+	packages := []AssignmentPackage{
+		{Assignment: Assignment{Title: "Quiz 1", Type: "multiple-choice"}},
+		{Assignment: Assignment{Title: "Quiz 2", Type: "multiple-choice"}},
+	}
+	result, err := client.BatchSyncAssignments(packages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if synced != 0 || result.SuccessCount != 0 || result.SkippedCount != 2 {
+		t.Errorf("synced %d, result = %+v; want both skipped", synced, result)
+	}
+	for _, r := range result.Results {
+		if r.Status != "skipped" {
+			t.Errorf("status = %q, want skipped", r.Status)
+		}
+	}
+}
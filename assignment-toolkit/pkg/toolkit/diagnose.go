@@ -0,0 +1,131 @@
+package toolkit
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+)
+
+// Stages at which a connection test can fail
+const (
+	StageDNS     = "dns"     // the host name could not be resolved
+	StageConnect = "connect" // the host could not be reached
+	StageTLS     = "tls"     // the TLS handshake failed
+	StageAuth    = "auth"    // the LMS rejected the API key
+	StagePath    = "path"    // the endpoint URL does not point at the LMS API
+	StageServer  = "server"  // the LMS answered with another error
+)
+
+// ConnectionDiagnostics describes what happened while contacting the LMS.
+// Stage is empty when the connection succeeded.
+type ConnectionDiagnostics struct {
+	URL        string
+	Host       string
+	Addresses  []string
+	TLS        bool
+	TLSVersion string
+	StatusCode int
+	Stage      string
+	Err        error
+}
+
+// DiagnoseConnection contacts the LMS the same way TestConnection does and
+// reports each step: name resolution, TLS handshake and response status
+func (c *LMSClient) DiagnoseConnection() *ConnectionDiagnostics {
+	endpoint := fmt.Sprintf("%s/api/auth/me", c.BaseURL)
+	diag := &ConnectionDiagnostics{URL: endpoint}
+
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		diag.Stage = StageConnect
+		diag.Err = fmt.Errorf("invalid endpoint URL: %v", err)
+		return diag
+	}
+	diag.Host = parsed.Hostname()
+	diag.TLS = parsed.Scheme == "https"
+
+	var dnsErr, connectErr, tlsErr error
+	trace := &httptrace.ClientTrace{
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			dnsErr = info.Err
+			for _, addr := range info.Addrs {
+				diag.Addresses = append(diag.Addresses, addr.String())
+			}
+		},
+		ConnectDone: func(network, addr string, err error) {
+			if err != nil {
+				connectErr = err
+			}
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			tlsErr = err
+			if err == nil {
+				diag.TLSVersion = tlsVersionName(state.Version)
+			}
+		},
+	}
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		diag.Stage = StageConnect
+		diag.Err = fmt.Errorf("failed to create request: %v", err)
+		return diag
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+
+	resp, err := c.do(req)
+	if err != nil {
+		switch {
+		case dnsErr != nil:
+			diag.Stage = StageDNS
+			diag.Err = fmt.Errorf("can't reach host: cannot resolve %s: %v", diag.Host, dnsErr)
+		case tlsErr != nil:
+			diag.Stage = StageTLS
+			diag.Err = fmt.Errorf("TLS handshake with %s failed: %v", diag.Host, tlsErr)
+		case connectErr != nil:
+			diag.Stage = StageConnect
+			diag.Err = fmt.Errorf("can't reach host: connection to %s failed: %v", parsed.Host, connectErr)
+		default:
+			diag.Stage = StageConnect
+			diag.Err = fmt.Errorf("failed to connect to LMS: %v", err)
+		}
+		return diag
+	}
+	defer resp.Body.Close()
+	diag.StatusCode = resp.StatusCode
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		diag.Stage = StageAuth
+		diag.Err = fmt.Errorf("authentication failed - check your API key")
+	case resp.StatusCode == http.StatusNotFound:
+		diag.Stage = StagePath
+		diag.Err = fmt.Errorf("wrong path: %s was not found - check the endpoint URL", parsed.Path)
+	default:
+		body, _ := ioutil.ReadAll(resp.Body)
+		diag.Stage = StageServer
+		diag.Err = fmt.Errorf("LMS returned error (%d): %s", resp.StatusCode, redactSecret(string(body), c.APIKey))
+	}
+
+	return diag
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	default:
+		return fmt.Sprintf("TLS 0x%04x", version)
+	}
+}
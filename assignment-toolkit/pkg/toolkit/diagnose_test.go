@@ -0,0 +1,45 @@
+package toolkit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDiagnoseConnectionStages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path != "/api/auth/me":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Header.Get("Authorization") != "Bearer good-key":
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	tests := []struct {
+		name    string
+		baseURL string
+		apiKey  string
+		stage   string
+	}{
+		{"ok", server.URL, "good-key", ""},
+		{"bad key", server.URL, "bad-key", StageAuth},
+		{"wrong path", server.URL + "/lms", "good-key", StagePath},
+		{"unreachable", closedURL, "good-key", StageConnect},
+	}
+
+	for _, tt := range tests {
+		diag := NewLMSClient(tt.baseURL, tt.apiKey).DiagnoseConnection()
+		if diag.Stage != tt.stage {
+			t.Errorf("%s: stage = %q, want %q (err: %v)", tt.name, diag.Stage, tt.stage, diag.Err)
+		}
+		if (diag.Err == nil) != (tt.stage == "") {
+			t.Errorf("%s: err = %v", tt.name, diag.Err)
+		}
+	}
+}
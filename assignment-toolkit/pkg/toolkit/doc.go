@@ -0,0 +1,6 @@
+// Package toolkit is the library behind the assignment-toolkit CLI. It
+// defines the portable assignment format and provides loading and saving,
+// type mapping, validation, conversion to the LMS payload format, and an
+// LMS API client, so other Go programs can work with portable assignments
+// without going through the command line.
+package toolkit
@@ -0,0 +1,80 @@
+package toolkit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxObjectiveLength is the longest learning objective, in characters,
+// accepted without a warning
+const MaxObjectiveLength = 200
+
+// checkEducationalMetadata flags blank, padded, duplicated, and overlong
+// learning objectives and prerequisites
+func checkEducationalMetadata(pkg AssignmentPackage) []string {
+	findings := checkMetadataList("Learning objective", pkg.Assignment.LearningObjectives)
+	for i, objective := range pkg.Assignment.LearningObjectives {
+		if length := len([]rune(strings.TrimSpace(objective))); length > MaxObjectiveLength {
+			findings = append(findings, fmt.Sprintf("Learning objective %d is %d characters long (limit %d); shorten or split it", i+1, length, MaxObjectiveLength))
+		}
+	}
+	return append(findings, checkMetadataList("Prerequisite", pkg.Assignment.Prerequisites)...)
+}
+
+func checkMetadataList(label string, entries []string) []string {
+	var findings []string
+	seen := make(map[string]bool)
+	for i, entry := range entries {
+		trimmed := strings.TrimSpace(entry)
+		switch {
+		case trimmed == "":
+			findings = append(findings, fmt.Sprintf("%s %d is empty", label, i+1))
+			continue
+		case trimmed != entry:
+			findings = append(findings, fmt.Sprintf("%s %d has leading or trailing spaces", label, i+1))
+		}
+
+		key := strings.ToLower(trimmed)
+		if seen[key] {
+			findings = append(findings, fmt.Sprintf("%s %q is listed more than once", label, trimmed))
+		}
+		seen[key] = true
+	}
+	return findings
+}
+
+// CleanEducationalMetadata trims learning objectives and prerequisites and
+// drops empty and duplicate entries, keeping the first of each. Overlong
+// objectives are left for the author to rewrite. It reports whether
+// anything changed.
+func CleanEducationalMetadata(pkg *AssignmentPackage) bool {
+	objectives, objectivesChanged := cleanMetadataList(pkg.Assignment.LearningObjectives)
+	prerequisites, prerequisitesChanged := cleanMetadataList(pkg.Assignment.Prerequisites)
+	pkg.Assignment.LearningObjectives = objectives
+	pkg.Assignment.Prerequisites = prerequisites
+	return objectivesChanged || prerequisitesChanged
+}
+
+func cleanMetadataList(entries []string) ([]string, bool) {
+	if entries == nil {
+		return nil, false
+	}
+
+	cleaned := []string{}
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		trimmed := strings.TrimSpace(entry)
+		key := strings.ToLower(trimmed)
+		if trimmed == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, trimmed)
+	}
+
+	changed := len(cleaned) != len(entries)
+	for i := 0; !changed && i < len(cleaned); i++ {
+		changed = cleaned[i] != entries[i]
+	}
+	return cleaned, changed
+}
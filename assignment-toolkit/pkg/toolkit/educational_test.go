@@ -0,0 +1,23 @@
+package toolkit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCleanEducationalMetadata(t *testing.T) {
+	pkg := AssignmentPackage{Assignment: Assignment{
+		LearningObjectives: []string{"  Add numbers", "", "add numbers", "Subtract"},
+		Prerequisites:      []string{"Counting"},
+	}}
+
+	if !CleanEducationalMetadata(&pkg) {
+		t.Fatal("expected the objectives to change")
+	}
+	if want := []string{"Add numbers", "Subtract"}; !reflect.DeepEqual(pkg.Assignment.LearningObjectives, want) {
+		t.Errorf("objectives = %q, want %q", pkg.Assignment.LearningObjectives, want)
+	}
+	if CleanEducationalMetadata(&pkg) {
+		t.Error("cleaning clean metadata reported a change")
+	}
+}
@@ -0,0 +1,65 @@
+package toolkit
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+)
+
+// utf8BOM starts files saved as "UTF-8 with BOM", as some Windows editors do
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// utf16BOMs start files saved as UTF-16, little- or big-endian
+var utf16BOMs = [][]byte{{0xFF, 0xFE}, {0xFE, 0xFF}}
+
+// decodeText prepares file contents for parsing: a UTF-8 BOM is dropped,
+// UTF-16 is refused, and invalid UTF-8 is reported to LoadWarningHandler
+// with the offset of the first bad byte, then replaced with U+FFFD so the
+// garbled text is visible instead of breaking the parser. Such files
+// usually come from an editor saving Thai text as TIS-620 or Windows-874.
+func decodeText(filename string, data []byte) ([]byte, error) {
+	for _, bom := range utf16BOMs {
+		if bytes.HasPrefix(data, bom) {
+			return nil, &ParseError{File: filename, Problem: "the file is saved as UTF-16", Hint: "save it as UTF-8"}
+		}
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+
+	offset, count := invalidUTF8(data)
+	if count == 0 {
+		return data, nil
+	}
+	line := bytes.Count(data[:offset], []byte("\n")) + 1
+	column := offset - bytes.LastIndexByte(data[:offset], '\n')
+	more := ""
+	if count > 1 {
+		more = fmt.Sprintf(" and %d more place(s)", count-1)
+	}
+	loadWarning("%s: invalid UTF-8 at byte %d (line %d, column %d)%s; the file is probably saved in another encoding, such as TIS-620 for Thai. Re-save it as UTF-8", filename, offset, line, column, more)
+	return bytes.ToValidUTF8(data, []byte("\uFFFD")), nil
+}
+
+// invalidUTF8 returns the offset of the first invalid UTF-8 sequence in
+// data and how many there are
+func invalidUTF8(data []byte) (first, count int) {
+	first = -1
+	for offset := 0; offset < len(data); {
+		r, size := utf8.DecodeRune(data[offset:])
+		if r == utf8.RuneError && size <= 1 {
+			if first < 0 {
+				first = offset
+			}
+			count++
+			// A run of bad bytes is one problem, as ToValidUTF8 replaces it once
+			for offset < len(data) {
+				if r, size = utf8.DecodeRune(data[offset:]); r != utf8.RuneError || size > 1 {
+					break
+				}
+				offset++
+			}
+			continue
+		}
+		offset += size
+	}
+	return first, count
+}
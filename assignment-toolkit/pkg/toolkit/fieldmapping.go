@@ -0,0 +1,93 @@
+package toolkit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// FieldMapping renames top-level payload keys for an LMS whose schema
+// spells them differently, e.g. autoGrade to auto_grade. Keys it doesn't
+// list keep their names. It is read from the file named by
+// field_mapping_file:
+//
+//	autoGrade: auto_grade
+//	showFeedback: show_feedback
+//	due_date: deadline
+type FieldMapping map[string]string
+
+// LoadFieldMapping reads a YAML or JSON field mapping file. Our side of
+// each entry may use any spelling PayloadKey accepts; every entry must
+// name a different LMS key.
+func LoadFieldMapping(path string) (FieldMapping, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]string
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, yamlParseError(path, data, err)
+	}
+	return NewFieldMapping(raw)
+}
+
+// NewFieldMapping checks a mapping from our payload keys to the LMS's and
+// returns it keyed by our canonical names
+func NewFieldMapping(raw map[string]string) (FieldMapping, error) {
+	mapping := make(FieldMapping, len(raw))
+	var problems []string
+	for name, target := range raw {
+		key, ok := PayloadKey(name)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%q is not a payload field", name))
+			continue
+		}
+		if target = strings.TrimSpace(target); target == "" {
+			problems = append(problems, fmt.Sprintf("%s is mapped to an empty name", key))
+			continue
+		}
+		mapping[key] = target
+	}
+
+	// Two fields sent under one name would overwrite each other
+	owners := make(map[string]string)
+	for _, key := range reservedPayloadKeys {
+		owners[mapping.Key(key)] = owners[mapping.Key(key)] + "," + key
+	}
+	for target, keys := range owners {
+		if keys = strings.TrimPrefix(keys, ","); strings.Contains(keys, ",") {
+			problems = append(problems, fmt.Sprintf("%s would be sent under the same name, %s", strings.Replace(keys, ",", " and ", -1), target))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("invalid field mapping: %s", strings.Join(problems, "; "))
+	}
+	return mapping, nil
+}
+
+// Key returns the LMS name of a payload key
+func (m FieldMapping) Key(key string) string {
+	if target, ok := m[key]; ok {
+		return target
+	}
+	return key
+}
+
+// Apply renames the mapped keys of payload
+func (m FieldMapping) Apply(payload map[string]interface{}) {
+	renamed := make(map[string]interface{}, len(m))
+	for key, target := range m {
+		if value, ok := payload[key]; ok {
+			delete(payload, key)
+			renamed[target] = value
+		}
+	}
+	for key, value := range renamed {
+		payload[key] = value
+	}
+}
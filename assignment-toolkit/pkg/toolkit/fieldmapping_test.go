@@ -0,0 +1,46 @@
+package toolkit
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFieldMappingRenamesPayloadKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mapping.yaml")
+	if err := ioutil.WriteFile(path, []byte("auto_grade: auto_grade\nshowFeedback: show_feedback\ntitle: name\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mapping, err := LoadFieldMapping(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewLMSClient("http://lms.example", "key")
+	client.FieldMapping = mapping
+	client.Fields = FieldFilter{Deny: []string{"showFeedback"}}
+	payload, err := client.LMSPayload(AssignmentPackage{Assignment: Assignment{Title: "Quiz", Type: "multiple-choice", AutoGrade: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if payload["auto_grade"] != true || payload["name"] != "Quiz" {
+		t.Errorf("mapped keys missing: auto_grade %v, name %v", payload["auto_grade"], payload["name"])
+	}
+	for _, key := range []string{"autoGrade", "title", "showFeedback", "show_feedback"} {
+		if _, ok := payload[key]; ok {
+			t.Errorf("payload still has %s", key)
+		}
+	}
+	if payload["description"] != "" {
+		t.Errorf("unmapped key description = %v", payload["description"])
+	}
+}
+
+func TestNewFieldMappingRejectsCollisions(t *testing.T) {
+	_, err := NewFieldMapping(map[string]string{"title": "description", "nonsense": "x"})
+	if err == nil || !strings.Contains(err.Error(), "title and description would be sent under the same name, description") || !strings.Contains(err.Error(), `"nonsense" is not a payload field`) {
+		t.Errorf("err = %v", err)
+	}
+}
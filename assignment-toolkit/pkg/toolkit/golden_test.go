@@ -0,0 +1,92 @@
+package toolkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata/golden")
+
+// fixedNow is the clock used for all golden output
+var fixedNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func useFixedClock(t *testing.T) {
+	t.Helper()
+	previous := Now
+	Now = func() time.Time { return fixedNow }
+	t.Cleanup(func() { Now = previous })
+}
+
+// testPackages returns the sample packages under testdata/packages keyed
+// by file name without extension
+func testPackages(t *testing.T) map[string]AssignmentPackage {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join("testdata", "packages", "*.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no test packages found in testdata/packages")
+	}
+
+	packages := make(map[string]AssignmentPackage, len(files))
+	for _, file := range files {
+		pkg, err := LoadPackage(file)
+		if err != nil {
+			t.Fatalf("failed to load %s: %v", file, err)
+		}
+		packages[strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))] = pkg
+	}
+	return packages
+}
+
+// checkGolden compares value, encoded as indented JSON, with the golden
+// file, rewriting it instead when -update is set
+func checkGolden(t *testing.T, name string, value interface{}) {
+	t.Helper()
+	got, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to encode %s: %v", name, err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", "golden", name)
+	if *updateGolden {
+		if err := ioutil.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("missing golden file (run go test -update): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s drifted from golden file; run go test -update if the change is intended\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+	}
+}
+
+func TestConvertToLMSFormatGolden(t *testing.T) {
+	useFixedClock(t)
+	for name, pkg := range testPackages(t) {
+		t.Run(name, func(t *testing.T) {
+			checkGolden(t, name+".payload.json", ConvertToLMSFormat(pkg))
+		})
+	}
+}
+
+func TestValidateAssignmentPackageGolden(t *testing.T) {
+	useFixedClock(t)
+	for name, pkg := range testPackages(t) {
+		t.Run(name, func(t *testing.T) {
+			checkGolden(t, name+".validation.json", ValidateAssignmentPackage(pkg))
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package toolkit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// DefaultMaxBodyLog is the number of body bytes logged per request or
+// response when no limit is given
+const DefaultMaxBodyLog = 2048
+
+const redacted = "[REDACTED]"
+
+// httpLogger is a RoundTripper that writes each request and response to out
+// with credentials redacted and bodies truncated
+type httpLogger struct {
+	next    http.RoundTripper
+	out     io.Writer
+	apiKey  string
+	maxBody int
+}
+
+// EnableHTTPLog writes every request and response the client makes to out.
+// The API key is redacted wherever it appears and bodies are cut to
+// maxBody bytes (DefaultMaxBodyLog if zero or less).
+func (c *LMSClient) EnableHTTPLog(out io.Writer, maxBody int) {
+	c.HTTPClient = logHTTP(c.HTTPClient, out, c.APIKey, maxBody)
+}
+
+// logHTTP returns a copy of client that logs through an httpLogger
+func logHTTP(client *http.Client, out io.Writer, apiKey string, maxBody int) *http.Client {
+	if maxBody <= 0 {
+		maxBody = DefaultMaxBodyLog
+	}
+
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	logged := *client
+	logged.Transport = &httpLogger{next: next, out: out, apiKey: apiKey, maxBody: maxBody}
+	return &logged
+}
+
+func (l *httpLogger) RoundTrip(req *http.Request) (*http.Response, error) {
+	fmt.Fprintf(l.out, "--> %s %s\n", req.Method, redactSecret(req.URL.String(), l.apiKey))
+	l.logHeaders(req.Header)
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		l.logBody(req.Header, body)
+	}
+
+	resp, err := l.next.RoundTrip(req)
+	if err != nil {
+		fmt.Fprintf(l.out, "<-- error: %s\n", redactSecret(err.Error(), l.apiKey))
+		return nil, err
+	}
+
+	fmt.Fprintf(l.out, "<-- %s\n", resp.Status)
+	l.logHeaders(resp.Header)
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	l.logBody(resp.Header, body)
+
+	return resp, nil
+}
+
+func (l *httpLogger) logHeaders(header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := strings.Join(header[name], ", ")
+		if strings.EqualFold(name, "Authorization") {
+			value = redactAuthorization(value)
+		}
+		fmt.Fprintf(l.out, "    %s: %s\n", name, redactSecret(value, l.apiKey))
+	}
+}
+
+// logBody prints text bodies up to the size limit; binary and compressed
+// bodies are summarized by size only
+func (l *httpLogger) logBody(header http.Header, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+
+	contentType := header.Get("Content-Type")
+	if header.Get("Content-Encoding") != "" || !(strings.Contains(contentType, "json") || strings.HasPrefix(contentType, "text/")) {
+		fmt.Fprintf(l.out, "    (%d byte %s body)\n", len(body), contentType)
+		return
+	}
+
+	// Redact before truncating so a cut can't leave part of the key behind
+	text := redactSecret(string(body), l.apiKey)
+	if len(text) > l.maxBody {
+		text = fmt.Sprintf("%s... (%d more bytes)", text[:l.maxBody], len(text)-l.maxBody)
+	}
+	fmt.Fprintf(l.out, "    %s\n", text)
+}
+
+// redactAuthorization keeps the scheme of an Authorization header but
+// hides the credential
+func redactAuthorization(value string) string {
+	if scheme := strings.SplitN(value, " ", 2); len(scheme) == 2 {
+		return scheme[0] + " " + redacted
+	}
+	return redacted
+}
+
+// redactSecret replaces every occurrence of secret in text
+func redactSecret(text, secret string) string {
+	if secret == "" {
+		return text
+	}
+	return strings.Replace(text, secret, redacted, -1)
+}
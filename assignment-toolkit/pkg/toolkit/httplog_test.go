@@ -0,0 +1,62 @@
+package toolkit
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPLogRedactsAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"bad key secret-key-123","padding":"` + strings.Repeat("x", 100) + `"}`))
+	}))
+	defer server.Close()
+
+	var log bytes.Buffer
+	client := NewLMSClient(server.URL, "secret-key-123")
+	client.EnableHTTPLog(&log, 40)
+
+	err := client.TestConnection()
+	if err == nil {
+		t.Fatal("expected an authentication error")
+	}
+
+	if strings.Contains(log.String(), "secret-key-123") {
+		t.Errorf("log leaks the API key:\n%s", log.String())
+	}
+	if !strings.Contains(log.String(), "Authorization: Bearer [REDACTED]") {
+		t.Errorf("log is missing the redacted Authorization header:\n%s", log.String())
+	}
+	if !strings.Contains(log.String(), "more bytes)") {
+		t.Errorf("log body was not truncated:\n%s", log.String())
+	}
+}
+
+func TestSyncCurlRedactsTheAPIKey(t *testing.T) {
+	client := NewLMSClient("https://lms.example", "secret-key")
+	client.GzipRequests = true
+	pkg := AssignmentPackage{
+		Metadata:   PackageMetadata{Custom: map[string]string{LMSIDKey: "a-42"}},
+		Assignment: Assignment{Title: "Quiz", Type: "multiple-choice"},
+	}
+
+	command, body, err := client.SyncCurl(pkg, "quiz's payload.json.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `curl -X PUT 'https://lms.example/api/assignments/a-42' \
+  -H 'Authorization: Bearer [REDACTED]' \
+  -H 'Content-Encoding: gzip' \
+  -H 'Content-Type: application/json' \
+  --data-binary '@quiz'\''s payload.json.gz'`
+	if command != want {
+		t.Errorf("command =\n%s\nwant\n%s", command, want)
+	}
+	if !bytes.HasPrefix(body, gzipMagic) {
+		t.Errorf("body is not the gzipped payload")
+	}
+}
@@ -0,0 +1,88 @@
+package toolkit
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// InstructionsMarkdown is the instructions_format of Markdown instructions.
+// Their images reference one of the package's resource IDs, a local file
+// (uploaded like option images), or a URL:
+//
+//	instructions_format: markdown
+//	instructions: |
+//	  ## Before you start
+//	  Label the parts of the cell below.
+//	  ![Plant cell](images/plant-cell.png)
+const InstructionsMarkdown = "markdown"
+
+// markdownImage matches ![alt](ref) and ![alt](ref "title")
+var markdownImage = regexp.MustCompile(`!\[([^\]]*)\]\(\s*([^)\s]+)(\s+"[^"]*")?\s*\)`)
+
+// MarkdownInstructions reports whether the assignment's instructions are Markdown
+func MarkdownInstructions(assignment Assignment) bool {
+	return strings.EqualFold(assignment.InstructionsFormat, InstructionsMarkdown)
+}
+
+// InstructionImage is an image embedded in Markdown instructions
+type InstructionImage struct {
+	Alt string
+	Ref string
+}
+
+// InstructionImages returns the images of Markdown instructions in order;
+// plain-text instructions have none
+func InstructionImages(assignment Assignment) []InstructionImage {
+	if !MarkdownInstructions(assignment) {
+		return nil
+	}
+	var images []InstructionImage
+	for _, match := range markdownImage.FindAllStringSubmatch(assignment.Instructions, -1) {
+		images = append(images, InstructionImage{Alt: match[1], Ref: match[2]})
+	}
+	return images
+}
+
+// isRemoteRef reports whether an image reference is a URL rather than a
+// resource ID or local file
+func isRemoteRef(ref string) bool {
+	return strings.Contains(ref, "://") || strings.HasPrefix(ref, "data:")
+}
+
+// rewriteInstructionImages replaces the reference of every image in
+// Markdown instructions that is not a URL with rewrite(ref)
+func rewriteInstructionImages(assignment *Assignment, rewrite func(ref string) string) {
+	if !MarkdownInstructions(*assignment) {
+		return
+	}
+	assignment.Instructions = markdownImage.ReplaceAllStringFunc(assignment.Instructions, func(image string) string {
+		match := markdownImage.FindStringSubmatch(image)
+		if isRemoteRef(match[2]) {
+			return image
+		}
+		return "![" + match[1] + "](" + rewrite(match[2]) + match[3] + ")"
+	})
+}
+
+// lmsInstructions rewrites instruction images for the LMS: resource IDs
+// become the resource's URL (after url_rewrites) or "resource:<id>" when it
+// has none, and local files "file:<name>", the name they are uploaded under
+func lmsInstructions(pkg AssignmentPackage) string {
+	resources := make(map[string]Resource, len(pkg.Resources))
+	for _, resource := range pkg.Resources {
+		resources[resource.ID] = resource
+	}
+
+	assignment := pkg.Assignment
+	rewriteInstructionImages(&assignment, func(ref string) string {
+		if resource, ok := resources[ref]; ok {
+			if resource.URL != "" {
+				return RewriteURL(resource.URL)
+			}
+			return "resource:" + ref
+		}
+		return "file:" + filepath.Base(ref)
+	})
+	return assignment.Instructions
+}
@@ -0,0 +1,51 @@
+package toolkit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInstructionImages(t *testing.T) {
+	pkg := AssignmentPackage{
+		Assignment: Assignment{
+			InstructionsFormat: "Markdown",
+			Instructions: "Label the map.\n" +
+				"![World map](map-1)\n" +
+				"![Flag](images/flag.png \"National flag\")\n" +
+				"![](https://example.com/key.png)",
+		},
+		Resources: []Resource{
+			{ID: "map-1", Title: "World map", URL: "https://cdn.example.com/map.png"},
+		},
+	}
+
+	images := InstructionImages(pkg.Assignment)
+	want := []InstructionImage{
+		{Alt: "World map", Ref: "map-1"},
+		{Alt: "Flag", Ref: "images/flag.png"},
+		{Alt: "", Ref: "https://example.com/key.png"},
+	}
+	if !reflect.DeepEqual(images, want) {
+		t.Errorf("InstructionImages = %v, want %v", images, want)
+	}
+
+	wantInstructions := "Label the map.\n" +
+		"![World map](https://cdn.example.com/map.png)\n" +
+		"![Flag](file:flag.png \"National flag\")\n" +
+		"![](https://example.com/key.png)"
+	if got := lmsInstructions(pkg); got != wantInstructions {
+		t.Errorf("lmsInstructions = %q, want %q", got, wantInstructions)
+	}
+
+	if files := QuestionMediaFiles(pkg); !reflect.DeepEqual(files, []string{"images/flag.png"}) {
+		t.Errorf("QuestionMediaFiles = %v, want only the local instruction image", files)
+	}
+
+	pkg.Assignment.InstructionsFormat = ""
+	if images := InstructionImages(pkg.Assignment); images != nil {
+		t.Errorf("plain-text instructions have images %v", images)
+	}
+	if got := lmsInstructions(pkg); got != pkg.Assignment.Instructions {
+		t.Errorf("plain-text instructions were rewritten: %q", got)
+	}
+}
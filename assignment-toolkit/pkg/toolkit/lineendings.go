@@ -0,0 +1,87 @@
+package toolkit
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Values of Config.NormalizeLineEndings
+const (
+	// LineEndingsOnSave rewrites CRLF as LF when packages are saved (the default)
+	LineEndingsOnSave = "save"
+	// LineEndingsAlways also normalizes packages as they are loaded
+	LineEndingsAlways = "always"
+	// LineEndingsOff keeps text exactly as written
+	LineEndingsOff = "off"
+)
+
+// LineEndingPolicies lists the accepted normalize_line_endings values
+var LineEndingPolicies = []string{LineEndingsOnSave, LineEndingsAlways, LineEndingsOff}
+
+func normalizeOnLoad() bool {
+	return activeConfig.NormalizeLineEndings == LineEndingsAlways
+}
+
+func normalizeOnSave() bool {
+	return activeConfig.NormalizeLineEndings != LineEndingsOff
+}
+
+// lineBreaks turns Windows (CRLF) and old Mac (CR) line breaks into LF
+var lineBreaks = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// NormalizeLineEndings rewrites CRLF and lone CR line breaks as LF in every
+// text of pkg, including free-form questions and custom metadata, and
+// reports whether anything changed
+func NormalizeLineEndings(pkg *AssignmentPackage) bool {
+	return normalizeLineEndings(reflect.ValueOf(pkg).Elem())
+}
+
+// normalizeLineEndings walks v, rewriting the strings it can set. Values
+// held in maps and interfaces are copied, fixed, and stored back.
+func normalizeLineEndings(v reflect.Value) bool {
+	changed := false
+	switch v.Kind() {
+	case reflect.String:
+		text := v.String()
+		if fixed := lineBreaks.Replace(text); fixed != text && v.CanSet() {
+			v.SetString(fixed)
+			return true
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return normalizeLineEndings(v.Elem())
+		}
+	case reflect.Interface:
+		if v.IsNil() || !v.CanSet() {
+			return false
+		}
+		value := reflect.New(v.Elem().Type()).Elem()
+		value.Set(v.Elem())
+		if normalizeLineEndings(value) {
+			v.Set(value)
+			return true
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Field(i); field.CanSet() && normalizeLineEndings(field) {
+				changed = true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if normalizeLineEndings(v.Index(i)) {
+				changed = true
+			}
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(v.MapIndex(key))
+			if normalizeLineEndings(value) {
+				v.SetMapIndex(key, value)
+				changed = true
+			}
+		}
+	}
+	return changed
+}
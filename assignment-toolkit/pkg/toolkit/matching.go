@@ -0,0 +1,196 @@
+package toolkit
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// A matching question is either two parallel lists, where each left item
+// matches the right item at the same position:
+//
+//	leftItems: ["France", "Spain"]
+//	rightItems: ["Paris", "Madrid"]
+//
+// or an explicit list of pairs, which lets several left items share one
+// right item. Extra rightItems act as distractors that match nothing:
+//
+//	pairs:
+//	  - left: "run"
+//	    right: "verb"
+//	  - left: "jump"
+//	    right: "verb"
+//	  - left: "quickly"
+//	    right: "adverb"
+//	rightItems: ["noun"]
+//
+// Any item may be a map like an option, pairing an image or an audio clip
+// with optional text:
+//
+//	pairs:
+//	  - left:
+//	      audio: "audio/maew.mp3"
+//	    right:
+//	      text: "cat"
+//	      image: "images/cat.png"
+
+// MatchingPair is one left item and the right item it matches. Each is a
+// string or a map with "text", "image", and "audio".
+type MatchingPair struct {
+	Left  interface{}
+	Right interface{}
+}
+
+// MatchingItemLabel names a matching item in messages: its text, or the
+// file name of its media when it has none
+func MatchingItemLabel(item interface{}) string {
+	if text := OptionText(item); text != "" {
+		return text
+	}
+	if o, ok := item.(map[string]interface{}); ok {
+		for _, kind := range mediaKinds {
+			if ref, _ := o[kind].(string); ref != "" {
+				return filepath.Base(ref)
+			}
+		}
+	}
+	return ""
+}
+
+// matchingItemKey identifies a matching item, so items with the same text
+// but different media stay distinct
+func matchingItemKey(item interface{}) string {
+	return fmt.Sprintf("%v", item)
+}
+
+// MatchingPairs returns the pairs of a matching question in either form
+func MatchingPairs(question map[string]interface{}) []MatchingPair {
+	if _, ok := question["pairs"]; ok {
+		var pairs []MatchingPair
+		for _, item := range listValue(question["pairs"]) {
+			pair, _ := item.(map[string]interface{})
+			pairs = append(pairs, MatchingPair{Left: pair["left"], Right: pair["right"]})
+		}
+		return pairs
+	}
+
+	left, right := listValue(question["leftItems"]), listValue(question["rightItems"])
+	var pairs []MatchingPair
+	for i := range left {
+		pair := MatchingPair{Left: left[i]}
+		if i < len(right) {
+			pair.Right = right[i]
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+// checkMatchingPairs reports incomplete pairs and left items listed twice
+// in questions that use the pairs form
+func checkMatchingPairs(pkg AssignmentPackage) []string {
+	var findings []string
+	for _, question := range questionMaps(pkg.Assignment.Questions) {
+		if _, ok := question["pairs"]; !ok {
+			continue
+		}
+		if _, ok := question["leftItems"]; ok {
+			findings = append(findings, "Matching questions must use either pairs or leftItems/rightItems, not both")
+			continue
+		}
+		if _, ok := question["pairs"].([]interface{}); !ok {
+			findings = append(findings, "Matching pairs must be a list of left/right items")
+			continue
+		}
+
+		seen := make(map[string]bool)
+		for i, pair := range MatchingPairs(question) {
+			if MatchingItemLabel(pair.Left) == "" || MatchingItemLabel(pair.Right) == "" {
+				findings = append(findings, fmt.Sprintf("Matching pair %d needs both a left and a right item", i+1))
+				continue
+			}
+			key := matchingItemKey(pair.Left)
+			if seen[key] {
+				findings = append(findings, fmt.Sprintf("Left item %q appears in more than one pair", MatchingItemLabel(pair.Left)))
+			}
+			seen[key] = true
+		}
+	}
+	return findings
+}
+
+// convertMatchingPairs rewrites questions in the pairs form for the LMS as
+// leftItems, the distinct rightItems (distractors last), and correctMatches
+// giving the rightItems index for each left item. Questions in the
+// parallel-list form are returned unchanged.
+func convertMatchingPairs(questions interface{}) interface{} {
+	convert := func(question map[string]interface{}) map[string]interface{} {
+		if _, ok := question["pairs"]; !ok {
+			return question
+		}
+
+		converted := make(map[string]interface{}, len(question))
+		for key, value := range question {
+			if key != "pairs" {
+				converted[key] = value
+			}
+		}
+
+		var leftItems, rightItems []interface{}
+		var correctMatches []int
+		index := make(map[string]int)
+		addRight := func(right interface{}) int {
+			key := matchingItemKey(right)
+			if i, ok := index[key]; ok {
+				return i
+			}
+			index[key] = len(rightItems)
+			rightItems = append(rightItems, right)
+			return index[key]
+		}
+		for _, pair := range MatchingPairs(question) {
+			leftItems = append(leftItems, pair.Left)
+			correctMatches = append(correctMatches, addRight(pair.Right))
+		}
+		for _, distractor := range listValue(question["rightItems"]) {
+			addRight(distractor)
+		}
+
+		converted["leftItems"] = leftItems
+		converted["rightItems"] = rightItems
+		converted["correctMatches"] = correctMatches
+		return converted
+	}
+
+	switch q := questions.(type) {
+	case map[string]interface{}:
+		return convert(q)
+	case []interface{}:
+		result := make([]interface{}, len(q))
+		for i, item := range q {
+			if question, ok := item.(map[string]interface{}); ok {
+				result[i] = convert(question)
+			} else {
+				result[i] = item
+			}
+		}
+		return result
+	default:
+		return questions
+	}
+}
+
+// listValue returns a YAML or JSON list as a generic list
+func listValue(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case []string:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = item
+		}
+		return result
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,29 @@
+package toolkit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckMatchingPairs(t *testing.T) {
+	pair := func(left, right interface{}) interface{} {
+		return map[string]interface{}{"left": left, "right": right}
+	}
+	clip := map[string]interface{}{"audio": "audio/cat.mp3"}
+	pkg := AssignmentPackage{Assignment: Assignment{Type: "matching", Questions: []interface{}{
+		map[string]interface{}{"pairs": []interface{}{pair("run", "verb"), pair("jump", "verb")}},
+		map[string]interface{}{"pairs": []interface{}{pair("run", "verb"), pair("run", "noun"), pair("fast", "")}},
+		map[string]interface{}{"pairs": []interface{}{}, "leftItems": []interface{}{"a"}},
+		map[string]interface{}{"pairs": []interface{}{pair(clip, "cat"), pair(clip, "kitten"), pair(map[string]interface{}{"audio": "dog.mp3"}, "dog")}},
+	}}}
+
+	want := []string{
+		`Left item "run" appears in more than one pair`,
+		"Matching pair 3 needs both a left and a right item",
+		"Matching questions must use either pairs or leftItems/rightItems, not both",
+		`Left item "cat.mp3" appears in more than one pair`,
+	}
+	if got := checkMatchingPairs(pkg); !reflect.DeepEqual(got, want) {
+		t.Errorf("findings = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+package toolkit
+
+import (
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultMediaMaxSizeMB is the audio/video size above which sync warns
+// when media_max_size_mb is not configured
+const DefaultMediaMaxSizeMB = 100
+
+// DefaultRejectedMediaFormats are the audio/video formats the LMS is
+// assumed not to accept when rejected_media_formats is not configured
+var DefaultRejectedMediaFormats = []string{"aiff", "avi", "flv", "wma", "wmv"}
+
+// IsMediaResource reports whether a resource is an audio or video file
+func IsMediaResource(resource Resource) bool {
+	if IsAudioResource(resource) {
+		return true
+	}
+	if resource.LocalPath == "" {
+		return false
+	}
+	return resource.Type == "video" || strings.HasPrefix(mime.TypeByExtension(filepath.Ext(resource.LocalPath)), "video/")
+}
+
+// mediaFormats returns the names a media file's format goes by: its
+// extension and, once hydrated, the formats ffprobe reported (which may be
+// a list such as "mov,mp4,m4a")
+func mediaFormats(resource Resource) []string {
+	formats := []string{strings.TrimPrefix(strings.ToLower(filepath.Ext(resource.LocalPath)), ".")}
+	for _, format := range strings.Split(resource.Metadata[MetaAudioFormat], ",") {
+		if format = strings.TrimSpace(format); format != "" {
+			formats = append(formats, strings.ToLower(format))
+		}
+	}
+	return formats
+}
+
+// CheckMediaResources warns about audio and video files the LMS is likely
+// to reject after a long upload: files over the configured size limit and
+// formats on the configured rejected list. Missing files are left to the
+// upload itself to report.
+func CheckMediaResources(pkg AssignmentPackage) []string {
+	maxSizeMB := activeConfig.MediaMaxSizeMB
+	if maxSizeMB <= 0 {
+		maxSizeMB = DefaultMediaMaxSizeMB
+	}
+	rejected := activeConfig.RejectedMediaFormats
+	if rejected == nil {
+		rejected = DefaultRejectedMediaFormats
+	}
+
+	var warnings []string
+	for _, resource := range pkg.Resources {
+		if !IsMediaResource(resource) {
+			continue
+		}
+
+		for _, format := range mediaFormats(resource) {
+			if containsFold(rejected, format) {
+				warnings = append(warnings, fmt.Sprintf("Resource %q is %s, which the LMS does not accept; transcode it first (e.g. ffmpeg -i %s %s)",
+					resource.Title, format, filepath.Base(resource.LocalPath), transcodeTarget(resource)))
+				break
+			}
+		}
+
+		info, err := os.Stat(resource.LocalPath)
+		if err != nil {
+			continue
+		}
+		if sizeMB := float64(info.Size()) / (1 << 20); sizeMB > float64(maxSizeMB) {
+			warnings = append(warnings, fmt.Sprintf("Resource %q is %.0f MB, over the %d MB media limit; consider transcoding it to a lower bitrate",
+				resource.Title, sizeMB, maxSizeMB))
+		}
+	}
+	return warnings
+}
+
+// transcodeTarget suggests a widely accepted file name for a media resource
+func transcodeTarget(resource Resource) string {
+	base := strings.TrimSuffix(filepath.Base(resource.LocalPath), filepath.Ext(resource.LocalPath))
+	if IsAudioResource(resource) {
+		return base + ".mp3"
+	}
+	return base + ".mp4"
+}
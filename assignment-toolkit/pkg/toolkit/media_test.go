@@ -0,0 +1,40 @@
+package toolkit
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckMediaResources(t *testing.T) {
+	defer UseConfig(Config{})
+	dir := t.TempDir()
+	for name, size := range map[string]int{"lecture.mp4": 2 << 20, "interview.wma": 10, "map.png": 3 << 20} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pkg := AssignmentPackage{Resources: []Resource{
+		{Title: "Lecture", Type: "video", LocalPath: filepath.Join(dir, "lecture.mp4")},
+		{Title: "Interview", Type: "audio", LocalPath: filepath.Join(dir, "interview.wma")},
+		{Title: "Map", Type: "image", LocalPath: filepath.Join(dir, "map.png")},
+	}}
+
+	UseConfig(Config{MediaMaxSizeMB: 1})
+	warnings := CheckMediaResources(pkg)
+	if len(warnings) != 2 {
+		t.Fatalf("got %d warnings, want 2: %v", len(warnings), warnings)
+	}
+	if !strings.Contains(warnings[0], "Lecture") || !strings.Contains(warnings[0], "over the 1 MB") {
+		t.Errorf("unexpected size warning: %s", warnings[0])
+	}
+	if !strings.Contains(warnings[1], "interview.mp3") {
+		t.Errorf("format warning doesn't suggest an mp3: %s", warnings[1])
+	}
+
+	UseConfig(Config{RejectedMediaFormats: []string{}})
+	if warnings := CheckMediaResources(pkg); len(warnings) != 0 {
+		t.Errorf("an empty rejected list and default size limit still warned: %v", warnings)
+	}
+}
@@ -0,0 +1,257 @@
+package toolkit
+
+import "path/filepath"
+
+// A multiple-choice option is either plain text or a map with "text" and
+// an optional "image" or "audio". Each is the ID of one of the package's
+// resources or a path to a local file:
+//
+//	options:
+//	  - "Triangle"
+//	  - text: "Diagram B"
+//	    image: "images/diagram-b.png"
+
+// OptionText returns the text of an option in either form
+func OptionText(option interface{}) string {
+	switch o := option.(type) {
+	case string:
+		return o
+	case map[string]interface{}:
+		text, _ := o["text"].(string)
+		return text
+	default:
+		return ""
+	}
+}
+
+// OptionImage returns the image reference of an option, or ""
+func OptionImage(option interface{}) string {
+	if o, ok := option.(map[string]interface{}); ok {
+		image, _ := o["image"].(string)
+		return image
+	}
+	return ""
+}
+
+// questionMaps returns the question objects of an assignment, which may be
+// stored as a single question or a list
+func questionMaps(questions interface{}) []map[string]interface{} {
+	switch q := questions.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{q}
+	case []interface{}:
+		var result []map[string]interface{}
+		for _, item := range q {
+			if question, ok := item.(map[string]interface{}); ok {
+				result = append(result, question)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// questionOptions returns a question's options as a generic list
+func questionOptions(question map[string]interface{}) []interface{} {
+	switch options := question["options"].(type) {
+	case []interface{}:
+		return options
+	case []string:
+		result := make([]interface{}, len(options))
+		for i, option := range options {
+			result[i] = option
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// mediaKinds are the media an option or matching item may carry, each
+// referenced like an option image
+var mediaKinds = []string{"image", "audio"}
+
+// questionItems returns the options and matching items of a question
+func questionItems(question map[string]interface{}) []interface{} {
+	items := append([]interface{}{}, questionOptions(question)...)
+	items = append(items, listValue(question["leftItems"])...)
+	items = append(items, listValue(question["rightItems"])...)
+	for _, pair := range listValue(question["pairs"]) {
+		if p, ok := pair.(map[string]interface{}); ok {
+			items = append(items, p["left"], p["right"])
+		}
+	}
+	return items
+}
+
+// visitQuestionMedia calls visit for each image or audio reference in the
+// options and matching items of questions, with the item holding it and
+// its key so the reference can be rewritten in place
+func visitQuestionMedia(questions interface{}, visit func(item map[string]interface{}, kind string)) {
+	for _, question := range questionMaps(questions) {
+		for _, item := range questionItems(question) {
+			o, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, kind := range mediaKinds {
+				if ref, _ := o[kind].(string); ref != "" {
+					visit(o, kind)
+				}
+			}
+		}
+	}
+}
+
+// questionMediaFile is a local file attached to a question item
+type questionMediaFile struct {
+	Path string
+	Kind string // "image" or "audio"
+}
+
+// questionMediaFiles returns the local files referenced by option images,
+// matching item media, and images in Markdown instructions, skipping
+// references to the package's resource IDs
+func questionMediaFiles(pkg AssignmentPackage) []questionMediaFile {
+	resourceIDs := make(map[string]bool, len(pkg.Resources))
+	for _, resource := range pkg.Resources {
+		resourceIDs[resource.ID] = true
+	}
+
+	var files []questionMediaFile
+	seen := make(map[string]bool)
+	visitQuestionMedia(pkg.Assignment.Questions, func(item map[string]interface{}, kind string) {
+		ref := item[kind].(string)
+		if resourceIDs[ref] || seen[ref] {
+			return
+		}
+		seen[ref] = true
+		files = append(files, questionMediaFile{Path: ref, Kind: kind})
+	})
+	for _, image := range InstructionImages(pkg.Assignment) {
+		if resourceIDs[image.Ref] || seen[image.Ref] || isRemoteRef(image.Ref) {
+			continue
+		}
+		seen[image.Ref] = true
+		files = append(files, questionMediaFile{Path: image.Ref, Kind: "image"})
+	}
+	return files
+}
+
+// QuestionMediaFiles returns the local files referenced by option images,
+// matching item images and audio, and images in Markdown instructions,
+// skipping references to the package's resource IDs
+func QuestionMediaFiles(pkg AssignmentPackage) []string {
+	var paths []string
+	for _, file := range questionMediaFiles(pkg) {
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
+
+// questionMediaResources turns question media files into resources so they
+// are uploaded alongside the package's own resources
+func questionMediaResources(pkg AssignmentPackage) []Resource {
+	var resources []Resource
+	for _, file := range questionMediaFiles(pkg) {
+		resources = append(resources, Resource{
+			Title:     filepath.Base(file.Path),
+			Type:      file.Kind,
+			LocalPath: file.Path,
+		})
+	}
+	return resources
+}
+
+// lmsMediaItem converts an option or matching item for the LMS. Each image
+// or audio becomes "<kind>ResourceId" for resource IDs or "<kind>File" (the
+// uploaded file name) for local files; items without media become their text.
+func lmsMediaItem(item interface{}, resourceIDs map[string]bool) interface{} {
+	o, ok := item.(map[string]interface{})
+	if !ok {
+		return OptionText(item)
+	}
+
+	converted := map[string]interface{}{"text": OptionText(o)}
+	for _, kind := range mediaKinds {
+		ref, _ := o[kind].(string)
+		if ref == "" {
+			continue
+		}
+		if resourceIDs[ref] {
+			converted[kind+"ResourceId"] = ref
+		} else {
+			converted[kind+"File"] = filepath.Base(ref)
+		}
+	}
+	if len(converted) == 1 {
+		return OptionText(o)
+	}
+	return converted
+}
+
+// convertQuestionMedia rewrites question media for the LMS with
+// lmsMediaItem, converting every option and matching item of a question
+// that has any. Questions without media are returned unchanged.
+func convertQuestionMedia(pkg AssignmentPackage) interface{} {
+	questions := pkg.Assignment.Questions
+	resourceIDs := make(map[string]bool, len(pkg.Resources))
+	for _, resource := range pkg.Resources {
+		resourceIDs[resource.ID] = true
+	}
+
+	convertQuestion := func(question map[string]interface{}) map[string]interface{} {
+		hasMedia := false
+		visitQuestionMedia(question, func(map[string]interface{}, string) { hasMedia = true })
+		if !hasMedia {
+			return question
+		}
+
+		converted := make(map[string]interface{}, len(question))
+		for key, value := range question {
+			converted[key] = value
+		}
+		convertList := func(items []interface{}) []interface{} {
+			result := make([]interface{}, len(items))
+			for i, item := range items {
+				result[i] = lmsMediaItem(item, resourceIDs)
+			}
+			return result
+		}
+		for _, key := range []string{"options", "leftItems", "rightItems"} {
+			if items := listValue(question[key]); items != nil {
+				converted[key] = convertList(items)
+			}
+		}
+		if pairs := listValue(question["pairs"]); pairs != nil {
+			lmsPairs := make([]interface{}, len(pairs))
+			for i, pair := range pairs {
+				p, _ := pair.(map[string]interface{})
+				lmsPairs[i] = map[string]interface{}{
+					"left":  lmsMediaItem(p["left"], resourceIDs),
+					"right": lmsMediaItem(p["right"], resourceIDs),
+				}
+			}
+			converted["pairs"] = lmsPairs
+		}
+		return converted
+	}
+
+	switch q := questions.(type) {
+	case map[string]interface{}:
+		return convertQuestion(q)
+	case []interface{}:
+		result := make([]interface{}, len(q))
+		for i, item := range q {
+			if question, ok := item.(map[string]interface{}); ok {
+				result[i] = convertQuestion(question)
+			} else {
+				result[i] = item
+			}
+		}
+		return result
+	default:
+		return questions
+	}
+}
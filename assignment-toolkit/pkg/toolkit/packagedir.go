@@ -0,0 +1,345 @@
+package toolkit
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// packageAssignmentFiles are the names `package` may give the assignment
+// inside a package directory
+var packageAssignmentFiles = []string{"assignment.yaml", "assignment.yaml.gz"}
+
+// LoadPackageDir loads a directory produced by the package command. Local
+// resource paths, including question media, are pointed at the copies in the
+// directory's resources/ folder.
+func LoadPackageDir(dir string) (AssignmentPackage, error) {
+	assignmentFile, err := packageAssignmentFile(dir)
+	if err != nil {
+		return AssignmentPackage{}, err
+	}
+
+	pkg, err := LoadPackage(assignmentFile)
+	if err != nil {
+		return pkg, err
+	}
+	pointResourcesAt(&pkg, filepath.Join(dir, "resources"))
+	return pkg, nil
+}
+
+// packageAssignmentFile finds the assignment inside a package directory
+func packageAssignmentFile(dir string) (string, error) {
+	for _, name := range packageAssignmentFiles {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return filepath.Join(dir, name), nil
+		}
+	}
+	return "", fmt.Errorf("%s does not contain assignment.yaml", dir)
+}
+
+// pointResourcesAt rewrites local resource paths, question media and
+// instruction images to the files of the same name in resourceDir
+func pointResourcesAt(pkg *AssignmentPackage, resourceDir string) {
+	for i, resource := range pkg.Resources {
+		if resource.LocalPath != "" {
+			pkg.Resources[i].LocalPath = filepath.Join(resourceDir, filepath.Base(resource.LocalPath))
+		}
+	}
+
+	resourceIDs := make(map[string]bool, len(pkg.Resources))
+	for _, resource := range pkg.Resources {
+		resourceIDs[resource.ID] = true
+	}
+	visitQuestionMedia(pkg.Assignment.Questions, func(item map[string]interface{}, kind string) {
+		if ref := item[kind].(string); !resourceIDs[ref] {
+			item[kind] = filepath.Join(resourceDir, filepath.Base(ref))
+		}
+	})
+	rewriteInstructionImages(&pkg.Assignment, func(ref string) string {
+		if resourceIDs[ref] {
+			return ref
+		}
+		return filepath.Join(resourceDir, filepath.Base(ref))
+	})
+}
+
+// VerifyResourceFiles checks that every local resource file exists and,
+// when the resource records a checksum, that the contents match it
+func VerifyResourceFiles(pkg AssignmentPackage) []string {
+	var problems []string
+	for _, resource := range pkg.Resources {
+		if resource.LocalPath == "" {
+			continue
+		}
+
+		checksum, err := FileChecksum(resource.LocalPath)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", resource.Title, err))
+			continue
+		}
+		if resource.Checksum != "" && !strings.EqualFold(resource.Checksum, checksum) {
+			problems = append(problems, fmt.Sprintf("%s: checksum mismatch (expected %s, got %s)", resource.Title, resource.Checksum, checksum))
+		}
+	}
+
+	for _, file := range questionMediaFiles(pkg) {
+		if _, err := os.Stat(file.Path); err != nil {
+			problems = append(problems, fmt.Sprintf("question %s %s: %v", file.Kind, filepath.Base(file.Path), err))
+		}
+	}
+	return problems
+}
+
+// CheckResourceFiles records in validation, as errors, the problems
+// VerifyResourceFiles finds: missing files and checksum mismatches
+func CheckResourceFiles(pkg AssignmentPackage, validation *ValidationInfo) {
+	for _, problem := range VerifyResourceFiles(pkg) {
+		validation.Errors = append(validation.Errors, problem)
+		validation.IsValid = false
+	}
+}
+
+// DescribeResourceFile records the SHA-256, size and, unless the resource
+// already names one, the MIME type sniffed from the contents of the
+// resource's local file
+func DescribeResourceFile(resource *Resource) error {
+	file, err := os.Open(resource.LocalPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// http.DetectContentType looks at no more than the first 512 bytes
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	head = head[:n]
+
+	hash := sha256.New()
+	hash.Write(head)
+	rest, err := io.Copy(hash, file)
+	if err != nil {
+		return err
+	}
+
+	resource.Checksum = fmt.Sprintf("%x", hash.Sum(nil))
+	resource.FileSize = int64(n) + rest
+	if resource.MimeType == "" {
+		resource.MimeType = http.DetectContentType(head)
+	}
+	return nil
+}
+
+// ZipPackageDir compresses a directory produced by the package command
+// into a zip at output. Entry names are relative to dir, so the archive
+// unpacks to assignment.yaml, README.md and resources/ wherever it is
+// extracted.
+func ZipPackageDir(dir, output string) error {
+	out, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	writer := zip.NewWriter(out)
+
+	err = filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		return addZipFile(writer, filepath.ToSlash(name), file)
+	})
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(output)
+	}
+	return err
+}
+
+// PackageZipName is the assignment name a package zip was made from:
+// fractions-quiz-package.zip gives fractions-quiz
+func PackageZipName(archive string) string {
+	name := filepath.Base(archive)
+	name = name[:len(name)-len(filepath.Ext(name))]
+	return strings.TrimSuffix(name, "-package")
+}
+
+// ExtractPackageZip unpacks a zip of a package directory into the
+// workspace dest, the way ExtractBundle unpacks a bundle: the assignment is
+// saved as dest/<name>.yaml (.yaml.gz if it was packaged compressed) and its
+// files go in dest/resources, with local paths rewritten to resources/<file>.
+// The package README is left out. Nothing is written if an existing file
+// would be replaced, unless overwrite is set; resource files identical to
+// the existing copy are skipped either way. It returns the assignment and
+// the file it was saved to.
+func ExtractPackageZip(archive, dest, name string, overwrite bool) (AssignmentPackage, string, error) {
+	tempDir, err := ioutil.TempDir("", "assignment-package-")
+	if err != nil {
+		return AssignmentPackage{}, "", err
+	}
+	defer os.RemoveAll(tempDir)
+
+	dir, err := ExtractZip(archive, tempDir)
+	if err != nil {
+		return AssignmentPackage{}, "", err
+	}
+	assignmentFile, err := packageAssignmentFile(dir)
+	if err != nil {
+		return AssignmentPackage{}, "", fmt.Errorf("not a package: %s does not contain assignment.yaml", filepath.Base(archive))
+	}
+	pkg, err := LoadPackage(assignmentFile)
+	if err != nil {
+		return pkg, "", err
+	}
+	pointResourcesAt(&pkg, "resources")
+
+	target := filepath.Join(dest, name+".yaml")
+	if isCompressedFile(assignmentFile) {
+		target += ".gz"
+	}
+	var conflicts []string
+	if _, err := os.Stat(target); err == nil && !overwrite {
+		conflicts = append(conflicts, target)
+	}
+
+	copies := make(map[string]string) // extracted file -> workspace file
+	resourceDir := filepath.Join(dir, "resources")
+	entries, err := ioutil.ReadDir(resourceDir)
+	if err != nil && !os.IsNotExist(err) {
+		return pkg, "", err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		src := filepath.Join(resourceDir, entry.Name())
+		dst := filepath.Join(dest, "resources", entry.Name())
+		if _, err := os.Stat(dst); err == nil {
+			if sameFileContents(src, dst) {
+				continue
+			}
+			if !overwrite {
+				conflicts = append(conflicts, dst)
+				continue
+			}
+		}
+		copies[src] = dst
+	}
+	if len(conflicts) > 0 {
+		return pkg, "", fmt.Errorf("would overwrite existing files: %s", strings.Join(conflicts, ", "))
+	}
+
+	for src, dst := range copies {
+		if err := copyFileTo(src, dst); err != nil {
+			return pkg, "", err
+		}
+	}
+	if err := SavePackage(pkg, target); err != nil {
+		return pkg, "", err
+	}
+	return pkg, target, nil
+}
+
+// sameFileContents reports whether two files have the same checksum
+func sameFileContents(a, b string) bool {
+	first, err := FileChecksum(a)
+	if err != nil {
+		return false
+	}
+	second, err := FileChecksum(b)
+	return err == nil && first == second
+}
+
+func copyFileTo(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// ExtractZip unpacks a zip archive into dest and returns the directory
+// holding the package, descending into a single top-level folder if the
+// archive wraps everything in one
+func ExtractZip(archive, dest string) (string, error) {
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	root := filepath.Clean(dest) + string(os.PathSeparator)
+	for _, file := range reader.File {
+		target := filepath.Join(dest, file.Name)
+		if !strings.HasPrefix(target, root) {
+			return "", fmt.Errorf("archive entry %q escapes the destination", file.Name)
+		}
+
+		if file.FileInfo().IsDir() {
+			os.MkdirAll(target, 0755)
+			continue
+		}
+		if err := extractZipFile(file, target); err != nil {
+			return "", err
+		}
+	}
+
+	entries, err := ioutil.ReadDir(dest)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dest, entries[0].Name()), nil
+	}
+	return dest, nil
+}
+
+func extractZipFile(file *zip.File, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, src)
+	return err
+}
@@ -0,0 +1,119 @@
+package toolkit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadPackageDirVerifiesResources(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "resources"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "resources", "map.png"), []byte("image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pkg := AssignmentPackage{
+		Assignment: Assignment{Title: "Map Quiz", Type: "multiple-choice"},
+		Resources: []Resource{
+			{ID: "map", Title: "Map", LocalPath: "/original/place/map.png", Checksum: "0000"},
+		},
+	}
+	if err := SavePackage(pkg, filepath.Join(dir, "assignment.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadPackageDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "resources", "map.png"); loaded.Resources[0].LocalPath != want {
+		t.Errorf("LocalPath = %q, want %q", loaded.Resources[0].LocalPath, want)
+	}
+
+	problems := VerifyResourceFiles(loaded)
+	if len(problems) != 1 || !strings.Contains(problems[0], "checksum mismatch") {
+		t.Errorf("problems = %v, want one checksum mismatch", problems)
+	}
+}
+
+func TestPackageZipRoundTrip(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "map-quiz-package")
+	if err := os.MkdirAll(filepath.Join(dir, "resources"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(filepath.Join(dir, "resources", "map.png"), []byte("image"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("# Map Quiz"), 0644)
+
+	pkg := AssignmentPackage{
+		Assignment: Assignment{Title: "Map Quiz", Type: "multiple-choice"},
+		Resources:  []Resource{{ID: "map", Title: "Map", LocalPath: "/original/place/map.png"}},
+	}
+	if err := SavePackage(pkg, filepath.Join(dir, "assignment.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := dir + ".zip"
+	if err := ZipPackageDir(dir, archive); err != nil {
+		t.Fatal(err)
+	}
+	if name := PackageZipName(archive); name != "map-quiz" {
+		t.Errorf("PackageZipName = %q, want map-quiz", name)
+	}
+
+	dest := t.TempDir()
+	loaded, file, err := ExtractPackageZip(archive, dest, "map-quiz", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dest, "map-quiz.yaml"); file != want {
+		t.Errorf("file = %q, want %q", file, want)
+	}
+	if want := filepath.Join("resources", "map.png"); loaded.Resources[0].LocalPath != want {
+		t.Errorf("LocalPath = %q, want %q", loaded.Resources[0].LocalPath, want)
+	}
+	if data, err := ioutil.ReadFile(filepath.Join(dest, "resources", "map.png")); err != nil || string(data) != "image" {
+		t.Errorf("resource not extracted: %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "README.md")); err == nil {
+		t.Error("README.md should not be extracted into the workspace")
+	}
+
+	if _, _, err := ExtractPackageZip(archive, dest, "map-quiz", false); err == nil {
+		t.Error("expected extracting twice to refuse to overwrite")
+	}
+}
+
+func TestDescribeResourceFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "map.png")
+	data := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 1000)...)
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	resource := Resource{Title: "Map", LocalPath: file}
+	if err := DescribeResourceFile(&resource); err != nil {
+		t.Fatal(err)
+	}
+	checksum, _ := FileChecksum(file)
+	if resource.Checksum != checksum || resource.FileSize != int64(len(data)) || resource.MimeType != "image/png" {
+		t.Errorf("resource = %+v, want checksum %s, size %d, image/png", resource, checksum, len(data))
+	}
+
+	var validation ValidationInfo
+	validation.IsValid = true
+	CheckResourceFiles(AssignmentPackage{Resources: []Resource{resource}}, &validation)
+	if !validation.IsValid {
+		t.Errorf("unchanged file reported: %v", validation.Errors)
+	}
+
+	ioutil.WriteFile(file, []byte("edited"), 0644)
+	CheckResourceFiles(AssignmentPackage{Resources: []Resource{resource}}, &validation)
+	if validation.IsValid || len(validation.Errors) != 1 || !strings.Contains(validation.Errors[0], "checksum mismatch") {
+		t.Errorf("errors = %v, want one checksum mismatch", validation.Errors)
+	}
+}
@@ -0,0 +1,109 @@
+package toolkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParseError describes an assignment file that could not be read, with
+// the position of the problem when the parser reports one and a hint
+// about the usual cause
+type ParseError struct {
+	File    string
+	Line    int // 0 when unknown
+	Column  int // 0 when unknown
+	Problem string
+	Hint    string
+}
+
+func (e *ParseError) Error() string {
+	location := e.File
+	if e.Line > 0 {
+		location += fmt.Sprintf(" line %d", e.Line)
+		if e.Column > 0 {
+			location += fmt.Sprintf(", column %d", e.Column)
+		}
+	}
+	message := fmt.Sprintf("%s: %s", location, e.Problem)
+	if e.Hint != "" {
+		message += " (" + e.Hint + ")"
+	}
+	return message
+}
+
+// yamlErrorLine matches the "line N: problem" parts of yaml.v2 errors
+var yamlErrorLine = regexp.MustCompile(`line (\d+): (.*)`)
+
+// yamlHints map fragments of yaml.v2 messages to advice for people
+// editing files by hand
+var yamlHints = []struct {
+	fragment string
+	hint     string
+}{
+	{"tab character", "indent with spaces, not tabs"},
+	{"mapping values are not allowed", "put quotes around text that contains \": \""},
+	{"did not find expected key", "check this line and the one after it are indented like their neighbours"},
+	{"could not find expected ':'", "each setting needs a colon after its name"},
+	{"did not find expected '-' indicator", "list items must all start with \"- \" at the same indentation"},
+	{"unexpected end of stream", "a quote or bracket is not closed"},
+	{"cannot unmarshal !!str", "this setting needs a number, true/false, or a list, not text"},
+	{"cannot unmarshal !!seq", "this setting takes a single value, not a list"},
+	{"cannot unmarshal !!map", "this setting takes a single value, not nested settings"},
+}
+
+// yamlParseError turns a yaml.v2 error into a ParseError. Only the first
+// problem of an "unmarshal errors" list is positioned; the rest are kept
+// in the message.
+func yamlParseError(filename string, data []byte, err error) *ParseError {
+	parseErr := &ParseError{File: filename, Problem: strings.TrimPrefix(err.Error(), "yaml: ")}
+
+	matches := yamlErrorLine.FindAllStringSubmatch(err.Error(), -1)
+	if len(matches) > 0 {
+		parseErr.Line, _ = strconv.Atoi(matches[0][1])
+		parseErr.Problem = matches[0][2]
+		if len(matches) > 1 {
+			parseErr.Problem += fmt.Sprintf("; %d more problem(s) after this one", len(matches)-1)
+		}
+	}
+
+	for _, candidate := range yamlHints {
+		if strings.Contains(parseErr.Problem, candidate.fragment) {
+			parseErr.Hint = candidate.hint
+			break
+		}
+	}
+	if parseErr.Hint == "" && bytes.Contains(data, []byte("\n\t")) {
+		parseErr.Hint = "the file is indented with tabs; YAML needs spaces"
+	}
+	return parseErr
+}
+
+// jsonParseError turns an encoding/json error into a ParseError, working
+// out the line and column from the byte offset
+func jsonParseError(filename string, data []byte, err error) *ParseError {
+	parseErr := &ParseError{File: filename, Problem: err.Error()}
+
+	var offset int64
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		offset = e.Offset
+		parseErr.Hint = "check for a missing comma or quote, or a trailing comma"
+	case *json.UnmarshalTypeError:
+		offset = e.Offset
+		parseErr.Problem = fmt.Sprintf("%s must be %s, not %s", e.Field, e.Type, e.Value)
+	default:
+		return parseErr
+	}
+
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	parseErr.Line = bytes.Count(before, []byte("\n")) + 1
+	parseErr.Column = int(offset) - bytes.LastIndexByte(before, '\n')
+	return parseErr
+}
@@ -0,0 +1,214 @@
+package toolkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// PayloadKey returns the LMS payload field a user-supplied name refers to.
+// Names match case-insensitively and may use the YAML spelling, so
+// "due_date", "dueDate", and "duedate" all mean dueDate.
+func PayloadKey(name string) (string, bool) {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+	for _, key := range reservedPayloadKeys {
+		if strings.ToLower(key) == normalized {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// LMSPayload is the body SyncAssignment sends for pkg, after the client's
+// transforms and field filter. Keys are renamed by the client's
+// FieldMapping last, so transforms and sync_fields use our names.
+func (c *LMSClient) LMSPayload(pkg AssignmentPackage) (map[string]interface{}, error) {
+	if c.StrictTypes {
+		if err := CheckLMSType(pkg.Assignment.Type); err != nil {
+			return nil, err
+		}
+	}
+	payload := ConvertToLMSFormat(pkg)
+	if err := ApplyPayloadTransforms(payload, c.Transforms); err != nil {
+		return nil, err
+	}
+	c.Fields.Apply(payload)
+	c.FieldMapping.Apply(payload)
+	return payload, nil
+}
+
+// SelectFields returns the named payload fields. Fields the payload
+// doesn't set are included as null so the LMS clears them.
+func SelectFields(payload map[string]interface{}, fields []string) map[string]interface{} {
+	selected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		selected[field] = payload[field]
+	}
+	return selected
+}
+
+// volatilePayloadKeys are restamped on every sync, so they always differ
+// from the LMS copy and never count as changes
+var volatilePayloadKeys = map[string]bool{"importedAt": true}
+
+// ChangedFields lists the fields of local whose values differ from the
+// LMS copy of the assignment, sorted by name. Values are compared as JSON,
+// so 5 and 5.0 are equal, and empty local values match fields the LMS
+// leaves out.
+func ChangedFields(local, remote map[string]interface{}) []string {
+	var changed []string
+	for key, value := range local {
+		if volatilePayloadKeys[key] {
+			continue
+		}
+		if remoteValue, ok := remote[key]; !ok && isEmptyValue(value) {
+			continue
+		} else if !jsonEqual(value, remoteValue) {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// isEmptyValue reports whether value is nil or an empty string, slice, or
+// map. false and 0 are real values and are not empty.
+func isEmptyValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return false
+}
+
+func jsonEqual(a, b interface{}) bool {
+	var normalized [2]interface{}
+	for i, value := range []interface{}{a, b} {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return false
+		}
+		if err := json.Unmarshal(data, &normalized[i]); err != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(normalized[0], normalized[1])
+}
+
+// GetAssignment fetches the LMS copy of an assignment as payload fields
+func (c *LMSClient) GetAssignment(id string) (map[string]interface{}, error) {
+	endpoint := fmt.Sprintf("%s/api/assignments/%s", c.BaseURL, url.PathEscape(id))
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("assignment %s does not exist in the LMS", id)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, redactSecret(string(body), c.APIKey))
+	}
+
+	var response struct {
+		Assignment map[string]interface{} `json:"assignment"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return response.Assignment, nil
+}
+
+// PatchAssignment updates only the given fields of an LMS assignment,
+// leaving everything else, including its resources, untouched
+func (c *LMSClient) PatchAssignment(id string, fields map[string]interface{}) (*ImportResult, error) {
+	jsonData, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal fields: %v", err)
+	}
+
+	if c.GzipRequests {
+		if jsonData, err = gzipBytes(jsonData); err != nil {
+			return nil, fmt.Errorf("failed to compress fields: %v", err)
+		}
+	}
+
+	endpoint := fmt.Sprintf("%s/api/assignments/%s", c.BaseURL, url.PathEscape(id))
+	req, err := http.NewRequest("PATCH", endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.GzipRequests {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("LMS rate limit still exceeded after waiting; try again later")
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("assignment %s no longer exists in the LMS; remove custom.%s to create it again", id, LMSIDKey)
+	}
+
+	if resp.StatusCode == http.StatusConflict {
+		result := parseConflictResponse(body)
+		return result, fmt.Errorf("assignment conflicts with existing LMS content: %s", strings.Join(result.Conflicts, "; "))
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, redactSecret(string(body), c.APIKey))
+	}
+
+	var response struct {
+		Message string `json:"message"`
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %v", err)
+		}
+	}
+
+	return &ImportResult{
+		AssignmentID: id,
+		Status:       "success",
+		Message:      response.Message,
+	}, nil
+}
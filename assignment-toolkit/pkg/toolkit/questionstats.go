@@ -0,0 +1,94 @@
+package toolkit
+
+// QuestionStats summarizes the questions of one assignment
+type QuestionStats struct {
+	Questions   int
+	TotalPoints int
+
+	// Points holds the points of each question: its own "points" value
+	// when it has one, otherwise an even share of the assignment points
+	// the other questions leave
+	Points []float64
+
+	// ExplicitPoints counts the questions that set their own points
+	ExplicitPoints int
+
+	// WithOptions counts the questions that offer options to choose from,
+	// and Options the options across them
+	WithOptions int
+	Options     int
+
+	// Matching counts the matching questions, and Pairs the pairs across them
+	Matching int
+	Pairs    int
+}
+
+// AverageOptions returns the mean number of options per question that has
+// options, or 0 when none do
+func (s QuestionStats) AverageOptions() float64 {
+	if s.WithOptions == 0 {
+		return 0
+	}
+	return float64(s.Options) / float64(s.WithOptions)
+}
+
+// PointDistribution counts the questions worth each number of points
+func (s QuestionStats) PointDistribution() map[float64]int {
+	distribution := make(map[float64]int)
+	for _, points := range s.Points {
+		distribution[points]++
+	}
+	return distribution
+}
+
+// AssignmentQuestionStats counts the questions of an assignment in any of
+// the shapes its types use: a single question object, a list of question
+// objects, or plain prompts
+func AssignmentQuestionStats(assignment Assignment) QuestionStats {
+	stats := QuestionStats{TotalPoints: assignment.Points}
+
+	var questions []interface{}
+	switch q := assignment.Questions.(type) {
+	case nil:
+	case []interface{}:
+		questions = q
+	default:
+		questions = []interface{}{q}
+	}
+	stats.Questions = len(questions)
+	if stats.Questions == 0 {
+		return stats
+	}
+
+	remaining := float64(assignment.Points)
+	for _, item := range questions {
+		question, _ := item.(map[string]interface{})
+		if value, ok := numberValue(question["points"]); ok {
+			remaining -= value
+			stats.ExplicitPoints++
+		}
+	}
+	var share float64
+	if stats.ExplicitPoints < stats.Questions && remaining > 0 {
+		share = remaining / float64(stats.Questions-stats.ExplicitPoints)
+	}
+
+	for _, item := range questions {
+		question, _ := item.(map[string]interface{})
+		points, ok := numberValue(question["points"])
+		if !ok {
+			points = share
+		}
+		stats.Points = append(stats.Points, points)
+
+		if options := questionOptions(question); len(options) > 0 {
+			stats.WithOptions++
+			stats.Options += len(options)
+		}
+		if _, ok := question["pairs"]; ok || question["leftItems"] != nil {
+			stats.Matching++
+			stats.Pairs += len(MatchingPairs(question))
+		}
+	}
+	return stats
+}
@@ -0,0 +1,35 @@
+package toolkit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAssignmentQuestionStats(t *testing.T) {
+	stats := AssignmentQuestionStats(Assignment{Points: 10, Questions: []interface{}{
+		map[string]interface{}{"question": "Q1", "options": []interface{}{"a", "b", "c", "d"}, "points": 4},
+		map[string]interface{}{"question": "Q2", "options": []interface{}{"a", "b"}},
+		map[string]interface{}{"pairs": []interface{}{
+			map[string]interface{}{"left": "cat", "right": "แมว"},
+			map[string]interface{}{"left": "dog", "right": "หมา"},
+		}},
+	}})
+
+	if stats.Questions != 3 || stats.ExplicitPoints != 1 {
+		t.Errorf("questions = %d, explicit = %d; want 3 and 1", stats.Questions, stats.ExplicitPoints)
+	}
+	if want := []float64{4, 3, 3}; !reflect.DeepEqual(stats.Points, want) {
+		t.Errorf("points = %v, want %v", stats.Points, want)
+	}
+	if stats.AverageOptions() != 3 {
+		t.Errorf("average options = %v, want 3", stats.AverageOptions())
+	}
+	if stats.Matching != 1 || stats.Pairs != 2 {
+		t.Errorf("matching = %d with %d pairs, want 1 with 2", stats.Matching, stats.Pairs)
+	}
+
+	single := AssignmentQuestionStats(Assignment{Points: 5, Questions: map[string]interface{}{"question": "Only"}})
+	if single.Questions != 1 || !reflect.DeepEqual(single.Points, []float64{5}) {
+		t.Errorf("single question stats = %+v", single)
+	}
+}
@@ -0,0 +1,117 @@
+package toolkit
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultMaxRateLimitWait caps the total time one request spends waiting
+// out 429 responses when LMSClient.MaxRateLimitWait is not set
+const DefaultMaxRateLimitWait = 2 * time.Minute
+
+// maxRateLimitRetries bounds how often a single request is retried after 429
+const maxRateLimitRetries = 5
+
+// sleep is replaced in tests
+var sleep = time.Sleep
+
+// do sends req, signing it first when the client has Signing set, and
+// waiting and retrying while the LMS answers 429 Too Many Requests. The
+// Retry-After header is honored (seconds or an HTTP date); without it the
+// wait doubles from one second. Once the total wait would exceed the cap
+// or run past the client's deadline, the 429 response is returned to the
+// caller. Network errors and 5xx responses are retried as the client's
+// Retry config allows. A request still running at the deadline is
+// cancelled.
+func (c *LMSClient) do(req *http.Request) (*http.Response, error) {
+	budget := c.MaxRateLimitWait
+	if budget <= 0 {
+		budget = DefaultMaxRateLimitWait
+	}
+
+	req, cancel := c.withDeadline(req)
+	resp, err := c.send(req, budget)
+	if err != nil || resp == nil {
+		cancel()
+		return resp, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// send is do without the deadline handling
+func (c *LMSClient) send(req *http.Request, budget time.Duration) (*http.Response, error) {
+	deadline := c.requestDeadline()
+	start := time.Now()
+
+	backoff := time.Second
+	retryBackoff := c.Retry.initialBackoff()
+	retries, rateLimited := 0, 0
+	for {
+		if c.Signing.Enabled() {
+			if err := c.Signing.signRequest(req); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := c.HTTPClient.Do(req)
+
+		var wait time.Duration
+		switch {
+		case transient(req, resp, err):
+			if retries >= c.Retry.MaxRetries || time.Since(start)+retryBackoff > c.Retry.maxElapsed() {
+				return resp, err
+			}
+			wait = retryBackoff
+			retryBackoff *= 2
+			retries++
+		case err != nil || resp.StatusCode != http.StatusTooManyRequests || rateLimited == maxRateLimitRetries:
+			return resp, err
+		default:
+			rateLimited++
+			var ok bool
+			if wait, ok = retryAfter(resp.Header.Get("Retry-After")); !ok {
+				wait = backoff
+				backoff *= 2
+			}
+			if wait > budget {
+				return resp, nil
+			}
+			budget -= wait
+		}
+		if (req.Body != nil && req.GetBody == nil) || (!deadline.IsZero() && time.Now().Add(wait).After(deadline)) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		sleep(wait)
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// retryAfter parses a Retry-After header given as seconds or an HTTP date
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := date.Sub(Now())
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
@@ -0,0 +1,50 @@
+package toolkit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDoHonorsRetryAfter(t *testing.T) {
+	var waits []time.Duration
+	sleep = func(d time.Duration) { waits = append(waits, d) }
+	t.Cleanup(func() { sleep = time.Sleep })
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	if err := NewLMSClient(server.URL, "key").TestConnection(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 || len(waits) != 2 || waits[0] != 7*time.Second {
+		t.Errorf("calls = %d, waits = %v; want 3 calls after two 7s waits", calls, waits)
+	}
+}
+
+func TestDoGivesUpPastWaitCap(t *testing.T) {
+	sleep = func(time.Duration) { t.Error("should not wait past the cap") }
+	t.Cleanup(func() { sleep = time.Sleep })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewLMSClient(server.URL, "key")
+	client.MaxRateLimitWait = time.Minute
+	if err := client.TestConnection(); err == nil {
+		t.Error("expected an error once the wait cap is exceeded")
+	}
+}
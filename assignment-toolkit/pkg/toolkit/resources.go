@@ -0,0 +1,90 @@
+package toolkit
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// RenumberResources sorts resources by their current order, then title,
+// and assigns contiguous orders starting at 1. It reports whether anything
+// changed.
+func RenumberResources(pkg *AssignmentPackage) bool {
+	resources := append([]Resource{}, pkg.Resources...)
+	sort.SliceStable(resources, func(i, j int) bool {
+		if resources[i].Order != resources[j].Order {
+			return resources[i].Order < resources[j].Order
+		}
+		return resources[i].Title < resources[j].Title
+	})
+
+	changed := false
+	for i := range resources {
+		if resources[i].Order != i+1 || resources[i].ID != pkg.Resources[i].ID {
+			changed = true
+		}
+		resources[i].Order = i + 1
+	}
+	pkg.Resources = resources
+	return changed
+}
+
+// ResourcePlan describes what packaging or syncing would do with one
+// resource or question media file
+type ResourcePlan struct {
+	Title  string
+	Kind   string // resource type, or "image"/"audio" for question media
+	Path   string // resolved local path; empty for URL-only resources
+	URL    string // URL as sent to the LMS, after url_rewrites
+	Exists bool
+	Size   int64
+	Error  string // why a local file can't be used
+}
+
+// Local reports whether the resource is a file to copy and upload rather
+// than a link
+func (p ResourcePlan) Local() bool {
+	return p.Path != ""
+}
+
+// PlanResources resolves the package's resources and question media
+// without copying or uploading anything. Relative paths are taken from
+// resourceDir when it is set.
+func PlanResources(pkg AssignmentPackage, resourceDir string) []ResourcePlan {
+	resolve := func(path string) string {
+		if resourceDir != "" && !filepath.IsAbs(path) {
+			return filepath.Join(resourceDir, path)
+		}
+		return path
+	}
+	stat := func(plan *ResourcePlan) {
+		info, err := os.Stat(plan.Path)
+		switch {
+		case os.IsNotExist(err):
+			plan.Error = "file not found"
+		case err != nil:
+			plan.Error = err.Error()
+		case info.IsDir():
+			plan.Error = "is a directory"
+		default:
+			plan.Exists = true
+			plan.Size = info.Size()
+		}
+	}
+
+	var plans []ResourcePlan
+	for _, resource := range pkg.Resources {
+		plan := ResourcePlan{Title: resource.Title, Kind: resource.Type, URL: RewriteURL(resource.URL)}
+		if resource.LocalPath != "" {
+			plan.Path = resolve(resource.LocalPath)
+			stat(&plan)
+		}
+		plans = append(plans, plan)
+	}
+	for _, file := range questionMediaFiles(pkg) {
+		plan := ResourcePlan{Title: filepath.Base(file.Path), Kind: file.Kind, Path: resolve(file.Path)}
+		stat(&plan)
+		plans = append(plans, plan)
+	}
+	return plans
+}
@@ -0,0 +1,65 @@
+package toolkit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRenumberResources(t *testing.T) {
+	pkg := AssignmentPackage{Resources: []Resource{
+		{ID: "c", Title: "Worksheet", Order: 2},
+		{ID: "b", Title: "Map", Order: 0},
+		{ID: "a", Title: "Atlas", Order: 0},
+	}}
+
+	if findings := checkResourceOrder(pkg); len(findings) != 1 {
+		t.Errorf("findings = %v, want one duplicate order", findings)
+	}
+
+	if !RenumberResources(&pkg) {
+		t.Fatal("expected resources to be renumbered")
+	}
+
+	var got []string
+	for _, resource := range pkg.Resources {
+		got = append(got, fmt.Sprintf("%s:%d", resource.ID, resource.Order))
+	}
+	if want := []string{"a:1", "b:2", "c:3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("renumbered = %v, want %v", got, want)
+	}
+	if RenumberResources(&pkg) {
+		t.Error("renumbering an ordered package should report no change")
+	}
+}
+
+func TestPlanResources(t *testing.T) {
+	defer UseConfig(Config{})
+	UseConfig(Config{URLRewrites: []URLRewrite{{From: "https://cdn.example.com/", To: "https://proxy.example.org/"}}})
+
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "map.png"), []byte("image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pkg := AssignmentPackage{
+		Assignment: Assignment{Questions: map[string]interface{}{
+			"leftItems":  []interface{}{map[string]interface{}{"audio": "clip.mp3"}},
+			"rightItems": []interface{}{"cat"},
+		}},
+		Resources: []Resource{
+			{Title: "Map", Type: "image", LocalPath: "map.png"},
+			{Title: "Video", Type: "video", URL: "https://cdn.example.com/v.mp4"},
+		},
+	}
+
+	want := []ResourcePlan{
+		{Title: "Map", Kind: "image", Path: filepath.Join(dir, "map.png"), Exists: true, Size: 5},
+		{Title: "Video", Kind: "video", URL: "https://proxy.example.org/v.mp4"},
+		{Title: "clip.mp3", Kind: "audio", Path: filepath.Join(dir, "clip.mp3"), Error: "file not found"},
+	}
+	if got := PlanResources(pkg, dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("plan = %+v, want %+v", got, want)
+	}
+}
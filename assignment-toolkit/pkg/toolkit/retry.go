@@ -0,0 +1,64 @@
+package toolkit
+
+import (
+	"net/http"
+	"time"
+)
+
+// RetryConfig controls how LMS requests that fail with a network error or
+// a 5xx response are retried. 4xx responses are never retried; 429 is
+// handled separately (see MaxRateLimitWait).
+type RetryConfig struct {
+	// MaxRetries is how often a request is retried after the first
+	// attempt. Zero (or less) disables retrying.
+	MaxRetries int
+
+	// InitialBackoff is the wait before the first retry; it doubles after
+	// each one. Zero uses DefaultRetryConfig's.
+	InitialBackoff time.Duration
+
+	// MaxElapsed bounds the time one request may take, attempts and waits
+	// included, before no further retry is started. Zero uses
+	// DefaultRetryConfig's.
+	MaxElapsed time.Duration
+}
+
+// DefaultRetryConfig retries a failed request three times, waiting one,
+// two, then four seconds, within thirty seconds in all
+var DefaultRetryConfig = RetryConfig{
+	MaxRetries:     3,
+	InitialBackoff: time.Second,
+	MaxElapsed:     30 * time.Second,
+}
+
+// NewLMSClientWithRetry creates an LMS client that retries transient
+// failures as retry describes
+func NewLMSClientWithRetry(baseURL, apiKey string, retry RetryConfig) *LMSClient {
+	client := NewLMSClient(baseURL, apiKey)
+	client.Retry = retry
+	return client
+}
+
+// transient reports whether a request that got resp or err may succeed
+// when sent again
+func transient(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		// A cancelled request or a passed deadline won't get better
+		return req.Context().Err() == nil
+	}
+	return resp.StatusCode >= 500
+}
+
+func (r RetryConfig) initialBackoff() time.Duration {
+	if r.InitialBackoff > 0 {
+		return r.InitialBackoff
+	}
+	return DefaultRetryConfig.InitialBackoff
+}
+
+func (r RetryConfig) maxElapsed() time.Duration {
+	if r.MaxElapsed > 0 {
+		return r.MaxElapsed
+	}
+	return DefaultRetryConfig.MaxElapsed
+}
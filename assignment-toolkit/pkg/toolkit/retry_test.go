@@ -0,0 +1,140 @@
+package toolkit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDoRetriesServerErrorsWithBackoff(t *testing.T) {
+	var waits []time.Duration
+	sleep = func(d time.Duration) { waits = append(waits, d) }
+	t.Cleanup(func() { sleep = time.Sleep })
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewLMSClientWithRetry(server.URL, "key", DefaultRetryConfig)
+	if err := client.TestConnection(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 || len(waits) != 2 || waits[0] != time.Second || waits[1] != 2*time.Second {
+		t.Errorf("calls = %d, waits = %v; want 3 calls after 1s and 2s waits", calls, waits)
+	}
+}
+
+func TestDoStopsAfterMaxRetries(t *testing.T) {
+	sleep = func(time.Duration) {}
+	t.Cleanup(func() { sleep = time.Sleep })
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewLMSClientWithRetry(server.URL, "key", RetryConfig{MaxRetries: 2})
+	if err := client.TestConnection(); err == nil {
+		t.Error("expected an error once retries run out")
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestDoDoesNotRetryClientErrors(t *testing.T) {
+	sleep = func(time.Duration) { t.Error("should not retry a 4xx response") }
+	t.Cleanup(func() { sleep = time.Sleep })
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewLMSClientWithRetry(server.URL, "key", DefaultRetryConfig)
+	if err := client.TestConnection(); err == nil {
+		t.Error("expected an error for a 400 response")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestDoWithoutRetryConfigSendsOnce(t *testing.T) {
+	sleep = func(time.Duration) { t.Error("a client without retry config should not retry") }
+	t.Cleanup(func() { sleep = time.Sleep })
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if err := NewLMSClient(server.URL, "key").TestConnection(); err == nil {
+		t.Error("expected an error for a 500 response")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestDoWithNegativeMaxRetriesSendsOnce(t *testing.T) {
+	sleep = func(time.Duration) { t.Error("negative MaxRetries should not retry") }
+	t.Cleanup(func() { sleep = time.Sleep })
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewLMSClientWithRetry(server.URL, "key", RetryConfig{MaxRetries: -1})
+	if err := client.TestConnection(); err == nil {
+		t.Error("expected an error for a 502 response")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestServerErrorRetriesLeaveRateLimitRetries(t *testing.T) {
+	sleep = func(time.Duration) {}
+	t.Cleanup(func() { sleep = time.Sleep })
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch {
+		case calls <= 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case calls <= 3+maxRateLimitRetries:
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	client := NewLMSClientWithRetry(server.URL, "key", DefaultRetryConfig)
+	if err := client.TestConnection(); err != nil {
+		t.Fatal(err)
+	}
+	if want := 4 + maxRateLimitRetries; calls != want {
+		t.Errorf("calls = %d, want %d", calls, want)
+	}
+}
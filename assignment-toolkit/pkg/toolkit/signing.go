@@ -0,0 +1,87 @@
+package toolkit
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Default header names for signed requests
+const (
+	DefaultSignatureHeader = "X-Signature"
+	DefaultTimestampHeader = "X-Timestamp"
+)
+
+// RequestSigning adds an HMAC-SHA256 signature to every LMS request for
+// deployments that require one. The bearer token is still sent. The
+// signature is the hex HMAC of
+//
+//	METHOD \n /path?query \n unix-timestamp \n hex(sha256(body))
+//
+// keyed with Secret; the timestamp goes in its own header.
+type RequestSigning struct {
+	Secret          string `json:"secret,omitempty" yaml:"secret,omitempty"`
+	SignatureHeader string `json:"signature_header,omitempty" yaml:"signature_header,omitempty"`
+	TimestampHeader string `json:"timestamp_header,omitempty" yaml:"timestamp_header,omitempty"`
+}
+
+// Enabled reports whether requests should be signed
+func (s RequestSigning) Enabled() bool {
+	return s.Secret != ""
+}
+
+// Sign computes the signature for a request
+func (s RequestSigning) Sign(method, requestURI, timestamp string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	message := strings.Join([]string{method, requestURI, timestamp, hex.EncodeToString(bodyHash[:])}, "\n")
+
+	mac := hmac.New(sha256.New, []byte(s.Secret))
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// signRequest stamps req with a fresh timestamp and signature. It runs
+// before every attempt so retried requests are not rejected as stale.
+func (s RequestSigning) signRequest(req *http.Request) error {
+	var body []byte
+	if req.Body != nil {
+		reader := req.Body
+		if req.GetBody != nil {
+			var err error
+			if reader, err = req.GetBody(); err != nil {
+				return err
+			}
+		}
+		data, err := ioutil.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return err
+		}
+		body = data
+		if req.GetBody == nil {
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+			req.GetBody = func() (io.ReadCloser, error) {
+				return ioutil.NopCloser(bytes.NewReader(body)), nil
+			}
+		}
+	}
+
+	signatureHeader, timestampHeader := s.SignatureHeader, s.TimestampHeader
+	if signatureHeader == "" {
+		signatureHeader = DefaultSignatureHeader
+	}
+	if timestampHeader == "" {
+		timestampHeader = DefaultTimestampHeader
+	}
+
+	timestamp := strconv.FormatInt(Now().Unix(), 10)
+	req.Header.Set(timestampHeader, timestamp)
+	req.Header.Set(signatureHeader, s.Sign(req.Method, req.URL.RequestURI(), timestamp, body))
+	return nil
+}
@@ -0,0 +1,47 @@
+package toolkit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSignedRequestsVerifyOnServer(t *testing.T) {
+	useFixedClock(t)
+	const secret = "shared-secret"
+
+	var signed int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodyHash := sha256.Sum256(body)
+		message := strings.Join([]string{r.Method, r.URL.RequestURI(), r.Header.Get("X-Lms-Time"), hex.EncodeToString(bodyHash[:])}, "\n")
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(message))
+		if !hmac.Equal([]byte(r.Header.Get("X-Lms-Signature")), []byte(hex.EncodeToString(mac.Sum(nil)))) {
+			t.Errorf("%s %s: bad signature", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("bearer token missing: %q", r.Header.Get("Authorization"))
+		}
+		signed++
+		w.Write([]byte(`{"assignment": {"id": "asg-1"}}`))
+	}))
+	defer server.Close()
+
+	client := NewLMSClient(server.URL, "key")
+	client.Signing = RequestSigning{Secret: secret, SignatureHeader: "X-Lms-Signature", TimestampHeader: "X-Lms-Time"}
+	if _, err := client.PatchAssignment("asg-1", map[string]interface{}{"title": "Signed"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetAssignment("asg-1"); err != nil {
+		t.Fatal(err)
+	}
+	if signed != 2 {
+		t.Errorf("server saw %d signed requests, want 2", signed)
+	}
+}
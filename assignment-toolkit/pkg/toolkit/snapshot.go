@@ -0,0 +1,143 @@
+package toolkit
+
+import (
+	"archive/zip"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SnapshotDir is the workspace directory holding snapshots: zip archives
+// of the assignment files and configuration, taken before risky bulk
+// changes so they can be rolled back
+const SnapshotDir = ".snapshots"
+
+// SnapshotInfo describes a snapshot archive
+type SnapshotInfo struct {
+	Name    string // file name without .zip
+	Path    string
+	Created time.Time
+	Files   int
+	Size    int64
+}
+
+// SnapshotPath returns a new, unused path under dir for a snapshot taken
+// now, e.g. .snapshots/snapshot-20240101-120000-before-reindex.zip
+func SnapshotPath(dir, label string) string {
+	name := "snapshot-" + Now().Format("20060102-150405")
+	if label = strings.Trim(strings.Join(strings.FieldsFunc(label, func(r rune) bool {
+		return r == '/' || r == '\\' || r == ' '
+	}), "-"), "-."); label != "" {
+		name += "-" + label
+	}
+
+	path := filepath.Join(dir, name+".zip")
+	for n := 2; ; n++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+		path = filepath.Join(dir, fmt.Sprintf("%s-%d.zip", name, n))
+	}
+}
+
+// WriteSnapshot archives files, given relative to the workspace, into a
+// zip at output, keeping their paths
+func WriteSnapshot(output string, files []string) error {
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	writer := zip.NewWriter(out)
+
+	for _, file := range files {
+		name := filepath.ToSlash(filepath.Clean(file))
+		if filepath.IsAbs(file) || strings.HasPrefix(name, "../") {
+			err = fmt.Errorf("%s is outside the workspace", file)
+			break
+		}
+		if err = addZipFile(writer, name, file); err != nil {
+			break
+		}
+	}
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(output)
+	}
+	return err
+}
+
+// ListSnapshots returns the snapshots in dir, newest first
+func ListSnapshots(dir string) ([]SnapshotInfo, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []SnapshotInfo
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".zip" {
+			continue
+		}
+		info := SnapshotInfo{
+			Name:    strings.TrimSuffix(entry.Name(), ".zip"),
+			Path:    filepath.Join(dir, entry.Name()),
+			Created: entry.ModTime(),
+			Size:    entry.Size(),
+		}
+		if reader, err := zip.OpenReader(info.Path); err == nil {
+			info.Files = len(reader.File)
+			reader.Close()
+		}
+		snapshots = append(snapshots, info)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Created.After(snapshots[j].Created)
+	})
+	return snapshots, nil
+}
+
+// RestoreSnapshot writes every file in the snapshot back to its place under
+// dest, replacing the current copy, and returns the restored paths. Files
+// created since the snapshot are left alone.
+func RestoreSnapshot(archive, dest string) ([]string, error) {
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		name := filepath.FromSlash(file.Name)
+		if filepath.IsAbs(name) || strings.HasPrefix(filepath.Clean(name), "..") {
+			return nil, fmt.Errorf("snapshot entry %q escapes the workspace", file.Name)
+		}
+	}
+
+	var restored []string
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		target := filepath.Join(dest, filepath.FromSlash(file.Name))
+		if err := extractZipFile(file, target); err != nil {
+			return restored, err
+		}
+		restored = append(restored, target)
+	}
+	return restored, nil
+}
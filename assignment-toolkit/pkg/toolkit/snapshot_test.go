@@ -0,0 +1,55 @@
+package toolkit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	useFixedClock(t)
+	workspace := t.TempDir()
+	wd, _ := os.Getwd()
+	if err := os.Chdir(workspace); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	os.MkdirAll("q1", 0755)
+	files := map[string]string{"quiz.yaml": "original quiz", filepath.Join("q1", "essay.yaml"): "original essay"}
+	for name, content := range files {
+		if err := ioutil.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := SnapshotPath(SnapshotDir, "before reindex")
+	if filepath.Base(path) != "snapshot-"+Now().Format("20060102-150405")+"-before-reindex.zip" {
+		t.Errorf("snapshot path = %s", path)
+	}
+	if err := WriteSnapshot(path, []string{"quiz.yaml", filepath.Join("q1", "essay.yaml")}); err != nil {
+		t.Fatal(err)
+	}
+	if again := SnapshotPath(SnapshotDir, "before reindex"); again == path {
+		t.Errorf("second snapshot in the same second reuses %s", path)
+	}
+
+	ioutil.WriteFile("quiz.yaml", []byte("broken by a bulk edit"), 0644)
+	os.Remove(filepath.Join("q1", "essay.yaml"))
+
+	restored, err := RestoreSnapshot(path, ".")
+	if err != nil || len(restored) != 2 {
+		t.Fatalf("restored %v, err %v", restored, err)
+	}
+	for name, content := range files {
+		if data, _ := ioutil.ReadFile(name); string(data) != content {
+			t.Errorf("%s = %q after restore, want %q", name, data, content)
+		}
+	}
+
+	snapshots, err := ListSnapshots(SnapshotDir)
+	if err != nil || len(snapshots) != 1 || snapshots[0].Files != 2 || snapshots[0].Name != "snapshot-"+Now().Format("20060102-150405")+"-before-reindex" {
+		t.Errorf("ListSnapshots = %+v, err %v", snapshots, err)
+	}
+}
@@ -0,0 +1,908 @@
+package toolkit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LMSClient handles communication with the LMS API
+type LMSClient struct {
+	BaseURL    string
+	APIKey     string
+	HTTPClient *http.Client
+	Transforms []PayloadTransform
+	Fields     FieldFilter
+	// FieldMapping renames payload keys for the LMS's schema
+	FieldMapping FieldMapping
+
+	// GzipRequests compresses JSON request bodies; the server must accept
+	// Content-Encoding: gzip
+	GzipRequests bool
+
+	// ChunkSize is the part size for resumable resource uploads; files no
+	// larger than it are sent in a single request. Zero uses DefaultChunkSize.
+	ChunkSize int64
+
+	// OnlyChangedResources links resources the LMS already holds (matched by
+	// checksum) instead of uploading them again
+	OnlyChangedResources bool
+
+	// StrictTypes rejects assignments whose type has no LMS mapping instead
+	// of sending the type unchanged
+	StrictTypes bool
+
+	// MaxRateLimitWait caps the time spent waiting out 429 responses per
+	// request. Zero uses DefaultMaxRateLimitWait.
+	MaxRateLimitWait time.Duration
+
+	// Retry retries requests that fail with a network error or a 5xx
+	// response. The zero value doesn't retry; see NewLMSClientWithRetry.
+	Retry RetryConfig
+
+	// Signing adds an HMAC signature to every request when its Secret is set
+	Signing RequestSigning
+
+	// ResourceTimeout cancels a resource upload, including its chunks and
+	// retries, that takes longer than this. Zero means no limit.
+	ResourceTimeout time.Duration
+
+	// Deadline cancels requests still running when it passes;
+	// BatchSyncAssignments starts no new assignments after it. The zero
+	// time means no deadline.
+	Deadline time.Time
+
+	resourceDeadline time.Time
+}
+
+// NewLMSClient creates a new LMS client
+func NewLMSClient(baseURL, apiKey string) *LMSClient {
+	return &LMSClient{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		APIKey:     apiKey,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// LMSIDKey is the custom metadata key holding the ID of the LMS assignment
+// a package was imported from or first synced as. Syncing such a package
+// updates that assignment rather than creating a new one.
+const LMSIDKey = "lms_id"
+
+// LMSAssignmentID returns the LMS assignment a package maps to, or ""
+func LMSAssignmentID(pkg AssignmentPackage) string {
+	return pkg.Metadata.Custom[LMSIDKey]
+}
+
+// SyncAssignment uploads an assignment to the LMS, updating the assignment
+// named by custom.lms_id when the package has one
+func (c *LMSClient) SyncAssignment(pkg AssignmentPackage) (*ImportResult, error) {
+	req, _, err := c.syncRequest(pkg)
+	if err != nil {
+		return nil, err
+	}
+	lmsID := LMSAssignmentID(pkg)
+
+	// Send request
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("LMS rate limit still exceeded after waiting; try again later")
+	}
+
+	if lmsID != "" && resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("assignment %s no longer exists in the LMS; remove custom.%s to create it again", lmsID, LMSIDKey)
+	}
+
+	if resp.StatusCode == http.StatusConflict {
+		result := parseConflictResponse(body)
+		return result, fmt.Errorf("assignment conflicts with existing LMS content: %s", strings.Join(result.Conflicts, "; "))
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, redactSecret(string(body), c.APIKey))
+	}
+
+	// Parse response
+	var response struct {
+		Assignment struct {
+			ID string `json:"id"`
+		} `json:"assignment"`
+		Message   string            `json:"message"`
+		Conflicts []json.RawMessage `json:"conflicts"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	if response.Assignment.ID == "" {
+		response.Assignment.ID = lmsID
+	}
+
+	result := &ImportResult{
+		AssignmentID: response.Assignment.ID,
+		Conflicts:    describeConflicts(response.Conflicts),
+		Status:       "success",
+		Message:      response.Message,
+	}
+
+	// Upload resources, including images attached to question options
+	c.attachResources(result, syncResources(pkg))
+
+	return result, nil
+}
+
+// syncRequest builds the request SyncAssignment sends for pkg, returning
+// its body as well
+func (c *LMSClient) syncRequest(pkg AssignmentPackage) (*http.Request, []byte, error) {
+	// Convert assignment to LMS format
+	lmsAssignment, err := c.LMSPayload(pkg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create JSON payload
+	jsonData, err := json.Marshal(lmsAssignment)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal assignment: %v", err)
+	}
+
+	if c.GzipRequests {
+		if jsonData, err = gzipBytes(jsonData); err != nil {
+			return nil, nil, fmt.Errorf("failed to compress assignment: %v", err)
+		}
+	}
+
+	// Create HTTP request; assignments imported from the LMS update the
+	// original instead of creating a copy
+	method, endpoint := "POST", fmt.Sprintf("%s/api/assignments", c.BaseURL)
+	if lmsID := LMSAssignmentID(pkg); lmsID != "" {
+		method, endpoint = "PUT", fmt.Sprintf("%s/api/assignments/%s", c.BaseURL, url.PathEscape(lmsID))
+	}
+	req, err := http.NewRequest(method, endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+	if c.GzipRequests {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+	return req, jsonData, nil
+}
+
+// syncResources returns the resources uploaded with pkg: its own resources
+// followed by the local files of question media
+func syncResources(pkg AssignmentPackage) []Resource {
+	return append(append([]Resource{}, pkg.Resources...), questionMediaResources(pkg)...)
+}
+
+// attachResources uploads resources to result's assignment. When any fail
+// the result becomes "partial" and lists them in FailedResources.
+func (c *LMSClient) attachResources(result *ImportResult, resources []Resource) {
+	if len(resources) == 0 {
+		return
+	}
+	resourceIDs, failed, err := c.uploadResources(result.AssignmentID, resources)
+	result.ResourceIDs = resourceIDs
+	if err != nil {
+		result.Status = "partial"
+		result.Message = strings.TrimSpace(result.Message + fmt.Sprintf(" Warning: Resource upload failed: %v", err))
+		for _, resource := range failed {
+			result.FailedResources = append(result.FailedResources, filepath.Base(resource.LocalPath))
+		}
+	}
+}
+
+// UploadResources uploads the resources of pkg, question media included,
+// to an assignment that already exists in the LMS, without syncing the
+// assignment itself. names limits it to the resources whose files have
+// those names, as listed in FailedResources after a partial sync; with no
+// names every resource is uploaded.
+func (c *LMSClient) UploadResources(pkg AssignmentPackage, assignmentID string, names []string) (*ImportResult, error) {
+	if assignmentID == "" {
+		return nil, fmt.Errorf("no LMS assignment to upload resources to")
+	}
+
+	var resources []Resource
+	found := make(map[string]bool, len(names))
+	for _, resource := range syncResources(pkg) {
+		name := filepath.Base(resource.LocalPath)
+		if resource.LocalPath == "" || (len(names) > 0 && !containsFold(names, name)) {
+			continue
+		}
+		found[strings.ToLower(name)] = true
+		resources = append(resources, resource)
+	}
+
+	var missing []string
+	for _, name := range names {
+		if !found[strings.ToLower(name)] {
+			missing = append(missing, name)
+		}
+	}
+	if len(resources) == 0 && len(missing) > 0 {
+		return nil, fmt.Errorf("%s no longer in the package", strings.Join(missing, ", "))
+	}
+
+	result := &ImportResult{AssignmentID: assignmentID, Status: "success"}
+	if len(missing) > 0 {
+		result.Message = fmt.Sprintf("Skipped %s, no longer in the package.", strings.Join(missing, ", "))
+	}
+	c.attachResources(result, resources)
+	return result, nil
+}
+
+// parseConflictResponse reads the body of a 409 response. The LMS is
+// expected to send {"message": "...", "conflicts": [...]}, but any body is
+// accepted so the reason is never lost.
+func parseConflictResponse(body []byte) *ImportResult {
+	var response struct {
+		Assignment struct {
+			ID string `json:"id"`
+		} `json:"assignment"`
+		Message   string            `json:"message"`
+		Conflicts []json.RawMessage `json:"conflicts"`
+	}
+
+	result := &ImportResult{Status: "conflict"}
+	if err := json.Unmarshal(body, &response); err != nil {
+		result.Conflicts = []string{strings.TrimSpace(string(body))}
+		return result
+	}
+
+	result.AssignmentID = response.Assignment.ID
+	result.Message = response.Message
+	result.Conflicts = describeConflicts(response.Conflicts)
+	if len(result.Conflicts) == 0 && response.Message != "" {
+		result.Conflicts = []string{response.Message}
+	}
+	return result
+}
+
+// describeConflicts turns the LMS conflict entries into readable lines.
+// Entries may be plain strings or objects naming the conflicting field,
+// its value, and the existing assignment.
+func describeConflicts(raw []json.RawMessage) []string {
+	var conflicts []string
+	for _, entry := range raw {
+		var text string
+		if err := json.Unmarshal(entry, &text); err == nil {
+			conflicts = append(conflicts, text)
+			continue
+		}
+
+		var conflict struct {
+			Field      string      `json:"field"`
+			Value      interface{} `json:"value"`
+			ExistingID string      `json:"existingId"`
+			Message    string      `json:"message"`
+		}
+		if err := json.Unmarshal(entry, &conflict); err != nil {
+			conflicts = append(conflicts, string(entry))
+			continue
+		}
+
+		description := conflict.Message
+		if conflict.Field != "" {
+			description = fmt.Sprintf("%s %v already used", conflict.Field, conflict.Value)
+			if conflict.ExistingID != "" {
+				description += " by assignment " + conflict.ExistingID
+			}
+			if conflict.Message != "" {
+				description += ": " + conflict.Message
+			}
+		}
+		if description == "" && conflict.ExistingID != "" {
+			description = "conflicts with assignment " + conflict.ExistingID
+		}
+		if description == "" {
+			description = string(entry)
+		}
+		conflicts = append(conflicts, description)
+	}
+	return conflicts
+}
+
+// BatchSyncAssignments uploads multiple assignments. Once the client's
+// Deadline passes, the assignment in flight is cancelled and the rest are
+// reported as skipped.
+func (c *LMSClient) BatchSyncAssignments(packages []AssignmentPackage) (*BatchImportResult, error) {
+	result := &BatchImportResult{
+		BatchID:      uuid.New().String(),
+		TotalCount:   len(packages),
+		SuccessCount: 0,
+		FailureCount: 0,
+		Results:      make([]ImportResult, 0, len(packages)),
+		StartedAt:    Now(),
+	}
+
+	for _, pkg := range packages {
+		if expired(c.Deadline) {
+			result.SkippedCount++
+			result.Results = append(result.Results, ImportResult{
+				Status:  "skipped",
+				Message: fmt.Sprintf("%s not synced: batch deadline reached", pkg.Assignment.Title),
+			})
+			continue
+		}
+		if pkg.Metadata.Archived {
+			result.SkippedCount++
+			result.Results = append(result.Results, ImportResult{
+				Status:  "skipped",
+				Message: fmt.Sprintf("%s not synced: archived", pkg.Assignment.Title),
+			})
+			continue
+		}
+
+		importResult, err := c.SyncAssignment(pkg)
+		if err != nil {
+			result.FailureCount++
+			failed := ImportResult{Status: "failed", Message: err.Error()}
+			if importResult != nil {
+				failed.Status = importResult.Status
+				failed.Conflicts = importResult.Conflicts
+			}
+			result.Results = append(result.Results, failed)
+		} else {
+			result.SuccessCount++
+			result.Results = append(result.Results, *importResult)
+		}
+	}
+
+	result.CompletedAt = Now()
+	return result, nil
+}
+
+// uploadResources uploads resource files to the LMS. A failed upload
+// doesn't stop the rest; the resources that failed are returned alongside
+// an error describing each failure.
+func (c *LMSClient) uploadResources(assignmentID string, resources []Resource) ([]string, []Resource, error) {
+	var resourceIDs []string
+	var failed []Resource
+	var problems []string
+
+	for _, resource := range resources {
+		if resource.LocalPath == "" {
+			continue // Skip resources without local files
+		}
+
+		resourceID, err := c.uploadOneResource(assignmentID, resource)
+		if err != nil {
+			failed = append(failed, resource)
+			problems = append(problems, err.Error())
+			continue
+		}
+		resourceIDs = append(resourceIDs, resourceID)
+	}
+
+	if len(problems) > 0 {
+		return resourceIDs, failed, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return resourceIDs, nil, nil
+}
+
+// uploadOneResource uploads a resource file, or links the LMS copy when
+// OnlyChangedResources is set and the LMS already has the same file
+func (c *LMSClient) uploadOneResource(assignmentID string, resource Resource) (string, error) {
+	checksum, err := FileChecksum(resource.LocalPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %v", resource.Title, err)
+	}
+	resource.Checksum = checksum
+	resource.URL = RewriteURL(resource.URL)
+
+	if c.OnlyChangedResources {
+		existingID, err := c.FindResourceByChecksum(checksum)
+		if err != nil {
+			return "", fmt.Errorf("failed to look up %s: %v", resource.Title, err)
+		}
+		if existingID != "" {
+			if err := c.linkResource(existingID, assignmentID); err != nil {
+				return "", fmt.Errorf("failed to link %s: %v", resource.Title, err)
+			}
+			return existingID, nil
+		}
+	}
+
+	resourceID, err := c.uploadResourceWithin(assignmentID, resource)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload %s: %v", resource.Title, err)
+	}
+	return resourceID, nil
+}
+
+// uploadResource uploads a single resource file, switching to a chunked
+// upload for files larger than the chunk size
+func (c *LMSClient) uploadResource(assignmentID string, resource Resource) (string, error) {
+	// Open file
+	file, err := os.Open(resource.LocalPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to stat file: %v", err)
+	}
+	if info.Size() > c.chunkSize() {
+		return c.uploadResourceChunked(assignmentID, resource, file, info)
+	}
+
+	// Create multipart form
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	// Add file field
+	part, err := writer.CreateFormFile("file", filepath.Base(resource.LocalPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to create form file: %v", err)
+	}
+
+	fileContent, err := ioutil.ReadAll(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %v", err)
+	}
+
+	part.Write(fileContent)
+
+	// Add metadata fields
+	writer.WriteField("title", resource.Title)
+	writer.WriteField("description", resource.Description)
+	writer.WriteField("type", resource.Type)
+	writer.WriteField("assignmentId", assignmentID)
+	writer.WriteField("isPublic", strconv.FormatBool(resource.IsPublic))
+	if resource.Checksum != "" {
+		writer.WriteField("checksum", resource.Checksum)
+	}
+	if resource.URL != "" {
+		writer.WriteField("url", resource.URL)
+	}
+	if resource.Order != 0 {
+		writer.WriteField("order", strconv.Itoa(resource.Order))
+	}
+	// Lists and maps don't fit form fields, so they are sent as JSON
+	if len(resource.Tags) > 0 {
+		tags, _ := json.Marshal(resource.Tags)
+		writer.WriteField("tags", string(tags))
+	}
+	if len(resource.Metadata) > 0 {
+		metadata, _ := json.Marshal(resource.Metadata)
+		writer.WriteField("metadata", string(metadata))
+	}
+
+	writer.Close()
+
+	// Create request
+	url := fmt.Sprintf("%s/api/resources", c.BaseURL)
+	req, err := http.NewRequest("POST", url, &buf)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+
+	// Send request
+	resp, err := c.do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("API error (%d): %s", resp.StatusCode, redactSecret(string(body), c.APIKey))
+	}
+
+	// Parse response
+	var response struct {
+		Resource struct {
+			ID string `json:"id"`
+		} `json:"resource"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	return response.Resource.ID, nil
+}
+
+// FileChecksum returns the hex SHA-256 of a file's contents, the form used
+// by Resource.Checksum
+func FileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// FindResourceByChecksum returns the ID of an LMS resource with identical
+// contents, or "" if there is none
+func (c *LMSClient) FindResourceByChecksum(checksum string) (string, error) {
+	url := fmt.Sprintf("%s/api/resources?checksum=%s", c.BaseURL, checksum)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil // No identical resource
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("API error (%d): %s", resp.StatusCode, redactSecret(string(body), c.APIKey))
+	}
+
+	var response struct {
+		Resource struct {
+			ID string `json:"id"`
+		} `json:"resource"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	return response.Resource.ID, nil
+}
+
+// linkResource attaches an existing LMS resource to an assignment
+func (c *LMSClient) linkResource(resourceID, assignmentID string) error {
+	jsonData, err := json.Marshal(map[string]string{"assignmentId": assignmentID})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	url := fmt.Sprintf("%s/api/resources/%s/link", c.BaseURL, resourceID)
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("API error (%d): %s", resp.StatusCode, redactSecret(string(body), c.APIKey))
+	}
+
+	return nil
+}
+
+// CheckLMSType returns an error when an assignment type has no LMS
+// mapping, suggesting close matches. ConvertToLMSFormat would send such a
+// type to the LMS unchanged.
+func CheckLMSType(assignmentType string) error {
+	typeManager := GetTypeManager()
+	if _, _, err := typeManager.ConvertToLMSFormat(assignmentType); err != nil {
+		if suggestions := typeManager.GetSuggestedTypes(assignmentType); len(suggestions) > 0 {
+			return fmt.Errorf("%v (did you mean %s?)", err, strings.Join(suggestions, ", "))
+		}
+		return err
+	}
+	return nil
+}
+
+// ConvertToLMSFormat converts our assignment format to LMS API format
+func ConvertToLMSFormat(pkg AssignmentPackage) map[string]interface{} {
+	assignment := pkg.Assignment
+
+	// Resolve portable type to LMS type
+	typeManager := GetTypeManager()
+	lmsType, lmsSubtype, err := typeManager.ConvertToLMSFormat(assignment.Type)
+	if err != nil {
+		// Fallback to original type if mapping fails; clients with
+		// StrictTypes refuse such packages first
+		lmsType = assignment.Type
+		lmsSubtype = assignment.Subtype
+	}
+
+	lmsAssignment := map[string]interface{}{
+		"title":                assignment.Title,
+		"description":          assignment.Description,
+		"type":                 lmsType,
+		"subtype":              lmsSubtype,
+		"category":             assignment.Category,
+		"difficulty":           NormalizeDifficulty(assignment.Difficulty),
+		"points":               assignment.Points,
+		"instructions":         lmsInstructions(pkg),
+		"criteria":             assignment.Criteria,
+		"autoGrade":            assignment.AutoGrade,
+		"showFeedback":         assignment.ShowFeedback,
+		"shuffleQuestions":     assignment.ShuffleQuestions,
+		"allowReview":          assignment.AllowReview,
+		"published":            assignment.Published,
+		"quarter":              NormalizeQuarter(assignment.Quarter),
+		"trackAttempts":        assignment.TrackAttempts,
+		"trackConfidence":      assignment.TrackConfidence,
+		"trackTimeSpent":       assignment.TrackTimeSpent,
+		"learningObjectives":   assignment.LearningObjectives,
+		"prerequisites":        assignment.Prerequisites,
+		"recommendedCourses":   assignment.RecommendedCourses,
+		"tags":                 assignment.Tags,
+		"questions":            convertMatchingPairs(convertQuestionMedia(pkg)),
+		"codeSubmissionConfig": assignment.CodeSubmissionConfig,
+
+		// Portable assignment metadata
+		"templateId":   pkg.Metadata.ID,
+		"version":      pkg.Metadata.Version,
+		"sourceHash":   pkg.Metadata.SourceHash,
+		"importedFrom": "assignment-toolkit",
+		"importedAt":   Now(),
+	}
+
+	if MarkdownInstructions(assignment) {
+		lmsAssignment["instructionsFormat"] = InstructionsMarkdown
+	}
+
+	// Handle time fields
+	if assignment.DueDate != nil {
+		lmsAssignment["dueDate"] = assignment.DueDate.Format(time.RFC3339)
+	}
+	if assignment.AvailableFrom != nil {
+		lmsAssignment["availableFrom"] = assignment.AvailableFrom.Format(time.RFC3339)
+	}
+	if assignment.AvailableTo != nil {
+		lmsAssignment["availableTo"] = assignment.AvailableTo.Format(time.RFC3339)
+	}
+
+	// Handle optional integer fields
+	if assignment.TimeLimit != nil {
+		lmsAssignment["timeLimit"] = *assignment.TimeLimit
+	}
+	if assignment.MaxAttempts != nil {
+		lmsAssignment["maxAttempts"] = *assignment.MaxAttempts
+	}
+	if assignment.MinWords != nil {
+		lmsAssignment["minWords"] = *assignment.MinWords
+	}
+	if assignment.MaxWords != nil {
+		lmsAssignment["maxWords"] = *assignment.MaxWords
+	}
+
+	// School-specific metadata travels in its own object so it can never
+	// overwrite a standard field
+	custom := make(map[string]interface{}, len(pkg.Metadata.Custom))
+	for key, value := range pkg.Metadata.Custom {
+		if key != LMSIDKey {
+			custom[key] = value
+		}
+	}
+	if len(custom) > 0 {
+		lmsAssignment["custom"] = custom
+	}
+
+	if assignment.ScoringMode != "" {
+		lmsAssignment["scoringMode"] = assignment.ScoringMode
+	}
+
+	return lmsAssignment
+}
+
+// Allows reports whether the filter lets key through
+func (f FieldFilter) Allows(key string) bool {
+	if len(f.Allow) > 0 && !containsKey(f.Allow, key) {
+		return false
+	}
+	return !containsKey(f.Deny, key)
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply removes the keys the filter does not accept from payload
+func (f FieldFilter) Apply(payload map[string]interface{}) {
+	if len(f.Allow) > 0 {
+		allowed := make(map[string]bool, len(f.Allow))
+		for _, key := range f.Allow {
+			allowed[key] = true
+		}
+		for key := range payload {
+			if !allowed[key] {
+				delete(payload, key)
+			}
+		}
+	}
+
+	for _, key := range f.Deny {
+		delete(payload, key)
+	}
+}
+
+// reservedPayloadKeys are the fields ConvertToLMSFormat may set, which
+// custom metadata must not replace
+var reservedPayloadKeys = []string{
+	"title", "description", "type", "subtype", "category", "difficulty", "points",
+	"instructions", "criteria", "autoGrade", "showFeedback", "shuffleQuestions",
+	"allowReview", "published", "quarter", "trackAttempts", "trackConfidence",
+	"trackTimeSpent", "learningObjectives", "prerequisites", "recommendedCourses",
+	"tags", "questions", "codeSubmissionConfig", "templateId", "version",
+	"sourceHash", "importedFrom", "importedAt", "dueDate", "availableFrom",
+	"availableTo", "timeLimit", "maxAttempts", "minWords", "maxWords", "scoringMode",
+	"instructionsFormat", "custom",
+}
+
+// IsReservedPayloadKey reports whether key is a standard payload field
+func IsReservedPayloadKey(key string) bool {
+	for _, reserved := range reservedPayloadKeys {
+		if key == reserved {
+			return true
+		}
+	}
+	return false
+}
+
+// TestConnection tests the connection to the LMS. Use DiagnoseConnection
+// to see which step failed.
+func (c *LMSClient) TestConnection() error {
+	return c.DiagnoseConnection().Err
+}
+
+// ExistingAssignment looks up an LMS assignment made from the same content
+// as pkg by its source hash, returning nil when there is none. Packages
+// whose source hash is missing or out of date aren't looked up, since the
+// hash no longer describes their content. A response that names no
+// assignment counts as none.
+func (c *LMSClient) ExistingAssignment(pkg AssignmentPackage) (*ImportResult, error) {
+	hash := pkg.Metadata.SourceHash
+	if hash == "" || hash != CalculateHash(pkg) {
+		return nil, nil
+	}
+	existing, err := c.GetAssignmentByHash(hash)
+	if err != nil || existing == nil || existing.Status != "exists" || existing.AssignmentID == "" {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// GetAssignmentByHash checks if an assignment with the given hash already exists
+func (c *LMSClient) GetAssignmentByHash(hash string) (*ImportResult, error) {
+	url := fmt.Sprintf("%s/api/assignments?sourceHash=%s", c.BaseURL, hash)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil // Assignment doesn't exist
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, redactSecret(string(body), c.APIKey))
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	var response struct {
+		Assignment struct {
+			ID string `json:"id"`
+		} `json:"assignment"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	return &ImportResult{
+		AssignmentID: response.Assignment.ID,
+		Status:       "exists",
+		Message:      "Assignment already exists",
+	}, nil
+}
+
+// AssignmentCounts is the number of assignments the LMS holds, in total and
+// per LMS type
+type AssignmentCounts struct {
+	Total  int            `json:"total"`
+	ByType map[string]int `json:"byType"`
+}
+
+// CountAssignments returns how many assignments exist in the LMS
+func (c *LMSClient) CountAssignments() (*AssignmentCounts, error) {
+	url := fmt.Sprintf("%s/api/assignments/count", c.BaseURL)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, redactSecret(string(body), c.APIKey))
+	}
+
+	var counts AssignmentCounts
+	if err := json.Unmarshal(body, &counts); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &counts, nil
+}
@@ -0,0 +1,423 @@
+package toolkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFieldFilterApply(t *testing.T) {
+	payload := map[string]interface{}{"title": "Quiz", "type": "matching", "custom": map[string]interface{}{}, "points": 5}
+
+	FieldFilter{Allow: []string{"title", "type", "points"}, Deny: []string{"points"}}.Apply(payload)
+
+	if len(payload) != 2 || payload["title"] != "Quiz" || payload["type"] != "matching" {
+		t.Errorf("unexpected payload after filtering: %v", payload)
+	}
+}
+
+func TestUploadResourcesLinksIdenticalFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "map.png")
+	if err := ioutil.WriteFile(path, []byte("image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	checksum, err := FileChecksum(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	linked := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/resources" && r.URL.Query().Get("checksum") == checksum:
+			fmt.Fprint(w, `{"resource":{"id":"res-9"}}`)
+		case r.Method == "POST" && r.URL.Path == "/api/resources/res-9/link":
+			linked = true
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := NewLMSClient(server.URL, "key")
+	client.OnlyChangedResources = true
+
+	ids, _, err := client.uploadResources("assignment-1", []Resource{{Title: "Map", LocalPath: path}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !linked || len(ids) != 1 || ids[0] != "res-9" {
+		t.Errorf("linked = %v, ids = %v; want existing resource res-9 linked", linked, ids)
+	}
+}
+
+func TestUploadResourceSendsFullDefinition(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "map.png")
+	if err := ioutil.WriteFile(path, []byte("image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var form map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("parse form: %v", err)
+			return
+		}
+		form = make(map[string]string)
+		for key, values := range r.MultipartForm.Value {
+			form[key] = values[0]
+		}
+		fmt.Fprint(w, `{"resource":{"id":"res-1"}}`)
+	}))
+	defer server.Close()
+
+	resource := Resource{
+		Title:     "Map",
+		Type:      "image",
+		LocalPath: path,
+		URL:       "https://example.com/map",
+		Order:     2,
+		IsPublic:  true,
+		Tags:      []string{"geography"},
+		Metadata:  map[string]string{"credit": "NASA"},
+	}
+	if _, err := NewLMSClient(server.URL, "key").uploadResource("assignment-1", resource); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"isPublic": "true",
+		"order":    "2",
+		"url":      "https://example.com/map",
+		"tags":     `["geography"]`,
+		"metadata": `{"credit":"NASA"}`,
+	}
+	for key, value := range want {
+		if form[key] != value {
+			t.Errorf("form field %s = %q, want %q", key, form[key], value)
+		}
+	}
+}
+
+func TestSyncAssignmentReportsConflicts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"message":"Duplicate assignment","conflicts":["slug taken",{"field":"title","value":"Quiz","existingId":"a-7"}]}`)
+	}))
+	defer server.Close()
+
+	result, err := NewLMSClient(server.URL, "key").SyncAssignment(AssignmentPackage{
+		Assignment: Assignment{Title: "Quiz", Type: "multiple-choice"},
+	})
+	if err == nil {
+		t.Fatal("expected an error for a conflicting sync")
+	}
+
+	want := []string{"slug taken", "title Quiz already used by assignment a-7"}
+	if result == nil || result.Status != "conflict" || !reflect.DeepEqual(result.Conflicts, want) {
+		t.Errorf("result = %+v, want conflict status with %q", result, want)
+	}
+}
+
+func TestCountAssignments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/assignments/count" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"total":3,"byType":{"quiz":2,"essay":1}}`)
+	}))
+	defer server.Close()
+
+	counts, err := NewLMSClient(server.URL, "key").CountAssignments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &AssignmentCounts{Total: 3, ByType: map[string]int{"quiz": 2, "essay": 1}}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("got %+v, want %+v", counts, want)
+	}
+}
+
+func TestSyncAssignmentUpdatesImportedAssignment(t *testing.T) {
+	var method, path string
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &payload)
+		fmt.Fprint(w, `{"message":"Updated"}`)
+	}))
+	defer server.Close()
+
+	result, err := NewLMSClient(server.URL, "key").SyncAssignment(AssignmentPackage{
+		Metadata:   PackageMetadata{Custom: map[string]string{LMSIDKey: "a-42"}},
+		Assignment: Assignment{Title: "Quiz", Type: "multiple-choice"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if method != "PUT" || path != "/api/assignments/a-42" {
+		t.Errorf("sent %s %s, want PUT /api/assignments/a-42", method, path)
+	}
+	if _, ok := payload["custom"]; ok {
+		t.Errorf("lms_id leaked into the payload: %v", payload["custom"])
+	}
+	if result.AssignmentID != "a-42" {
+		t.Errorf("AssignmentID = %q, want a-42", result.AssignmentID)
+	}
+}
+
+func TestPatchAssignmentSendsOnlyChangedFields(t *testing.T) {
+	var method, path string
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			fmt.Fprint(w, `{"assignment":{"title":"Quiz","points":5,"dueDate":"2024-05-01T00:00:00Z"}}`)
+			return
+		}
+		method, path = r.Method, r.URL.Path
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &sent)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewLMSClient(server.URL, "key")
+	local := map[string]interface{}{"title": "Quiz", "points": 5, "dueDate": "2024-05-08T00:00:00Z", "quarter": "Q2",
+		"criteria": "", "importedAt": Now()}
+	remote, err := client.GetAssignment("a-42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := ChangedFields(local, remote)
+	if !reflect.DeepEqual(changed, []string{"dueDate", "quarter"}) {
+		t.Fatalf("ChangedFields = %v, want [dueDate quarter]", changed)
+	}
+
+	if _, err := client.PatchAssignment("a-42", SelectFields(local, changed)); err != nil {
+		t.Fatal(err)
+	}
+	if method != "PATCH" || path != "/api/assignments/a-42" {
+		t.Errorf("sent %s %s, want PATCH /api/assignments/a-42", method, path)
+	}
+	if len(sent) != 2 || sent["dueDate"] != "2024-05-08T00:00:00Z" || sent["quarter"] != "Q2" {
+		t.Errorf("PATCH body = %v, want only dueDate and quarter", sent)
+	}
+}
+
+func TestPayloadKey(t *testing.T) {
+	for name, want := range map[string]string{"due_date": "dueDate", "dueDate": "dueDate", "POINTS": "points"} {
+		if got, ok := PayloadKey(name); !ok || got != want {
+			t.Errorf("PayloadKey(%q) = %q, %v; want %q", name, got, ok, want)
+		}
+	}
+	if _, ok := PayloadKey("colour"); ok {
+		t.Error("PayloadKey accepted an unknown field")
+	}
+}
+
+func TestStrictTypesRejectsUnmappedType(t *testing.T) {
+	client := NewLMSClient("http://lms.invalid", "key")
+	pkg := AssignmentPackage{Assignment: Assignment{Title: "Quiz", Type: "multiple-choise"}}
+
+	payload, err := client.LMSPayload(pkg)
+	if err != nil || payload["type"] != "multiple-choise" {
+		t.Fatalf("without StrictTypes got %v, %v; want the type sent unchanged", payload["type"], err)
+	}
+
+	client.StrictTypes = true
+	if _, err := client.LMSPayload(pkg); err == nil {
+		t.Error("StrictTypes accepted an unmapped type")
+	}
+	pkg.Assignment.Type = "mcq"
+	if _, err := client.LMSPayload(pkg); err != nil {
+		t.Errorf("StrictTypes rejected an alias: %v", err)
+	}
+}
+
+func TestSyncAssignmentRecordsFailedResourcesForRetry(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"map.png", "worksheet.pdf"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	failing := true
+	var assignmentPosts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/assignments" {
+			assignmentPosts++
+			fmt.Fprint(w, `{"assignment":{"id":"a-1"}}`)
+			return
+		}
+		_, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if failing && header.Filename == "worksheet.pdf" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprintf(w, `{"resource":{"id":"res-%s"}}`, header.Filename)
+	}))
+	defer server.Close()
+
+	pkg := AssignmentPackage{
+		Assignment: Assignment{Title: "Quiz", Type: "multiple-choice"},
+		Resources: []Resource{
+			{Title: "Map", LocalPath: filepath.Join(dir, "map.png")},
+			{Title: "Worksheet", LocalPath: filepath.Join(dir, "worksheet.pdf")},
+		},
+	}
+	client := NewLMSClient(server.URL, "key")
+
+	result, err := client.SyncAssignment(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != "partial" || !reflect.DeepEqual(result.FailedResources, []string{"worksheet.pdf"}) || !reflect.DeepEqual(result.ResourceIDs, []string{"res-map.png"}) {
+		t.Fatalf("result = %+v, want worksheet.pdf failed and map.png uploaded", result)
+	}
+
+	failing = false
+	retry, err := client.UploadResources(pkg, result.AssignmentID, result.FailedResources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retry.Status != "success" || !reflect.DeepEqual(retry.ResourceIDs, []string{"res-worksheet.pdf"}) {
+		t.Errorf("retry = %+v, want just worksheet.pdf uploaded", retry)
+	}
+	if assignmentPosts != 1 {
+		t.Errorf("assignment created %d times, want once", assignmentPosts)
+	}
+
+	if _, err := client.UploadResources(pkg, "a-1", []string{"gone.pdf"}); err == nil {
+		t.Error("expected an error when no named resource is left in the package")
+	}
+}
+
+func TestBatchSyncSkipsArchivedAssignments(t *testing.T) {
+	var posts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		fmt.Fprint(w, `{"assignment":{"id":"a-1"}}`)
+	}))
+	defer server.Close()
+
+	result, err := NewLMSClient(server.URL, "key").BatchSyncAssignments([]AssignmentPackage{
+		{Assignment: Assignment{Title: "Current", Type: "essay"}},
+		{Metadata: PackageMetadata{Archived: true}, Assignment: Assignment{Title: "Old", Type: "essay"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if posts != 1 || result.SuccessCount != 1 || result.SkippedCount != 1 || result.Results[1].Status != "skipped" {
+		t.Errorf("posts = %d, result = %+v; want only the current assignment synced", posts, result)
+	}
+}
+
+func TestExistingAssignmentLooksUpCurrentHash(t *testing.T) {
+	var lookups []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups = append(lookups, r.URL.Query().Get("sourceHash"))
+		fmt.Fprint(w, `{"assignment":{"id":"a-7"}}`)
+	}))
+	defer server.Close()
+	client := NewLMSClient(server.URL, "key")
+
+	pkg := AssignmentPackage{Assignment: Assignment{Title: "Quiz", Type: "essay"}}
+	pkg.Metadata.SourceHash = CalculateHash(pkg)
+	existing, err := client.ExistingAssignment(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if existing == nil || existing.AssignmentID != "a-7" || len(lookups) != 1 || lookups[0] != pkg.Metadata.SourceHash {
+		t.Errorf("existing = %+v after lookups %q; want a-7 found by the source hash", existing, lookups)
+	}
+
+	pkg.Assignment.Title = "Quiz, edited"
+	if existing, err := client.ExistingAssignment(pkg); err != nil || existing != nil || len(lookups) != 1 {
+		t.Errorf("a stale source hash was looked up: %+v, %v", existing, err)
+	}
+}
+
+func TestExistingAssignmentIgnoresResponseWithoutID(t *testing.T) {
+	for _, body := range []string{`{}`, `{"assignment":null}`} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, body)
+		}))
+
+		pkg := AssignmentPackage{Assignment: Assignment{Title: "Quiz", Type: "essay"}}
+		pkg.Metadata.SourceHash = CalculateHash(pkg)
+		existing, err := NewLMSClient(server.URL, "key").ExistingAssignment(pkg)
+		server.Close()
+		if err != nil || existing != nil {
+			t.Errorf("%s: existing = %+v, %v; want none", body, existing, err)
+		}
+	}
+}
+
+func TestConvertToLMSFormatForwardsScoringMode(t *testing.T) {
+	for _, autoGrade := range []bool{true, false} {
+		pkg := AssignmentPackage{Assignment: Assignment{
+			Title:       "Capitals",
+			Type:        "matching",
+			AutoGrade:   autoGrade,
+			ScoringMode: ScoringPartial,
+		}}
+		if mode := ConvertToLMSFormat(pkg)["scoringMode"]; mode != ScoringPartial {
+			t.Errorf("auto_grade %v: scoringMode = %v, want %s", autoGrade, mode, ScoringPartial)
+		}
+	}
+
+	pkg := AssignmentPackage{Assignment: Assignment{Title: "Capitals", Type: "matching"}}
+	if mode, ok := ConvertToLMSFormat(pkg)["scoringMode"]; ok {
+		t.Errorf("scoringMode = %v with no scoring mode set", mode)
+	}
+}
+
+func TestDescribeConflictsWithoutFieldOrMessage(t *testing.T) {
+	raw := []json.RawMessage{json.RawMessage(`{"existingId":"a-1"}`), json.RawMessage(`{"code":42}`)}
+	want := []string{"conflicts with assignment a-1", `{"code":42}`}
+	if got := describeConflicts(raw); !reflect.DeepEqual(got, want) {
+		t.Errorf("describeConflicts = %q, want %q", got, want)
+	}
+}
+
+func TestLMSIDIsEscapedInThePath(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.EscapedPath())
+		fmt.Fprint(w, `{"assignment":{"id":"unit/1?draft#2"}}`)
+	}))
+	defer server.Close()
+	client := NewLMSClient(server.URL, "key")
+
+	pkg := AssignmentPackage{Assignment: Assignment{Title: "Quiz", Type: "essay"}}
+	pkg.Metadata.Custom = map[string]string{LMSIDKey: "unit/1?draft#2"}
+	if _, err := client.SyncAssignment(pkg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetAssignment("unit/1?draft#2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.PatchAssignment("unit/1?draft#2", map[string]interface{}{"title": "Quiz"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "/api/assignments/unit%2F1%3Fdraft%232"
+	for i, method := range []string{"PUT", "GET", "PATCH"} {
+		if i >= len(paths) || paths[i] != method+" "+want {
+			t.Errorf("requests = %q, want %s %s", paths, method, want)
+		}
+	}
+}
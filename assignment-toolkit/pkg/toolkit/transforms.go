@@ -0,0 +1,95 @@
+package toolkit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PayloadTransform adjusts an LMS payload produced by ConvertToLMSFormat
+// before it is uploaded
+type PayloadTransform func(payload map[string]interface{}) error
+
+// payloadTransforms holds the transforms that can be enabled by name
+var payloadTransforms = map[string]PayloadTransform{}
+
+// defaultPayloadTransforms run when the config does not list any
+var defaultPayloadTransforms = []string{"config-fields"}
+
+// RegisterPayloadTransform makes a transform available under name
+func RegisterPayloadTransform(name string, transform PayloadTransform) {
+	payloadTransforms[name] = transform
+}
+
+func init() {
+	RegisterPayloadTransform("config-fields", configFieldsTransform)
+	RegisterPayloadTransform("flatten-custom", flattenCustomTransform)
+}
+
+// ResolvePayloadTransforms looks up transforms by name, preserving order
+func ResolvePayloadTransforms(names []string) ([]PayloadTransform, error) {
+	if len(names) == 0 {
+		names = defaultPayloadTransforms
+	}
+
+	var transforms []PayloadTransform
+	for _, name := range names {
+		transform, exists := payloadTransforms[name]
+		if !exists {
+			return nil, fmt.Errorf("unknown payload transform %q (available: %s)", name, strings.Join(payloadTransformNames(), ", "))
+		}
+		transforms = append(transforms, transform)
+	}
+	return transforms, nil
+}
+
+func payloadTransformNames() []string {
+	var names []string
+	for name := range payloadTransforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyPayloadTransforms runs transforms in order, stopping at the first error
+func ApplyPayloadTransforms(payload map[string]interface{}, transforms []PayloadTransform) error {
+	for _, transform := range transforms {
+		if err := transform(payload); err != nil {
+			return fmt.Errorf("payload transform failed: %v", err)
+		}
+	}
+	return nil
+}
+
+// configFieldsTransform merges the payload_fields from config into the
+// payload, e.g. to inject a department code on every assignment
+func configFieldsTransform(payload map[string]interface{}) error {
+	for key, value := range activeConfig.PayloadFields {
+		payload[key] = value
+	}
+	return nil
+}
+
+// flattenCustomTransform moves custom metadata to top-level payload fields
+// for LMS deployments that expect them there. Keys that would replace a
+// standard field stay under "custom".
+func flattenCustomTransform(payload map[string]interface{}) error {
+	custom, ok := payload["custom"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	for key, value := range custom {
+		if IsReservedPayloadKey(key) {
+			continue
+		}
+		payload[key] = value
+		delete(custom, key)
+	}
+
+	if len(custom) == 0 {
+		delete(payload, "custom")
+	}
+	return nil
+}
@@ -0,0 +1,416 @@
+package toolkit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// TypeMappingsFile holds a workspace's own type mappings and aliases. It is
+// loaded from the workspace root by GetTypeManager when present.
+const TypeMappingsFile = ".assignment-types.yaml"
+
+// typeMappingsFile is the layout of TypeMappingsFile
+type typeMappingsFile struct {
+	Mappings []TypeMapping     `yaml:"mappings"`
+	Aliases  map[string]string `yaml:"aliases"`
+}
+
+// TypeMapping handles assignment type conflicts and transformations
+type TypeMapping struct {
+	PortableType string `json:"portable_type" yaml:"portable_type"`
+	LMSType      string `json:"lms_type" yaml:"lms_type"`
+	LMSSubtype   string `json:"lms_subtype,omitempty" yaml:"lms_subtype,omitempty"`
+	Description  string `json:"description" yaml:"description"`
+	Deprecated   bool   `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+}
+
+// AssignmentTypeManager manages type mappings and conflicts
+type AssignmentTypeManager struct {
+	mappings map[string]TypeMapping
+	aliases  map[string]string
+	custom   map[string]bool
+	fromFile map[string]bool
+	fileErr  error
+}
+
+// NewAssignmentTypeManager creates a new type manager with default mappings
+func NewAssignmentTypeManager() *AssignmentTypeManager {
+	manager := &AssignmentTypeManager{
+		mappings: make(map[string]TypeMapping),
+		aliases:  make(map[string]string),
+		custom:   make(map[string]bool),
+		fromFile: make(map[string]bool),
+	}
+
+	// Initialize default mappings
+	manager.initializeDefaultMappings()
+	return manager
+}
+
+// initializeDefaultMappings sets up the default type mappings
+func (atm *AssignmentTypeManager) initializeDefaultMappings() {
+	// Direct mappings (no conflicts)
+	directMappings := []TypeMapping{
+		{"multiple-choice", "multiple-choice", "", "Multiple choice questions", false},
+		{"true-false", "true-false", "", "True/false questions", false},
+		{"matching", "matching", "", "Match items from two lists", false},
+		{"writing-short", "writing", "", "Short writing assignments", false},
+		{"writing-long", "writing-long", "", "Extended writing assignments", false},
+		{"speaking", "speaking", "", "Oral presentation assignments", false},
+		{"listening", "listening", "", "Audio comprehension exercises", false},
+		{"code-submission", "code-submission", "", "Programming assignments", false},
+		{"image-upload", "image-upload", "", "Image upload assignments", false},
+
+		// Specialized LMS types
+		{"line-match", "line-match", "", "Line matching exercises (LMS specific)", false},
+		{"phoneme-build", "phoneme-build", "", "Phoneme building exercises (LMS specific)", false},
+
+		// Drag-and-drop with subtypes
+		{"drag-drop-ordering", "drag-and-drop", "ordering", "Drag and drop ordering", false},
+		{"drag-drop-categorization", "drag-and-drop", "categorization", "Drag and drop categorization", false},
+		{"drag-drop-fill-blank", "drag-and-drop", "fill-blank", "Drag and drop fill in blanks", false},
+		{"drag-drop-labeling", "drag-and-drop", "labeling", "Drag and drop labeling", false},
+		{"drag-drop-image-caption", "drag-and-drop", "image-caption", "Drag and drop image captions", false},
+
+		// Generic assignment (conflict resolution)
+		{"generic-assignment", "assignment", "", "Generic assignment type", false},
+
+		// Portable-specific types (not in LMS)
+		{"essay", "writing-long", "", "Essay assignment (mapped to writing-long)", false},
+		{"quiz", "multiple-choice", "", "Quiz assignment (mapped to multiple-choice)", false},
+		{"presentation", "speaking", "", "Presentation (mapped to speaking)", false},
+		{"comprehension", "listening", "", "Comprehension exercise (mapped to listening)", false},
+	}
+
+	for _, mapping := range directMappings {
+		atm.mappings[mapping.PortableType] = mapping
+	}
+
+	// Set up aliases for common terms
+	atm.aliases = map[string]string{
+		"mcq":         "multiple-choice",
+		"mc":          "multiple-choice",
+		"tf":          "true-false",
+		"t/f":         "true-false",
+		"match":       "matching",
+		"essay":       "writing-long",
+		"short-essay": "writing-short",
+		"code":        "code-submission",
+		"programming": "code-submission",
+		"drag-drop":   "drag-drop-ordering",
+		"dnd":         "drag-drop-ordering",
+		"oral":        "speaking",
+		"audio":       "listening",
+		"image":       "image-upload",
+		"upload":      "image-upload",
+
+		// Legacy names kept so older files still resolve
+		"writing": "writing-short",
+	}
+}
+
+// SetCustomMappings replaces the workspace's custom types (config
+// custom_types). Custom types can't reuse a built-in type or alias name;
+// those are skipped and reported in the returned error, and the rest are
+// still added.
+func (atm *AssignmentTypeManager) SetCustomMappings(mappings []TypeMapping) error {
+	for portableType := range atm.custom {
+		delete(atm.mappings, portableType)
+	}
+	atm.custom = make(map[string]bool)
+
+	var problems []string
+	for _, mapping := range mappings {
+		mapping.PortableType = strings.ToLower(strings.TrimSpace(mapping.PortableType))
+		if err := atm.checkCustomMapping(mapping); err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		atm.mappings[mapping.PortableType] = mapping
+		atm.custom[mapping.PortableType] = true
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func (atm *AssignmentTypeManager) checkCustomMapping(mapping TypeMapping) error {
+	switch {
+	case mapping.PortableType == "":
+		return fmt.Errorf("custom type needs a name")
+	case mapping.LMSType == "":
+		return fmt.Errorf("custom type %s needs an LMS type", mapping.PortableType)
+	case atm.custom[mapping.PortableType]:
+		return fmt.Errorf("custom type %s is listed twice", mapping.PortableType)
+	}
+	if atm.fromFile[mapping.PortableType] {
+		return fmt.Errorf("%s is defined in %s", mapping.PortableType, TypeMappingsFile)
+	}
+	if _, exists := atm.mappings[mapping.PortableType]; exists {
+		return fmt.Errorf("%s is a built-in type", mapping.PortableType)
+	}
+	if _, exists := atm.aliases[mapping.PortableType]; exists {
+		return fmt.Errorf("%s is a built-in alias", mapping.PortableType)
+	}
+	return nil
+}
+
+// LoadMappingsFromFile merges the mappings and aliases in a YAML file
+// (mappings: a list of type mappings, aliases: alias to portable type) over
+// the current ones. A mapping for an existing portable type replaces it.
+// Entries that are incomplete or name an unknown type are skipped and
+// reported in the returned error; the rest are still merged.
+func (atm *AssignmentTypeManager) LoadMappingsFromFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var file typeMappingsFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	var problems []string
+	for _, mapping := range file.Mappings {
+		mapping.PortableType = strings.ToLower(strings.TrimSpace(mapping.PortableType))
+		switch {
+		case mapping.PortableType == "":
+			problems = append(problems, "a mapping needs a portable_type")
+			continue
+		case mapping.LMSType == "":
+			problems = append(problems, fmt.Sprintf("%s needs an lms_type", mapping.PortableType))
+			continue
+		}
+		atm.mappings[mapping.PortableType] = mapping
+		atm.fromFile[mapping.PortableType] = true
+		delete(atm.aliases, mapping.PortableType)
+	}
+
+	for alias, target := range file.Aliases {
+		alias = strings.ToLower(strings.TrimSpace(alias))
+		target = strings.ToLower(strings.TrimSpace(target))
+		if _, exists := atm.mappings[alias]; exists {
+			problems = append(problems, fmt.Sprintf("alias %s is already a type", alias))
+			continue
+		}
+		if _, exists := atm.mappings[target]; !exists {
+			problems = append(problems, fmt.Sprintf("alias %s names unknown type %s", alias, target))
+			continue
+		}
+		atm.aliases[alias] = target
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s: %s", path, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// IsFileType reports whether a portable type's mapping comes from
+// TypeMappingsFile, whether it adds a type or replaces a built-in one
+func (atm *AssignmentTypeManager) IsFileType(portableType string) bool {
+	return atm.fromFile[strings.ToLower(strings.TrimSpace(portableType))]
+}
+
+// FileError returns the problem GetTypeManager found loading
+// TypeMappingsFile, if any
+func (atm *AssignmentTypeManager) FileError() error {
+	return atm.fileErr
+}
+
+// IsCustomType reports whether a portable type comes from the workspace
+// configuration rather than the built-in mappings
+func (atm *AssignmentTypeManager) IsCustomType(portableType string) bool {
+	return atm.custom[strings.ToLower(strings.TrimSpace(portableType))]
+}
+
+// ResolveType resolves a portable type to LMS format
+func (atm *AssignmentTypeManager) ResolveType(portableType string) (TypeMapping, error) {
+	// Normalize input
+	normalizedType := strings.ToLower(strings.TrimSpace(portableType))
+
+	// Check direct mapping first
+	if mapping, exists := atm.mappings[normalizedType]; exists {
+		return mapping, nil
+	}
+
+	// Check aliases
+	if aliasTarget, exists := atm.aliases[normalizedType]; exists {
+		if mapping, exists := atm.mappings[aliasTarget]; exists {
+			return mapping, nil
+		}
+	}
+
+	// Return error for unknown types
+	return TypeMapping{}, fmt.Errorf("unknown assignment type: %s", portableType)
+}
+
+// CanonicalType returns the portable type name for a type string,
+// following aliases and legacy names. ok is false for unknown types.
+func (atm *AssignmentTypeManager) CanonicalType(assignmentType string) (string, bool) {
+	normalizedType := strings.ToLower(strings.TrimSpace(assignmentType))
+
+	if _, exists := atm.mappings[normalizedType]; exists {
+		return normalizedType, true
+	}
+	if aliasTarget, exists := atm.aliases[normalizedType]; exists {
+		if _, exists := atm.mappings[aliasTarget]; exists {
+			return aliasTarget, true
+		}
+	}
+	return "", false
+}
+
+// GetPortableTypes returns all available portable types
+func (atm *AssignmentTypeManager) GetPortableTypes() []string {
+	var types []string
+	for portableType := range atm.mappings {
+		types = append(types, portableType)
+	}
+	return types
+}
+
+// GetAliases returns the built-in aliases and legacy names, mapped to the
+// portable type each resolves to
+func (atm *AssignmentTypeManager) GetAliases() map[string]string {
+	result := make(map[string]string, len(atm.aliases))
+	for alias, target := range atm.aliases {
+		result[alias] = target
+	}
+	return result
+}
+
+// GetLMSTypes returns all LMS types
+func (atm *AssignmentTypeManager) GetLMSTypes() []string {
+	lmsTypes := make(map[string]bool)
+	for _, mapping := range atm.mappings {
+		lmsTypes[mapping.LMSType] = true
+	}
+
+	var types []string
+	for lmsType := range lmsTypes {
+		types = append(types, lmsType)
+	}
+	return types
+}
+
+// ValidatePortableType checks if a portable type is valid
+func (atm *AssignmentTypeManager) ValidatePortableType(portableType string) bool {
+	_, err := atm.ResolveType(portableType)
+	return err == nil
+}
+
+// GetTypeDescription returns a description for a type
+func (atm *AssignmentTypeManager) GetTypeDescription(portableType string) string {
+	if mapping, err := atm.ResolveType(portableType); err == nil {
+		return mapping.Description
+	}
+	return "Unknown assignment type"
+}
+
+// ConvertToLMSFormat converts a portable assignment type to LMS format
+func (atm *AssignmentTypeManager) ConvertToLMSFormat(portableType string) (string, string, error) {
+	mapping, err := atm.ResolveType(portableType)
+	if err != nil {
+		return "", "", err
+	}
+
+	return mapping.LMSType, mapping.LMSSubtype, nil
+}
+
+// ResolvedType is how a portable type is sent to the LMS
+type ResolvedType struct {
+	Portable   string `json:"portable" yaml:"portable"`
+	LMSType    string `json:"lms_type" yaml:"lms_type"`
+	LMSSubtype string `json:"lms_subtype,omitempty" yaml:"lms_subtype,omitempty"`
+}
+
+// ResolveTypeMapping returns the canonical portable type, LMS type, and
+// subtype an assignment type is synced as
+func ResolveTypeMapping(assignmentType string) (*ResolvedType, error) {
+	mapping, err := GetTypeManager().ResolveType(assignmentType)
+	if err != nil {
+		return nil, err
+	}
+	return &ResolvedType{Portable: mapping.PortableType, LMSType: mapping.LMSType, LMSSubtype: mapping.LMSSubtype}, nil
+}
+
+// GetSuggestedTypes returns type suggestions for invalid input
+func (atm *AssignmentTypeManager) GetSuggestedTypes(input string) []string {
+	input = strings.ToLower(input)
+	var suggestions []string
+
+	// Check for partial matches
+	for portableType := range atm.mappings {
+		if strings.Contains(portableType, input) || strings.Contains(input, portableType) {
+			suggestions = append(suggestions, portableType)
+		}
+	}
+
+	// Check aliases
+	for alias, target := range atm.aliases {
+		if strings.Contains(alias, input) || strings.Contains(input, alias) {
+			suggestions = append(suggestions, target)
+		}
+	}
+
+	// Remove duplicates
+	seen := make(map[string]bool)
+	var unique []string
+	for _, suggestion := range suggestions {
+		if !seen[suggestion] {
+			seen[suggestion] = true
+			unique = append(unique, suggestion)
+		}
+	}
+
+	return unique
+}
+
+// ListTypesWithDescriptions returns a formatted list of all types
+func (atm *AssignmentTypeManager) ListTypesWithDescriptions() map[string]string {
+	result := make(map[string]string)
+	for portableType, mapping := range atm.mappings {
+		lmsInfo := mapping.LMSType
+		if mapping.LMSSubtype != "" {
+			lmsInfo += " (" + mapping.LMSSubtype + ")"
+		}
+		result[portableType] = fmt.Sprintf("%s → %s", mapping.Description, lmsInfo)
+	}
+	return result
+}
+
+// ListMappings returns all type mappings keyed by portable type
+func (atm *AssignmentTypeManager) ListMappings() map[string]TypeMapping {
+	result := make(map[string]TypeMapping, len(atm.mappings))
+	for portableType, mapping := range atm.mappings {
+		result[portableType] = mapping
+	}
+	return result
+}
+
+// Global type manager instance
+var (
+	globalTypeManager     *AssignmentTypeManager
+	globalTypeManagerOnce sync.Once
+)
+
+// GetTypeManager returns the global type manager instance, with the
+// workspace's TypeMappingsFile merged in when there is one (see FileError).
+// It is safe to call from multiple goroutines.
+func GetTypeManager() *AssignmentTypeManager {
+	globalTypeManagerOnce.Do(func() {
+		globalTypeManager = NewAssignmentTypeManager()
+		if _, err := os.Stat(TypeMappingsFile); err == nil {
+			globalTypeManager.fileErr = globalTypeManager.LoadMappingsFromFile(TypeMappingsFile)
+		}
+	})
+	return globalTypeManager
+}
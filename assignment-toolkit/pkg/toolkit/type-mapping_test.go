@@ -0,0 +1,115 @@
+package toolkit
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGetTypeManagerConcurrent(t *testing.T) {
+	const workers = 16
+	managers := make([]*AssignmentTypeManager, workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			managers[i] = GetTypeManager()
+		}(i)
+	}
+	wg.Wait()
+
+	for i, manager := range managers {
+		if manager == nil || manager != managers[0] {
+			t.Fatalf("worker %d got a different type manager instance", i)
+		}
+	}
+}
+
+func TestCanonicalType(t *testing.T) {
+	cases := map[string]string{
+		"MCQ":             "multiple-choice",
+		"writing":         "writing-short",
+		" essay ":         "essay",
+		"code-submission": "code-submission",
+	}
+	for input, want := range cases {
+		if got, ok := GetTypeManager().CanonicalType(input); !ok || got != want {
+			t.Errorf("CanonicalType(%q) = %q, %v; want %q", input, got, ok, want)
+		}
+	}
+	if _, ok := GetTypeManager().CanonicalType("interpretive-dance"); ok {
+		t.Error("unknown type should not resolve")
+	}
+}
+
+func TestSetCustomMappings(t *testing.T) {
+	manager := NewAssignmentTypeManager()
+	err := manager.SetCustomMappings([]TypeMapping{
+		{PortableType: "Phonics-Drill", LMSType: "phoneme-build"},
+		{PortableType: "essay", LMSType: "writing"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "essay is a built-in type") {
+		t.Errorf("err = %v, want the built-in essay reported", err)
+	}
+	if lmsType, _, err := manager.ConvertToLMSFormat("phonics-drill"); err != nil || lmsType != "phoneme-build" {
+		t.Errorf("phonics-drill resolved to %q, %v", lmsType, err)
+	}
+	if !manager.IsCustomType("phonics-drill") || manager.IsCustomType("essay") {
+		t.Error("IsCustomType should only report the added type")
+	}
+
+	// Replacing the custom types drops the earlier ones
+	if err := manager.SetCustomMappings(nil); err != nil {
+		t.Fatal(err)
+	}
+	if manager.ValidatePortableType("phonics-drill") {
+		t.Error("removed custom type still resolves")
+	}
+}
+
+func TestLoadMappingsFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), TypeMappingsFile)
+	data := `mappings:
+  - portable_type: lab-report
+    lms_type: assignment
+    lms_subtype: lab
+    description: Science lab report
+  - portable_type: essay
+    lms_type: long-form
+    description: Essay graded with the district rubric
+  - portable_type: no-lms-type
+aliases:
+  lab: lab-report
+  mcq: essay
+  ghost: nowhere
+`
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewAssignmentTypeManager()
+	err := manager.LoadMappingsFromFile(path)
+	if err == nil || !strings.Contains(err.Error(), "no-lms-type needs an lms_type") || !strings.Contains(err.Error(), "ghost names unknown type nowhere") {
+		t.Errorf("err = %v, want the incomplete mapping and unknown alias target reported", err)
+	}
+
+	if mapping, err := manager.ResolveType("lab"); err != nil || mapping.LMSSubtype != "lab" {
+		t.Errorf("lab resolved to %+v, %v; want the lab-report mapping", mapping, err)
+	}
+	if mapping, _ := manager.ResolveType("essay"); mapping.LMSType != "long-form" {
+		t.Errorf("essay resolved to %s, want the file's override long-form", mapping.LMSType)
+	}
+	if canonical, _ := manager.CanonicalType("mcq"); canonical != "essay" {
+		t.Errorf("mcq now resolves to %s, want essay", canonical)
+	}
+	if !manager.IsFileType("lab-report") || manager.IsFileType("matching") {
+		t.Error("IsFileType should only report the file's types")
+	}
+	if err := manager.SetCustomMappings([]TypeMapping{{PortableType: "lab-report", LMSType: "assignment"}}); err == nil {
+		t.Error("a custom type reused a type from the mappings file")
+	}
+}
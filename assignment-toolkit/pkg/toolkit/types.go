@@ -1,4 +1,4 @@
-package main
+package toolkit
 
 import (
 	"time"
@@ -27,6 +27,14 @@ type PackageMetadata struct {
 	Language    string            `json:"language,omitempty" yaml:"language,omitempty"`
 	SourceHash  string            `json:"source_hash" yaml:"source_hash"`
 	Custom      map[string]string `json:"custom,omitempty" yaml:"custom,omitempty"`
+
+	// TypeMapping records the LMS type the assignment type resolved to
+	// when the package was created
+	TypeMapping *ResolvedType `json:"type_mapping,omitempty" yaml:"type_mapping,omitempty"`
+
+	// Archived assignments are retired: list hides them and batch syncs
+	// skip them, but the file is kept
+	Archived bool `json:"archived,omitempty" yaml:"archived,omitempty"`
 }
 
 // Assignment represents the core assignment data
@@ -42,8 +50,11 @@ type Assignment struct {
 	// Content
 	Questions           interface{} `json:"questions,omitempty" yaml:"questions,omitempty"`
 	Instructions        string      `json:"instructions,omitempty" yaml:"instructions,omitempty"`
+	InstructionsFormat  string      `json:"instructions_format,omitempty" yaml:"instructions_format,omitempty"` // "markdown" or plain text when empty
 	Criteria            string      `json:"criteria,omitempty" yaml:"criteria,omitempty"`
 	CodeSubmissionConfig interface{} `json:"code_submission_config,omitempty" yaml:"code_submission_config,omitempty"`
+	MinWords            *int        `json:"min_words,omitempty" yaml:"min_words,omitempty"` // writing word limits enforced by the LMS
+	MaxWords            *int        `json:"max_words,omitempty" yaml:"max_words,omitempty"`
 
 	// Scoring & Behavior
 	Points           int  `json:"points" yaml:"points"`
@@ -53,6 +64,7 @@ type Assignment struct {
 	ShowFeedback     bool `json:"show_feedback" yaml:"show_feedback"`
 	ShuffleQuestions bool `json:"shuffle_questions" yaml:"shuffle_questions"`
 	AllowReview      bool `json:"allow_review" yaml:"allow_review"`
+	ScoringMode      string `json:"scoring_mode,omitempty" yaml:"scoring_mode,omitempty"` // all-or-nothing, partial
 
 	// Scheduling
 	DueDate       *time.Time `json:"due_date,omitempty" yaml:"due_date,omitempty"`
@@ -75,6 +87,12 @@ type Assignment struct {
 	Published bool `json:"published" yaml:"published"`
 }
 
+// Scoring modes for auto-graded question types
+const (
+	ScoringAllOrNothing = "all-or-nothing"
+	ScoringPartial      = "partial"
+)
+
 // Resource represents a learning resource attached to an assignment
 type Resource struct {
 	ID          string            `json:"id" yaml:"id"`
@@ -124,6 +142,7 @@ type ImportResult struct {
 	AssignmentID string            `json:"assignment_id,omitempty"`
 	ResourceIDs  []string          `json:"resource_ids,omitempty"`
 	Conflicts    []string          `json:"conflicts,omitempty"`
+	FailedResources []string       `json:"failed_resources,omitempty"`
 	Status       string            `json:"status"`
 	Message      string            `json:"message,omitempty"`
 	Metadata     map[string]string `json:"metadata,omitempty"`
@@ -135,6 +154,7 @@ type BatchImportResult struct {
 	TotalCount   int            `json:"total_count"`
 	SuccessCount int            `json:"success_count"`
 	FailureCount int            `json:"failure_count"`
+	SkippedCount int            `json:"skipped_count,omitempty"`
 	Results      []ImportResult `json:"results"`
 	StartedAt    time.Time      `json:"started_at"`
 	CompletedAt  time.Time      `json:"completed_at"`
@@ -150,6 +170,110 @@ type Config struct {
 	APIKey      string            `json:"api_key,omitempty" yaml:"api_key,omitempty"`
 	Templates   map[string]string `json:"templates" yaml:"templates"`
 	Defaults    map[string]string `json:"defaults" yaml:"defaults"`
+
+	// DefaultPoints proposes points per assignment type, e.g. essay: 20;
+	// other types fall back to defaults.points
+	DefaultPoints map[string]int `json:"default_points,omitempty" yaml:"default_points,omitempty"`
+
+	// CustomTypes adds workspace-specific assignment types to the built-in
+	// mappings; manage them with 'types add' and 'types remove'
+	CustomTypes []TypeMapping `json:"custom_types,omitempty" yaml:"custom_types,omitempty"`
+
+	// AllowedQuarters extends the built-in Q1-Q4 set, e.g. for semesters or terms
+	AllowedQuarters []string `json:"allowed_quarters,omitempty" yaml:"allowed_quarters,omitempty"`
+
+	// AllowedDifficulties replaces the built-in beginner/intermediate/advanced levels
+	AllowedDifficulties []string `json:"allowed_difficulties,omitempty" yaml:"allowed_difficulties,omitempty"`
+
+	// DisabledRules turns off validation rules by ID
+	DisabledRules []string `json:"disabled_rules,omitempty" yaml:"disabled_rules,omitempty"`
+
+	// PayloadTransforms lists the transforms applied before sync, in order
+	PayloadTransforms []string          `json:"payload_transforms,omitempty" yaml:"payload_transforms,omitempty"`
+	PayloadFields     map[string]string `json:"payload_fields,omitempty" yaml:"payload_fields,omitempty"`
+
+	// SyncFields restricts which top-level payload keys are sent to the LMS
+	SyncFields FieldFilter `json:"sync_fields,omitempty" yaml:"sync_fields,omitempty"`
+
+	// FieldMappingFile names a file renaming payload keys for the LMS, e.g. autoGrade: auto_grade
+	FieldMappingFile string `json:"field_mapping_file,omitempty" yaml:"field_mapping_file,omitempty"`
+
+	// GzipUploads compresses assignment uploads (the LMS must support it)
+	GzipUploads bool `json:"gzip_uploads,omitempty" yaml:"gzip_uploads,omitempty"`
+
+	// UploadChunkSizeMB splits larger resource files into chunks of this size
+	UploadChunkSizeMB int `json:"upload_chunk_size_mb,omitempty" yaml:"upload_chunk_size_mb,omitempty"`
+
+	// MediaMaxSizeMB is the audio/video file size above which sync warns
+	// (DefaultMediaMaxSizeMB when zero)
+	MediaMaxSizeMB int `json:"media_max_size_mb,omitempty" yaml:"media_max_size_mb,omitempty"`
+
+	// RejectedMediaFormats lists audio/video formats the LMS does not accept
+	// (DefaultRejectedMediaFormats when unset)
+	RejectedMediaFormats []string `json:"rejected_media_formats,omitempty" yaml:"rejected_media_formats,omitempty"`
+
+	// StrictTypes makes sync fail on assignment types with no LMS mapping
+	// instead of sending them unchanged
+	StrictTypes bool `json:"strict_types,omitempty" yaml:"strict_types,omitempty"`
+
+	// Production marks lms_endpoint as the live LMS; sync then asks for
+	// the endpoint host to be typed before uploading
+	Production bool `json:"production,omitempty" yaml:"production,omitempty"`
+
+	// ConfirmBeforeSync asks for that confirmation on every endpoint
+	ConfirmBeforeSync bool `json:"confirm_before_sync,omitempty" yaml:"confirm_before_sync,omitempty"`
+
+	// NormalizeLineEndings says when CRLF line breaks in assignment text
+	// become LF: "save" (the default), "always" (on load too), or "off"
+	NormalizeLineEndings string `json:"normalize_line_endings,omitempty" yaml:"normalize_line_endings,omitempty"`
+
+	// RequestSigning signs every LMS request with HMAC-SHA256 for
+	// deployments that require it; bearer auth alone is the default
+	RequestSigning RequestSigning `json:"request_signing,omitempty" yaml:"request_signing,omitempty"`
+
+	// RateLimitMaxWaitSeconds caps how long one request waits out 429 responses
+	RateLimitMaxWaitSeconds int `json:"rate_limit_max_wait_seconds,omitempty" yaml:"rate_limit_max_wait_seconds,omitempty"`
+
+	// SyncRetries is how often an LMS request that fails with a network
+	// error or a 5xx response is retried (default 3; 0 turns retrying off)
+	SyncRetries *int `json:"sync_retries,omitempty" yaml:"sync_retries,omitempty"`
+
+	// SyncRetryMaxSeconds bounds the time one request may spend retrying
+	SyncRetryMaxSeconds int `json:"sync_retry_max_seconds,omitempty" yaml:"sync_retry_max_seconds,omitempty"`
+
+	// URLRewrites rewrite resource URLs as they are uploaded, e.g. to an
+	// internal media proxy
+	URLRewrites []URLRewrite `json:"url_rewrites,omitempty" yaml:"url_rewrites,omitempty"`
+
+	// ResourcePublic is the default visibility for resources attached in
+	// the create wizard
+	ResourcePublic bool `json:"resource_public,omitempty" yaml:"resource_public,omitempty"`
+
+	// ReadmeTemplate is a text/template file 'package' renders README.md
+	// from; it receives the AssignmentPackage
+	ReadmeTemplate string `json:"readme_template,omitempty" yaml:"readme_template,omitempty"`
+
+	// OutputDir is where create and batch-create save new assignments
+	// (the current directory when empty)
+	OutputDir string `json:"output_dir,omitempty" yaml:"output_dir,omitempty"`
+
+	// OutputLayout organizes OutputDir into subdirectories named after
+	// assignment fields, e.g. "{quarter}/{type}"
+	OutputLayout string `json:"output_layout,omitempty" yaml:"output_layout,omitempty"`
+
+	// BaseTemplate seeds every 'create' that doesn't pass --template
+	BaseTemplate string `json:"base_template,omitempty" yaml:"base_template,omitempty"`
+
+	// UpdateURL is no longer read: self-update only takes its release feed
+	// from the environment. Kept so existing config files still load.
+	UpdateURL string `json:"update_url,omitempty" yaml:"update_url,omitempty"`
+}
+
+// FieldFilter selects payload keys. When Allow is set only those keys are
+// kept; keys in Deny are always removed.
+type FieldFilter struct {
+	Allow []string `json:"allow,omitempty" yaml:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty" yaml:"deny,omitempty"`
 }
 
 // Template represents an assignment template
@@ -0,0 +1,211 @@
+package toolkit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// DefaultChunkSize is the resource upload part size used when
+// LMSClient.ChunkSize is not set
+const DefaultChunkSize int64 = 5 << 20
+
+func (c *LMSClient) chunkSize() int64 {
+	if c.ChunkSize > 0 {
+		return c.ChunkSize
+	}
+	return DefaultChunkSize
+}
+
+// chunkUploadID identifies an upload so an interrupted transfer of the same
+// file can resume on the next sync
+func chunkUploadID(assignmentID string, path string, info os.FileInfo) string {
+	key := fmt.Sprintf("%s\x00%s\x00%d\x00%d", assignmentID, filepath.Base(path), info.Size(), info.ModTime().UnixNano())
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(key)))[:32]
+}
+
+// uploadResourceChunked sends a large resource in parts to
+// /api/resources/chunk, skipping parts the server already has, and then
+// asks the server to reassemble them
+func (c *LMSClient) uploadResourceChunked(assignmentID string, resource Resource, file *os.File, info os.FileInfo) (string, error) {
+	chunkSize := c.chunkSize()
+	totalChunks := int((info.Size() + chunkSize - 1) / chunkSize)
+	uploadID := chunkUploadID(assignmentID, resource.LocalPath, info)
+
+	received, err := c.receivedChunks(uploadID)
+	if err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, chunkSize)
+	for index := 0; index < totalChunks; index++ {
+		if received[index] {
+			continue
+		}
+
+		n, err := file.ReadAt(buf, int64(index)*chunkSize)
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("failed to read file: %v", err)
+		}
+
+		if err := c.uploadChunk(uploadID, index, totalChunks, filepath.Base(resource.LocalPath), buf[:n]); err != nil {
+			return "", fmt.Errorf("chunk %d of %d: %v", index+1, totalChunks, err)
+		}
+	}
+
+	return c.completeChunkedUpload(uploadID, assignmentID, resource, totalChunks)
+}
+
+// receivedChunks asks the LMS which parts of an upload it already holds.
+// A 404 means the upload has not been started.
+func (c *LMSClient) receivedChunks(uploadID string) (map[int]bool, error) {
+	endpoint := fmt.Sprintf("%s/api/resources/chunk?uploadId=%s", c.BaseURL, url.QueryEscape(uploadID))
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	received := make(map[int]bool)
+	if resp.StatusCode == http.StatusNotFound {
+		return received, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, redactSecret(string(body), c.APIKey))
+	}
+
+	var response struct {
+		Received []int `json:"received"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	for _, index := range response.Received {
+		received[index] = true
+	}
+	return received, nil
+}
+
+// uploadChunk sends one part. Network failures and server errors are
+// retried by do as the client's Retry config allows; a chunk that still
+// fails stops the upload, which can be resumed later from that chunk.
+func (c *LMSClient) uploadChunk(uploadID string, index, totalChunks int, filename string, data []byte) error {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	part, err := writer.CreateFormFile("chunk", filename)
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %v", err)
+	}
+	part.Write(data)
+
+	writer.WriteField("uploadId", uploadID)
+	writer.WriteField("chunkIndex", strconv.Itoa(index))
+	writer.WriteField("totalChunks", strconv.Itoa(totalChunks))
+	writer.Close()
+
+	url := fmt.Sprintf("%s/api/resources/chunk", c.BaseURL)
+	req, err := http.NewRequest("POST", url, &buf)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("API error (%d): %s", resp.StatusCode, redactSecret(string(body), c.APIKey))
+	}
+	return nil
+}
+
+// completeChunkedUpload asks the LMS to reassemble the parts into a resource
+func (c *LMSClient) completeChunkedUpload(uploadID, assignmentID string, resource Resource, totalChunks int) (string, error) {
+	request := map[string]interface{}{
+		"uploadId":     uploadID,
+		"totalChunks":  totalChunks,
+		"filename":     filepath.Base(resource.LocalPath),
+		"title":        resource.Title,
+		"description":  resource.Description,
+		"type":         resource.Type,
+		"assignmentId": assignmentID,
+		"isPublic":     resource.IsPublic,
+		"checksum":     resource.Checksum,
+	}
+	if resource.URL != "" {
+		request["url"] = resource.URL
+	}
+	if resource.Order != 0 {
+		request["order"] = resource.Order
+	}
+	if len(resource.Tags) > 0 {
+		request["tags"] = resource.Tags
+	}
+	if len(resource.Metadata) > 0 {
+		request["metadata"] = resource.Metadata
+	}
+
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	url := fmt.Sprintf("%s/api/resources/chunk/complete", c.BaseURL)
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("API error (%d): %s", resp.StatusCode, redactSecret(string(body), c.APIKey))
+	}
+
+	var response struct {
+		Resource struct {
+			ID string `json:"id"`
+		} `json:"resource"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	return response.Resource.ID, nil
+}
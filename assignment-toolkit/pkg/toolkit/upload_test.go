@@ -0,0 +1,118 @@
+package toolkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUploadResourceChunkedResumesAndRetries(t *testing.T) {
+	sleep = func(time.Duration) {}
+	t.Cleanup(func() { sleep = time.Sleep })
+
+	content := bytes.Repeat([]byte("0123456789"), 5) // 50 bytes, 5 chunks of 10
+	path := filepath.Join(t.TempDir(), "lecture.mp3")
+	if err := ioutil.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	parts := map[int][]byte{0: content[:10]} // first chunk already uploaded
+	failed := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/resources/chunk":
+			received := []int{}
+			for index := range parts {
+				received = append(received, index)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"received": received})
+		case r.Method == "POST" && r.URL.Path == "/api/resources/chunk":
+			index, _ := strconv.Atoi(r.FormValue("chunkIndex"))
+			if index == 0 {
+				t.Errorf("chunk 0 was uploaded again")
+			}
+			if index == 2 && !failed {
+				failed = true
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			file, _, err := r.FormFile("chunk")
+			if err != nil {
+				t.Errorf("missing chunk: %v", err)
+				return
+			}
+			parts[index], _ = ioutil.ReadAll(file)
+		case r.Method == "POST" && r.URL.Path == "/api/resources/chunk/complete":
+			var assembled []byte
+			for index := 0; index < len(parts); index++ {
+				assembled = append(assembled, parts[index]...)
+			}
+			if !bytes.Equal(assembled, content) {
+				t.Errorf("reassembled %q, want %q", assembled, content)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"resource": map[string]string{"id": "res-1"}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewLMSClientWithRetry(server.URL, "key", DefaultRetryConfig)
+	client.ChunkSize = 10
+
+	id, err := client.uploadResource("assignment-1", Resource{Title: "Lecture", LocalPath: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "res-1" {
+		t.Errorf("resource id = %q, want res-1", id)
+	}
+	if !failed {
+		t.Error("expected the failing chunk to be retried")
+	}
+}
+
+func TestUploadResourcesRewritesURLs(t *testing.T) {
+	defer UseConfig(Config{})
+	path := filepath.Join(t.TempDir(), "map.png")
+	if err := ioutil.WriteFile(path, []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.FormValue("url"))
+		json.NewEncoder(w).Encode(map[string]interface{}{"resource": map[string]string{"id": "res-1"}})
+	}))
+	defer server.Close()
+	client := NewLMSClient(server.URL, "key")
+
+	resources := []Resource{
+		{Title: "Map", LocalPath: path, URL: "https://media.example.com/maps/world.png"},
+		{Title: "Other", LocalPath: path, URL: "https://elsewhere.example.org/a.png"},
+	}
+	UseConfig(Config{URLRewrites: []URLRewrite{{From: "https://media.example.com/", To: "https://proxy.school.internal/media/"}}})
+	if _, _, err := client.uploadResources("assignment-1", resources); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"https://proxy.school.internal/media/maps/world.png", "https://elsewhere.example.org/a.png"}
+	if len(sent) != 2 || sent[0] != want[0] || sent[1] != want[1] {
+		t.Errorf("sent URLs %q, want %q", sent, want)
+	}
+	if resources[0].URL != "https://media.example.com/maps/world.png" {
+		t.Errorf("package resource was modified: %s", resources[0].URL)
+	}
+}
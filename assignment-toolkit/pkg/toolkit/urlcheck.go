@@ -0,0 +1,130 @@
+package toolkit
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultURLCheckConcurrency is how many resource URLs are checked at once
+// when no limit is given
+const DefaultURLCheckConcurrency = 4
+
+// URLChecker checks that the URLs of remote resources still respond
+type URLChecker struct {
+	HTTPClient  *http.Client
+	Concurrency int
+}
+
+// NewURLChecker returns a checker that gives up on a URL after timeout and
+// checks at most concurrency URLs at once
+func NewURLChecker(timeout time.Duration, concurrency int) *URLChecker {
+	return &URLChecker{
+		HTTPClient:  &http.Client{Timeout: timeout},
+		Concurrency: concurrency,
+	}
+}
+
+// EnableHTTPLog writes every request and response the checker makes to
+// out, with bodies cut to maxBody bytes
+func (u *URLChecker) EnableHTTPLog(out io.Writer, maxBody int) {
+	u.HTTPClient = logHTTP(u.HTTPClient, out, "", maxBody)
+}
+
+// URLCheck is the outcome of checking one resource URL. StatusCode is zero
+// when the URL could not be reached at all.
+type URLCheck struct {
+	Resource   string
+	URL        string
+	StatusCode int
+	Err        error
+}
+
+// OK reports whether the URL answered with a 2xx status
+func (c URLCheck) OK() bool {
+	return c.Err == nil && c.StatusCode >= 200 && c.StatusCode < 300
+}
+
+// Dead reports whether the URL is unreachable or the server says the
+// resource is gone, as opposed to an error that may be temporary
+func (c URLCheck) Dead() bool {
+	return c.Err != nil || c.StatusCode == http.StatusNotFound || c.StatusCode == http.StatusGone
+}
+
+func (c URLCheck) String() string {
+	if c.Err != nil {
+		return fmt.Sprintf("Resource %q URL %s is unreachable: %v", c.Resource, c.URL, c.Err)
+	}
+	return fmt.Sprintf("Resource %q URL %s returned %d %s", c.Resource, c.URL, c.StatusCode, http.StatusText(c.StatusCode))
+}
+
+// Check sends a HEAD request to the URL of every resource that has an
+// http(s) URL, falling back to GET for servers that don't allow HEAD.
+// Results are in resource order.
+func (u *URLChecker) Check(resources []Resource) []URLCheck {
+	var checks []URLCheck
+	for _, resource := range resources {
+		url := strings.TrimSpace(resource.URL)
+		if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+			checks = append(checks, URLCheck{Resource: resource.Title, URL: url})
+		}
+	}
+
+	concurrency := u.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultURLCheckConcurrency
+	}
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range checks {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(check *URLCheck) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			check.StatusCode, check.Err = u.status(check.URL)
+		}(&checks[i])
+	}
+	wg.Wait()
+	return checks
+}
+
+// CheckPackage checks the package's resource URLs and records each that
+// doesn't respond in validation: dead links are errors, other non-2xx
+// responses are warnings. The score is left alone.
+func (u *URLChecker) CheckPackage(pkg AssignmentPackage, validation *ValidationInfo) {
+	for _, check := range u.Check(pkg.Resources) {
+		switch {
+		case check.OK():
+		case check.Dead():
+			validation.Errors = append(validation.Errors, check.String())
+			validation.IsValid = false
+		default:
+			validation.Warnings = append(validation.Warnings, check.String())
+		}
+	}
+}
+
+func (u *URLChecker) status(url string) (int, error) {
+	code, err := u.request("HEAD", url)
+	if err == nil && (code == http.StatusMethodNotAllowed || code == http.StatusNotImplemented) {
+		return u.request("GET", url)
+	}
+	return code, err
+}
+
+func (u *URLChecker) request(method, url string) (int, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := u.HTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,42 @@
+package toolkit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestURLCheckerReportsDeadLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+		case "/get-only":
+			if r.Method == "HEAD" {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		case "/busy":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	pkg := AssignmentPackage{Resources: []Resource{
+		{Title: "Video", URL: server.URL + "/ok"},
+		{Title: "Slides", URL: server.URL + "/get-only"},
+		{Title: "Map", URL: server.URL + "/busy"},
+		{Title: "Worksheet", URL: server.URL + "/gone"},
+		{Title: "Local", LocalPath: "map.png"},
+	}}
+	validation := ValidationInfo{IsValid: true}
+	NewURLChecker(5*time.Second, 2).CheckPackage(pkg, &validation)
+
+	if validation.IsValid || len(validation.Errors) != 1 || len(validation.Warnings) != 1 {
+		t.Fatalf("errors = %q, warnings = %q; want the 404 as an error and the 503 as a warning", validation.Errors, validation.Warnings)
+	}
+	if want := `Resource "Worksheet" URL ` + server.URL + "/gone returned 404 Not Found"; validation.Errors[0] != want {
+		t.Errorf("error = %q, want %q", validation.Errors[0], want)
+	}
+}
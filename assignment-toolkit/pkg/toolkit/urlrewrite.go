@@ -0,0 +1,29 @@
+package toolkit
+
+import "strings"
+
+// URLRewrite replaces the start of a resource URL, e.g. to send externally
+// hosted media through a school proxy:
+//
+//	url_rewrites:
+//	  - from: https://media.example.com/
+//	    to: https://proxy.school.internal/media/
+type URLRewrite struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
+
+// RewriteURL applies the first url_rewrites rule whose From prefix matches
+// url. URLs no rule matches, and every URL when none are configured, are
+// returned unchanged.
+func RewriteURL(url string) string {
+	if url == "" {
+		return url
+	}
+	for _, rule := range activeConfig.URLRewrites {
+		if rule.From != "" && strings.HasPrefix(url, rule.From) {
+			return rule.To + strings.TrimPrefix(url, rule.From)
+		}
+	}
+	return url
+}
@@ -0,0 +1,431 @@
+package toolkit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Validation severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// ValidationRule is a pluggable check run by ValidateAssignmentPackage
+type ValidationRule struct {
+	ID          string
+	Description string
+	Severity    string
+	Types       []string // Portable types the rule applies to; empty means all
+	Penalty     int      // Score deduction per finding
+	Check       func(pkg AssignmentPackage) []string
+}
+
+// validationRules holds the registered rules in registration order
+var validationRules []ValidationRule
+
+// RegisterValidationRule adds a rule to the validator
+func RegisterValidationRule(rule ValidationRule) {
+	validationRules = append(validationRules, rule)
+}
+
+// ValidationRules returns the registered rules in registration order
+func ValidationRules() []ValidationRule {
+	return append([]ValidationRule{}, validationRules...)
+}
+
+// RuleDisabled reports whether disabled_rules in the config turns a rule
+// off
+func RuleDisabled(id string) bool {
+	for _, disabled := range activeConfig.DisabledRules {
+		if disabled == id {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	RegisterValidationRule(ValidationRule{
+		ID:          "quarter-allowed",
+		Description: "Quarter must be one of the allowed values (Q1-Q4 plus allowed_quarters in config)",
+		Severity:    SeverityWarning,
+		Penalty:     5,
+		Check:       checkQuarter,
+	})
+	RegisterValidationRule(ValidationRule{
+		ID:          "difficulty-allowed",
+		Description: "Difficulty must be one of the allowed values (beginner, intermediate, advanced, or allowed_difficulties in config)",
+		Severity:    SeverityWarning,
+		Penalty:     2,
+		Check:       checkDifficulty,
+	})
+	RegisterValidationRule(ValidationRule{
+		ID:          "instructions-format",
+		Description: "instructions_format must be markdown or unset, and instructions with images need it set to markdown",
+		Severity:    SeverityWarning,
+		Penalty:     2,
+		Check:       checkInstructionsFormat,
+	})
+	RegisterValidationRule(ValidationRule{
+		ID:          "custom-keys-reserved",
+		Description: "Custom metadata keys must not reuse standard LMS payload field names",
+		Severity:    SeverityWarning,
+		Penalty:     2,
+		Check:       checkCustomKeys,
+	})
+	RegisterValidationRule(ValidationRule{
+		ID:          "attempts-tracked",
+		Description: "A max attempts limit requires track_attempts to be enabled",
+		Severity:    SeverityWarning,
+		Penalty:     5,
+		Check:       checkAttemptsTracked,
+	})
+	RegisterValidationRule(ValidationRule{
+		ID:          "writing-manual-grade",
+		Description: "Writing assignments cannot be auto-graded",
+		Severity:    SeverityError,
+		Types:       []string{"writing", "writing-short", "writing-long", "essay"},
+		Penalty:     10,
+		Check:       checkWritingAutoGrade,
+	})
+	RegisterValidationRule(ValidationRule{
+		ID:          "word-limits",
+		Description: "Word limits must be positive and min_words must be less than max_words",
+		Severity:    SeverityError,
+		Penalty:     10,
+		Check:       checkWordLimits,
+	})
+	RegisterValidationRule(ValidationRule{
+		ID:          "matching-pairs",
+		Description: "Matching pairs need a left and right item, and each left item may appear only once",
+		Severity:    SeverityError,
+		Types:       []string{"matching"},
+		Penalty:     10,
+		Check:       checkMatchingPairs,
+	})
+	RegisterValidationRule(ValidationRule{
+		ID:          "code-submission-config",
+		Description: "Code submissions need a code_submission_config with a programming language and positive file limits (validate --fix moves one left under questions)",
+		Severity:    SeverityError,
+		Types:       []string{"code-submission"},
+		Penalty:     10,
+		Check:       checkCodeSubmissionConfig,
+	})
+	RegisterValidationRule(ValidationRule{
+		ID:          "code-submission-language",
+		Description: "The programming language should be one the LMS knows",
+		Severity:    SeverityWarning,
+		Types:       []string{"code-submission"},
+		Penalty:     2,
+		Check:       checkProgrammingLanguage,
+	})
+	RegisterValidationRule(ValidationRule{
+		ID:          "code-submission-expected-output",
+		Description: "Auto-graded code submissions should set an expected output",
+		Severity:    SeverityWarning,
+		Types:       []string{"code-submission"},
+		Penalty:     5,
+		Check:       checkExpectedOutput,
+	})
+	RegisterValidationRule(ValidationRule{
+		ID:          "educational-metadata",
+		Description: "Learning objectives and prerequisites must not be blank, padded, repeated, or overlong (validate --fix cleans them)",
+		Severity:    SeverityWarning,
+		Penalty:     1,
+		Check:       checkEducationalMetadata,
+	})
+	RegisterValidationRule(ValidationRule{
+		ID:          "resource-order-unique",
+		Description: "Resources must not share an order value (validate --fix renumbers them)",
+		Severity:    SeverityWarning,
+		Penalty:     2,
+		Check:       checkResourceOrder,
+	})
+	RegisterValidationRule(ValidationRule{
+		ID:          "media-upload-ready",
+		Description: "Audio and video files should be within media_max_size_mb and not in rejected_media_formats",
+		Severity:    SeverityWarning,
+		Penalty:     2,
+		Check:       CheckMediaResources,
+	})
+}
+
+// ValidateAssignmentPackage checks the package structure and content and
+// returns the findings with a quality score from 0 to 100
+func ValidateAssignmentPackage(pkg AssignmentPackage) ValidationInfo {
+	validation := ValidationInfo{
+		IsValid:          true,
+		ValidatedAt:      Now(),
+		ValidatorVersion: "1.0.0",
+		Score:            100,
+	}
+
+	// Basic validation
+	if pkg.Assignment.Title == "" {
+		validation.Errors = append(validation.Errors, "Assignment title is required")
+		validation.IsValid = false
+		validation.Score -= 20
+	}
+
+	if pkg.Assignment.Type == "" {
+		validation.Errors = append(validation.Errors, "Assignment type is required")
+		validation.IsValid = false
+		validation.Score -= 20
+	}
+
+	// Type-specific validation
+	switch pkg.Assignment.Type {
+	case "multiple-choice":
+		if pkg.Assignment.Questions == nil {
+			validation.Errors = append(validation.Errors, "Multiple choice questions are required")
+			validation.IsValid = false
+			validation.Score -= 30
+		}
+	case "matching":
+		if pkg.Assignment.Questions == nil {
+			validation.Errors = append(validation.Errors, "Matching items are required")
+			validation.IsValid = false
+			validation.Score -= 30
+		}
+	}
+
+	if mode := pkg.Assignment.ScoringMode; mode != "" && mode != ScoringAllOrNothing && mode != ScoringPartial {
+		validation.Errors = append(validation.Errors, fmt.Sprintf("Unknown scoring mode %q (expected %s or %s)", mode, ScoringAllOrNothing, ScoringPartial))
+		validation.IsValid = false
+		validation.Score -= 10
+	}
+
+	// Warnings
+	if pkg.Assignment.Description == "" {
+		validation.Warnings = append(validation.Warnings, "Assignment description is recommended")
+		validation.Score -= 5
+	}
+
+	if pkg.Assignment.Points <= 0 {
+		validation.Warnings = append(validation.Warnings, "Assignment should have positive points")
+		validation.Score -= 10
+	}
+
+	applyValidationRules(pkg, &validation)
+
+	return validation
+}
+
+// applyValidationRules runs every registered rule that applies to the
+// package and records its findings
+func applyValidationRules(pkg AssignmentPackage, validation *ValidationInfo) {
+	for _, rule := range validationRules {
+		if RuleDisabled(rule.ID) || !ruleAppliesTo(rule, pkg.Assignment.Type) {
+			continue
+		}
+
+		for _, finding := range rule.Check(pkg) {
+			validation.Score -= rule.Penalty
+			if rule.Severity == SeverityError {
+				validation.Errors = append(validation.Errors, finding)
+				validation.IsValid = false
+			} else {
+				validation.Warnings = append(validation.Warnings, finding)
+			}
+		}
+	}
+
+	if validation.Score < 0 {
+		validation.Score = 0
+	}
+}
+
+func ruleAppliesTo(rule ValidationRule, assignmentType string) bool {
+	if len(rule.Types) == 0 {
+		return true
+	}
+
+	resolved := assignmentType
+	if mapping, err := GetTypeManager().ResolveType(assignmentType); err == nil {
+		resolved = mapping.PortableType
+	}
+	for _, ruleType := range rule.Types {
+		if ruleType == assignmentType || ruleType == resolved {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultQuarters is the built-in set of allowed quarter values
+var defaultQuarters = []string{"Q1", "Q2", "Q3", "Q4"}
+
+// allowedQuarters returns the default quarters plus any configured extras
+func allowedQuarters() []string {
+	quarters := append([]string{}, defaultQuarters...)
+	for _, extra := range activeConfig.AllowedQuarters {
+		if extra = strings.TrimSpace(extra); extra != "" {
+			quarters = append(quarters, extra)
+		}
+	}
+	return quarters
+}
+
+// NormalizeQuarter trims the value and returns the canonical spelling from
+// the allowed set, or the trimmed value when it is not recognised
+func NormalizeQuarter(quarter string) string {
+	trimmed := strings.TrimSpace(quarter)
+	for _, allowed := range allowedQuarters() {
+		if strings.EqualFold(trimmed, allowed) {
+			return allowed
+		}
+	}
+	return trimmed
+}
+
+func checkQuarter(pkg AssignmentPackage) []string {
+	quarter := pkg.Assignment.Quarter
+	if quarter == "" {
+		return nil
+	}
+
+	normalized := NormalizeQuarter(quarter)
+	for _, allowed := range allowedQuarters() {
+		if normalized == allowed {
+			if quarter != allowed {
+				return []string{fmt.Sprintf("Quarter %q should be written as %q", quarter, allowed)}
+			}
+			return nil
+		}
+	}
+
+	return []string{fmt.Sprintf("Unknown quarter %q (allowed: %s)", quarter, strings.Join(allowedQuarters(), ", "))}
+}
+
+// DefaultDifficulties is the built-in set of difficulty levels
+var DefaultDifficulties = []string{"beginner", "intermediate", "advanced"}
+
+// AllowedDifficulties returns allowed_difficulties from the config, or the
+// default levels when it is not set
+func AllowedDifficulties() []string {
+	var difficulties []string
+	for _, difficulty := range activeConfig.AllowedDifficulties {
+		if difficulty = strings.TrimSpace(difficulty); difficulty != "" {
+			difficulties = append(difficulties, difficulty)
+		}
+	}
+	if len(difficulties) == 0 {
+		return append([]string{}, DefaultDifficulties...)
+	}
+	return difficulties
+}
+
+// NormalizeDifficulty trims the value and returns the canonical spelling
+// from the allowed set, or the trimmed value when it is not recognised
+func NormalizeDifficulty(difficulty string) string {
+	trimmed := strings.TrimSpace(difficulty)
+	for _, allowed := range AllowedDifficulties() {
+		if strings.EqualFold(trimmed, allowed) {
+			return allowed
+		}
+	}
+	return trimmed
+}
+
+func checkDifficulty(pkg AssignmentPackage) []string {
+	difficulty := pkg.Assignment.Difficulty
+	if difficulty == "" {
+		return nil
+	}
+
+	normalized := NormalizeDifficulty(difficulty)
+	for _, allowed := range AllowedDifficulties() {
+		if normalized == allowed {
+			if difficulty != allowed {
+				return []string{fmt.Sprintf("Difficulty %q should be written as %q", difficulty, allowed)}
+			}
+			return nil
+		}
+	}
+
+	return []string{fmt.Sprintf("Unknown difficulty %q (allowed: %s)", difficulty, strings.Join(AllowedDifficulties(), ", "))}
+}
+
+func checkInstructionsFormat(pkg AssignmentPackage) []string {
+	assignment := pkg.Assignment
+	switch {
+	case assignment.InstructionsFormat != "" && !MarkdownInstructions(assignment):
+		return []string{fmt.Sprintf("Unknown instructions_format %q (use %q or leave it unset for plain text)", assignment.InstructionsFormat, InstructionsMarkdown)}
+	case !MarkdownInstructions(assignment) && markdownImage.MatchString(assignment.Instructions):
+		return []string{"Instructions embed images but instructions_format is not markdown, so the images are sent as plain text"}
+	}
+	return nil
+}
+
+func checkCustomKeys(pkg AssignmentPackage) []string {
+	var findings []string
+	for key := range pkg.Metadata.Custom {
+		if IsReservedPayloadKey(key) {
+			findings = append(findings, fmt.Sprintf("Custom metadata key %q is a reserved payload field and will only be sent under \"custom\"", key))
+		}
+	}
+	sort.Strings(findings)
+	return findings
+}
+
+func checkAttemptsTracked(pkg AssignmentPackage) []string {
+	assignment := pkg.Assignment
+	if assignment.MaxAttempts == nil {
+		return nil
+	}
+
+	var findings []string
+	if *assignment.MaxAttempts < 1 {
+		findings = append(findings, fmt.Sprintf("Max attempts must be at least 1 (got %d)", *assignment.MaxAttempts))
+	}
+	if !assignment.TrackAttempts {
+		findings = append(findings, "Max attempts is set but track_attempts is false, so the limit cannot be enforced")
+	}
+	return findings
+}
+
+func checkWritingAutoGrade(pkg AssignmentPackage) []string {
+	if pkg.Assignment.AutoGrade {
+		return []string{fmt.Sprintf("Auto-grading is not supported for %s assignments; set auto_grade to false", pkg.Assignment.Type)}
+	}
+	return nil
+}
+
+func checkWordLimits(pkg AssignmentPackage) []string {
+	minWords, maxWords := pkg.Assignment.MinWords, pkg.Assignment.MaxWords
+
+	var findings []string
+	if minWords != nil && *minWords < 0 {
+		findings = append(findings, fmt.Sprintf("Minimum words cannot be negative (got %d)", *minWords))
+	}
+	if maxWords != nil && *maxWords < 1 {
+		findings = append(findings, fmt.Sprintf("Maximum words must be at least 1 (got %d)", *maxWords))
+	}
+	if minWords != nil && maxWords != nil && *minWords >= *maxWords {
+		findings = append(findings, fmt.Sprintf("Minimum words (%d) must be less than maximum words (%d)", *minWords, *maxWords))
+	}
+	return findings
+}
+
+func checkResourceOrder(pkg AssignmentPackage) []string {
+	titles := make(map[int][]string)
+	for _, resource := range pkg.Resources {
+		titles[resource.Order] = append(titles[resource.Order], resource.Title)
+	}
+
+	var orders []int
+	for order, shared := range titles {
+		if len(shared) > 1 {
+			orders = append(orders, order)
+		}
+	}
+	sort.Ints(orders)
+
+	var findings []string
+	for _, order := range orders {
+		findings = append(findings, fmt.Sprintf("Resources share order %d: %s", order, strings.Join(titles[order], ", ")))
+	}
+	return findings
+}
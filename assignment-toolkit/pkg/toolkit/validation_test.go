@@ -0,0 +1,50 @@
+package toolkit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDisabledRulesAreSkipped(t *testing.T) {
+	defer UseConfig(Config{})
+	pkg := AssignmentPackage{Assignment: Assignment{
+		Title: "Essay", Type: "essay", Description: "Write", Points: 10, Quarter: "Q9",
+	}}
+
+	UseConfig(Config{})
+	if warnings := ValidateAssignmentPackage(pkg).Warnings; len(warnings) != 1 || !strings.Contains(warnings[0], "Q9") {
+		t.Fatalf("expected one quarter warning, got %v", warnings)
+	}
+
+	UseConfig(Config{DisabledRules: []string{"quarter-allowed"}})
+	validation := ValidateAssignmentPackage(pkg)
+	if len(validation.Warnings) != 0 || validation.Score != 100 {
+		t.Errorf("disabled rule still ran: %v (score %d)", validation.Warnings, validation.Score)
+	}
+}
+
+func TestDifficultyAllowed(t *testing.T) {
+	t.Cleanup(func() { UseConfig(Config{}) })
+	check := func(difficulty string) []string {
+		return checkDifficulty(AssignmentPackage{Assignment: Assignment{Difficulty: difficulty}})
+	}
+
+	UseConfig(Config{})
+	if findings := check("intermediate"); len(findings) != 0 {
+		t.Errorf("intermediate: %v", findings)
+	}
+	if findings := check("Advanced"); len(findings) != 1 || !strings.Contains(findings[0], `should be written as "advanced"`) {
+		t.Errorf("Advanced: %v", findings)
+	}
+	if findings := check("intermedaite"); len(findings) != 1 || !strings.Contains(findings[0], "Unknown difficulty") {
+		t.Errorf("intermedaite: %v", findings)
+	}
+
+	UseConfig(Config{AllowedDifficulties: []string{"easy", "hard"}})
+	if findings := check("beginner"); len(findings) != 1 {
+		t.Errorf("beginner with custom levels: %v", findings)
+	}
+	if got := NormalizeDifficulty(" HARD "); got != "hard" {
+		t.Errorf("NormalizeDifficulty = %q, want hard", got)
+	}
+}
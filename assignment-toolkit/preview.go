@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(previewCmd)
+}
+
+// Preview command
+var previewCmd = &cobra.Command{
+	Use:   "preview [file]",
+	Short: "Show an assignment as students will read it",
+	Long: `Print the title, description, and instructions of an assignment. Markdown
+instructions (instructions_format: markdown) are rendered for the terminal,
+with each image shown as the resource, file, or URL it resolves to; images
+whose local file is missing are flagged.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAssignmentFile,
+	RunE:              runPreview,
+}
+
+func runPreview(cmd *cobra.Command, args []string) error {
+	pkg, err := toolkit.LoadPackage(args[0])
+	if err != nil {
+		return failf("Failed to load assignment: %v", err)
+	}
+	assignment := pkg.Assignment
+
+	fmt.Println(assignment.Title)
+	fmt.Println(strings.Repeat("=", len([]rune(assignment.Title))))
+	fmt.Printf("%s, %d point(s)\n", assignment.Type, assignment.Points)
+	if assignment.Description != "" {
+		fmt.Println()
+		fmt.Println(assignment.Description)
+	}
+	if assignment.Instructions == "" {
+		return nil
+	}
+
+	fmt.Println()
+	if !toolkit.MarkdownInstructions(assignment) {
+		fmt.Println(assignment.Instructions)
+		return nil
+	}
+	missing := renderMarkdown(pkg, assignment.Instructions)
+	if missing > 0 {
+		fmt.Println()
+		printWarning("%d instruction image(s) not found", missing)
+		return errFailed
+	}
+	return nil
+}
+
+var (
+	markdownHeading  = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	markdownBullet   = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	markdownImageRef = regexp.MustCompile(`!\[([^\]]*)\]\(\s*([^)\s]+)(\s+"[^"]*")?\s*\)`)
+	markdownLink     = regexp.MustCompile(`\[([^\]]+)\]\(\s*([^)\s]+)[^)]*\)`)
+	markdownEmphasis = regexp.MustCompile("(\\*\\*|__|\\*|_|`)([^*_`]+)(\\*\\*|__|\\*|_|`)")
+)
+
+// renderMarkdown prints Markdown instructions for the terminal: headings
+// are underlined, list bullets use the theme's bullet, emphasis markers are
+// dropped, links show their URL, and images show what they resolve to. It
+// returns the number of images whose local file is missing.
+func renderMarkdown(pkg toolkit.AssignmentPackage, text string) int {
+	missing := 0
+	for _, line := range strings.Split(text, "\n") {
+		line = markdownImageRef.ReplaceAllStringFunc(line, func(image string) string {
+			match := markdownImageRef.FindStringSubmatch(image)
+			target, found := describeInstructionImage(pkg, match[2])
+			if !found {
+				missing++
+			}
+			if match[1] == "" {
+				return fmt.Sprintf("[image: %s]", target)
+			}
+			return fmt.Sprintf("[image %q: %s]", match[1], target)
+		})
+		line = markdownLink.ReplaceAllString(line, "$1 <$2>")
+		line = markdownEmphasis.ReplaceAllString(line, "$2")
+
+		if match := markdownHeading.FindStringSubmatch(line); match != nil {
+			underline := "-"
+			if len(match[1]) == 1 {
+				underline = "="
+			}
+			fmt.Println(match[2])
+			fmt.Println(strings.Repeat(underline, len([]rune(match[2]))))
+			continue
+		}
+		if match := markdownBullet.FindStringSubmatch(line); match != nil {
+			fmt.Printf("%s  %s %s\n", match[1], icon(iconBullet), match[2])
+			continue
+		}
+		fmt.Println(line)
+	}
+	return missing
+}
+
+// describeInstructionImage names what an image reference resolves to and
+// reports whether it can be found
+func describeInstructionImage(pkg toolkit.AssignmentPackage, ref string) (string, bool) {
+	for _, resource := range pkg.Resources {
+		if resource.ID == ref {
+			return fmt.Sprintf("resource %q", resource.Title), true
+		}
+	}
+	if strings.Contains(ref, "://") || strings.HasPrefix(ref, "data:") {
+		return ref, true
+	}
+	if _, err := os.Stat(ref); err != nil {
+		return ref + " (missing)", false
+	}
+	return ref, true
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"assignment-toolkit/pkg/toolkit"
+)
+
+// runPrintCurl writes the body sync would upload for pkg and prints a curl
+// command that sends it, for reproducing a sync with LMS admins
+func runPrintCurl(config toolkit.Config, pkg toolkit.AssignmentPackage, filename, payloadFile string) error {
+	client, err := newLMSClientFromConfig(config)
+	if err != nil {
+		return failf("Invalid sync configuration: %v", err)
+	}
+
+	if payloadFile == "" {
+		payloadFile = payloadFileName(filename, config.GzipUploads)
+	}
+	command, body, err := client.SyncCurl(pkg, payloadFile)
+	if err != nil {
+		return failf("Failed to build the request: %v", err)
+	}
+	if err := ioutil.WriteFile(payloadFile, body, 0644); err != nil {
+		return failf("Failed to write %s: %v", payloadFile, err)
+	}
+
+	printSuccess("Wrote the request body to %s; nothing was synced", payloadFile)
+	printHint("Replace [REDACTED] with the API key before running the command")
+	fmt.Println()
+	fmt.Println(command)
+	return nil
+}
+
+// payloadFileName is the default --payload-file for an assignment file,
+// e.g. quiz.payload.json for quiz.yaml
+func payloadFileName(filename string, compressed bool) string {
+	name := strings.TrimSuffix(filepath.Base(filename), ".gz")
+	name = strings.TrimSuffix(name, filepath.Ext(name)) + ".payload.json"
+	if compressed {
+		name += ".gz"
+	}
+	return name
+}
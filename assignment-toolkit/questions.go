@@ -0,0 +1,265 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	questionsAddCmd.Flags().Int("position", 0, "Insert the new question at this position (1-based, default: end)")
+	questionsAddCmd.Flags().Bool("keep-points", false, "Do not adjust assignment points")
+	questionsRemoveCmd.Flags().Bool("keep-points", false, "Do not adjust assignment points")
+
+	questionsCmd.AddCommand(questionsListCmd)
+	questionsCmd.AddCommand(questionsAddCmd)
+	questionsCmd.AddCommand(questionsRemoveCmd)
+	questionsCmd.AddCommand(questionsMoveCmd)
+	rootCmd.AddCommand(questionsCmd)
+}
+
+// Questions command
+var questionsCmd = &cobra.Command{
+	Use:   "questions",
+	Short: "Edit the question list of an assignment",
+	Long:  "Add, remove, or reorder individual questions of an existing assignment in place",
+}
+
+var questionsListCmd = &cobra.Command{
+	Use:               "list [file]",
+	Short:             "List the questions of an assignment",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAssignmentFile,
+	RunE:              runQuestionsList,
+}
+
+var questionsAddCmd = &cobra.Command{
+	Use:               "add [file]",
+	Short:             "Add a question using the interactive wizard",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAssignmentFile,
+	RunE:              runQuestionsAdd,
+}
+
+var questionsRemoveCmd = &cobra.Command{
+	Use:               "remove [file] [number]",
+	Short:             "Remove a question by its number",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeAssignmentFile,
+	RunE:              runQuestionsRemove,
+}
+
+var questionsMoveCmd = &cobra.Command{
+	Use:               "move [file] [from] [to]",
+	Short:             "Move a question to a new position",
+	Args:              cobra.ExactArgs(3),
+	ValidArgsFunction: completeAssignmentFile,
+	RunE:              runQuestionsMove,
+}
+
+func runQuestionsList(cmd *cobra.Command, args []string) error {
+	pkg, err := toolkit.LoadPackage(args[0])
+	if err != nil {
+		return failf("Failed to load assignment: %v", err)
+	}
+
+	questions := questionList(pkg.Assignment)
+	if len(questions) == 0 {
+		fmt.Println("No questions found in this assignment.")
+		return nil
+	}
+
+	printMessage(iconInfo, "%s (%d question(s), %d points)", pkg.Assignment.Title, len(questions), pkg.Assignment.Points)
+	fmt.Println()
+	for i, question := range questions {
+		fmt.Printf("  %d. %s\n", i+1, questionSummary(question))
+	}
+	return nil
+}
+
+func runQuestionsAdd(cmd *cobra.Command, args []string) error {
+	filename := args[0]
+	position, _ := cmd.Flags().GetInt("position")
+	keepPoints, _ := cmd.Flags().GetBool("keep-points")
+
+	pkg, err := toolkit.LoadPackage(filename)
+	if err != nil {
+		return failf("Failed to load assignment: %v", err)
+	}
+
+	var question interface{}
+	switch resolveQuestionType(pkg.Assignment.Type) {
+	case "multiple-choice":
+		question = createMultipleChoiceQuestions()
+	case "matching":
+		question = createMatchingQuestions()
+	default:
+		return failf("Question editing is not supported for %s assignments", pkg.Assignment.Type)
+	}
+
+	questions := questionList(pkg.Assignment)
+	oldCount := len(questions)
+	if position < 1 || position > len(questions) {
+		position = len(questions) + 1
+	}
+
+	questions = append(questions, nil)
+	copy(questions[position:], questions[position-1:])
+	questions[position-1] = question
+
+	if !keepPoints {
+		adjustQuestionPoints(&pkg.Assignment, oldCount, len(questions))
+	}
+
+	if err := saveQuestionList(&pkg, questions, filename); err != nil {
+		return failf("Failed to save assignment: %v", err)
+	}
+
+	printSuccess("Question added at position %d (%d question(s) total)", position, len(questions))
+	return nil
+}
+
+func runQuestionsRemove(cmd *cobra.Command, args []string) error {
+	filename := args[0]
+	keepPoints, _ := cmd.Flags().GetBool("keep-points")
+
+	pkg, err := toolkit.LoadPackage(filename)
+	if err != nil {
+		return failf("Failed to load assignment: %v", err)
+	}
+
+	questions := questionList(pkg.Assignment)
+	index, err := parseQuestionNumber(args[1], len(questions))
+	if err != nil {
+		return failf("%v", err)
+	}
+
+	removed := questions[index]
+	oldCount := len(questions)
+	questions = append(questions[:index], questions[index+1:]...)
+
+	if !keepPoints {
+		adjustQuestionPoints(&pkg.Assignment, oldCount, len(questions))
+	}
+
+	if err := saveQuestionList(&pkg, questions, filename); err != nil {
+		return failf("Failed to save assignment: %v", err)
+	}
+
+	printSuccess("Removed question %d: %s", index+1, questionSummary(removed))
+	return nil
+}
+
+func runQuestionsMove(cmd *cobra.Command, args []string) error {
+	filename := args[0]
+
+	pkg, err := toolkit.LoadPackage(filename)
+	if err != nil {
+		return failf("Failed to load assignment: %v", err)
+	}
+
+	questions := questionList(pkg.Assignment)
+	from, err := parseQuestionNumber(args[1], len(questions))
+	if err != nil {
+		return failf("%v", err)
+	}
+	to, err := parseQuestionNumber(args[2], len(questions))
+	if err != nil {
+		return failf("%v", err)
+	}
+
+	question := questions[from]
+	questions = append(questions[:from], questions[from+1:]...)
+	questions = append(questions, nil)
+	copy(questions[to+1:], questions[to:])
+	questions[to] = question
+
+	if err := saveQuestionList(&pkg, questions, filename); err != nil {
+		return failf("Failed to save assignment: %v", err)
+	}
+
+	printSuccess("Moved question %d to position %d", from+1, to+1)
+	return nil
+}
+
+// questionList returns the assignment questions as a list, treating a
+// single question object as a list of one
+func questionList(assignment toolkit.Assignment) []interface{} {
+	switch questions := assignment.Questions.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return questions
+	default:
+		return []interface{}{questions}
+	}
+}
+
+// resolveQuestionType maps aliases such as "mcq" or "quiz" onto the
+// question structure the wizard knows how to build
+func resolveQuestionType(assignmentType string) string {
+	mapping, err := toolkit.GetTypeManager().ResolveType(assignmentType)
+	if err != nil {
+		return assignmentType
+	}
+	return mapping.LMSType
+}
+
+// adjustQuestionPoints rescales assignment points when they were evenly
+// distributed across the previous question count
+func adjustQuestionPoints(assignment *toolkit.Assignment, oldCount, newCount int) {
+	if oldCount == 0 || newCount == 0 || assignment.Points%oldCount != 0 {
+		return
+	}
+
+	perQuestion := assignment.Points / oldCount
+	newPoints := perQuestion * newCount
+	if newPoints != assignment.Points {
+		printMessage(iconInfo, "Points updated: %d %s %d (%d per question)", assignment.Points, icon(iconArrow), newPoints, perQuestion)
+		assignment.Points = newPoints
+	}
+}
+
+func saveQuestionList(pkg *toolkit.AssignmentPackage, questions []interface{}, filename string) error {
+	if len(questions) == 0 {
+		pkg.Assignment.Questions = nil
+	} else {
+		pkg.Assignment.Questions = questions
+	}
+
+	pkg.Metadata.Modified = toolkit.Now()
+	pkg.Metadata.SourceHash = toolkit.CalculateHash(*pkg)
+
+	return toolkit.SavePackage(*pkg, filename)
+}
+
+func parseQuestionNumber(value string, count int) (int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 || number > count {
+		return 0, fmt.Errorf("invalid question number %q (expected 1-%d)", value, count)
+	}
+	return number - 1, nil
+}
+
+func questionSummary(question interface{}) string {
+	fields, ok := question.(map[string]interface{})
+	if !ok {
+		return fmt.Sprintf("%v", question)
+	}
+
+	if text, ok := fields["question"].(string); ok && text != "" {
+		return text
+	}
+	if pairs, ok := fields["pairs"].([]interface{}); ok {
+		return fmt.Sprintf("Matching (%d pairs)", len(pairs))
+	}
+	if left, ok := fields["leftItems"].([]interface{}); ok {
+		return fmt.Sprintf("Matching (%d pairs)", len(left))
+	}
+	if left, ok := fields["leftItems"].([]string); ok {
+		return fmt.Sprintf("Matching (%d pairs)", len(left))
+	}
+	return "(untitled question)"
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"assignment-toolkit/pkg/toolkit"
+)
+
+var fixedNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// useFixedClock pins the package clock for the duration of a test
+func useFixedClock(t *testing.T) {
+	t.Helper()
+	previous := toolkit.Now
+	toolkit.Now = func() time.Time { return fixedNow }
+	t.Cleanup(func() { toolkit.Now = previous })
+}
+
+func TestSaveQuestionListStampsModified(t *testing.T) {
+	useFixedClock(t)
+
+	pkg := toolkit.AssignmentPackage{
+		Assignment: toolkit.Assignment{
+			Title:     "Quiz",
+			Type:      "multiple-choice",
+			Points:    2,
+			Questions: map[string]interface{}{"question": "First?"},
+		},
+	}
+
+	questions := append(questionList(pkg.Assignment), map[string]interface{}{"question": "Second?"})
+	adjustQuestionPoints(&pkg.Assignment, 1, len(questions))
+
+	filename := filepath.Join(t.TempDir(), "quiz.yaml")
+	if err := saveQuestionList(&pkg, questions, filename); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := toolkit.LoadPackage(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !saved.Metadata.Modified.Equal(fixedNow) {
+		t.Errorf("Modified = %v, want %v", saved.Metadata.Modified, fixedNow)
+	}
+	if saved.Assignment.Points != 4 {
+		t.Errorf("Points = %d, want 4", saved.Assignment.Points)
+	}
+	if got := len(questionList(saved.Assignment)); got != 2 {
+		t.Errorf("question count = %d, want 2", got)
+	}
+}
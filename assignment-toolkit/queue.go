@@ -0,0 +1,400 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// queueFile records syncs deferred until the LMS is reachable
+const queueFile = ".assignment-queue.yaml"
+
+// queueEntry is one pending sync
+type queueEntry struct {
+	File      string    `yaml:"file"`
+	AddedAt   time.Time `yaml:"added_at"`
+	Attempts  int       `yaml:"attempts,omitempty"`
+	LastError string    `yaml:"last_error,omitempty"`
+}
+
+func init() {
+	queueSyncCmd.Flags().Bool("strict-types", false, strictTypesUsage)
+	queueSyncCmd.Flags().Duration("timeout-per-resource", 0, timeoutPerResourceUsage)
+	queueSyncCmd.Flags().BoolP("yes", "y", false, yesUsage)
+	queueSyncCmd.Flags().Bool("force", false, "Sync even the assignments the LMS already has with the same source hash")
+	queueSyncCmd.Flags().Bool("summary-only", false, "Print just the final counts and the failures instead of a line per assignment")
+	queueSyncCmd.Flags().Bool("json", false, "Print the batch result as JSON (with --summary-only, only the entries that didn't sync cleanly)")
+	queueSyncCmd.Flags().Duration("deadline", 0, "Stop after this long (e.g. 30m), cancelling the upload in progress; unsynced assignments stay queued")
+	queueCmd.AddCommand(queueAddCmd)
+	queueCmd.AddCommand(queueListCmd)
+	queueCmd.AddCommand(queueSyncCmd)
+	rootCmd.AddCommand(queueCmd)
+}
+
+// Queue command
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Defer syncs until the LMS is reachable",
+	Long: `Queue assignments while offline and sync them all once connectivity returns.
+Assignments that fail to sync stay queued for the next attempt.`,
+}
+
+var queueAddCmd = &cobra.Command{
+	Use:               "add [file...]",
+	Short:             "Queue assignments for the next sync",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeAssignmentFiles,
+	RunE:              runQueueAdd,
+}
+
+var queueListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List queued assignments",
+	Args:  cobra.NoArgs,
+	RunE:  runQueueList,
+}
+
+var queueSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync every queued assignment with the LMS",
+	Args:  cobra.NoArgs,
+	RunE:  runQueueSync,
+}
+
+func runQueueAdd(cmd *cobra.Command, args []string) error {
+	queue, err := loadQueue()
+	if err != nil {
+		return failf("Failed to read queue: %v", err)
+	}
+
+	failed := 0
+	for _, file := range args {
+		if _, err := toolkit.LoadPackage(file); err != nil {
+			printError("%s: failed to load: %v", file, err)
+			failed++
+			continue
+		}
+		if queueIndex(queue, file) >= 0 {
+			printMessage(iconNote, "%s is already queued", file)
+			continue
+		}
+
+		queue = append(queue, queueEntry{File: filepath.Clean(file), AddedAt: toolkit.Now()})
+		printSuccess("Queued %s", file)
+	}
+
+	if err := saveQueue(queue); err != nil {
+		return failf("Failed to save queue: %v", err)
+	}
+	if failed > 0 {
+		return errFailed
+	}
+	return nil
+}
+
+func runQueueList(cmd *cobra.Command, args []string) error {
+	queue, err := loadQueue()
+	if err != nil {
+		return failf("Failed to read queue: %v", err)
+	}
+	if len(queue) == 0 {
+		fmt.Println("The sync queue is empty.")
+		return nil
+	}
+
+	fmt.Printf("%-35s %-17s %s\n", "FILE", "QUEUED", "STATUS")
+	fmt.Println("------------------------------------------------------------------------")
+	for _, entry := range queue {
+		status := "pending"
+		if entry.LastError != "" {
+			status = fmt.Sprintf("failed %d time(s): %s", entry.Attempts, entry.LastError)
+		}
+		fmt.Printf("%-35s %-17s %s\n", entry.File, entry.AddedAt.Format("2006-01-02 15:04"), status)
+	}
+	return nil
+}
+
+func runQueueSync(cmd *cobra.Command, args []string) error {
+	queue, err := loadQueue()
+	if err != nil {
+		return failf("Failed to read queue: %v", err)
+	}
+	if len(queue) == 0 {
+		fmt.Println("The sync queue is empty.")
+		return nil
+	}
+
+	config := getConfig()
+	applyStrictTypesFlag(cmd, &config)
+	if config.LMSEndpoint == "" {
+		return failf("LMS endpoint not configured. Run 'assignment-toolkit config set lms-endpoint <url>'")
+	}
+	if err := confirmSync(cmd, config); err != nil {
+		return err
+	}
+
+	client, err := newLMSClientFromConfig(config)
+	if err != nil {
+		return failf("Invalid sync configuration: %v", err)
+	}
+
+	client.ResourceTimeout, _ = cmd.Flags().GetDuration("timeout-per-resource")
+	if deadline, _ := cmd.Flags().GetDuration("deadline"); deadline > 0 {
+		client.Deadline = time.Now().Add(deadline)
+	}
+
+	if err := client.TestConnection(); err != nil {
+		printError("LMS is not reachable: %v", err)
+		printHint("%d assignment(s) remain queued; run 'assignment-toolkit queue sync' again when you are online", len(queue))
+		return errNetwork
+	}
+
+	summaryOnly, _ := cmd.Flags().GetBool("summary-only")
+	force, _ := cmd.Flags().GetBool("force")
+	asJSON, _ := cmd.Flags().GetBool("json")
+	verbose := !summaryOnly && !asJSON
+	if verbose {
+		printMessage(iconSync, "Syncing %d queued assignment(s) with %s...", len(queue), config.LMSEndpoint)
+	}
+
+	batch := &toolkit.BatchImportResult{
+		BatchID:    uuid.New().String(),
+		TotalCount: len(queue),
+		Results:    make([]toolkit.ImportResult, 0, len(queue)),
+		StartedAt:  toolkit.Now(),
+	}
+	var remaining []queueEntry
+	unattempted := 0
+	for i, entry := range queue {
+		if !client.Deadline.IsZero() && !time.Now().Before(client.Deadline) {
+			unattempted = len(queue) - i
+			batch.SkippedCount += unattempted
+			for _, skipped := range queue[i:] {
+				batch.Results = append(batch.Results, queueResult(skipped, toolkit.ImportResult{Status: "skipped", Message: "batch deadline reached"}))
+			}
+			remaining = append(remaining, queue[i:]...)
+			break
+		}
+		result, err := syncQueueEntry(client, entry, verbose, force)
+		if err != nil {
+			entry.Attempts++
+			entry.LastError = err.Error()
+			remaining = append(remaining, entry)
+			batch.FailureCount++
+			failed := toolkit.ImportResult{Status: "failed", Message: err.Error()}
+			if result != nil {
+				failed.Status = result.Status
+				failed.Conflicts = result.Conflicts
+			}
+			batch.Results = append(batch.Results, queueResult(entry, failed))
+			if verbose {
+				printError("%s: %v", entry.File, err)
+			}
+			continue
+		}
+		if result.Status == "skipped" {
+			batch.SkippedCount++
+		} else {
+			batch.SuccessCount++
+		}
+		batch.Results = append(batch.Results, queueResult(entry, *result))
+	}
+	batch.CompletedAt = toolkit.Now()
+
+	if err := saveQueue(remaining); err != nil {
+		return failf("Failed to save queue: %v", err)
+	}
+
+	if asJSON {
+		if err := printBatchJSON(batch, summaryOnly); err != nil {
+			return err
+		}
+		if len(remaining) > 0 {
+			return errFailed
+		}
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("Synced %d of %d queued assignment(s)\n", batch.SuccessCount, len(queue))
+	if summaryOnly {
+		printBatchFailures(batch)
+	}
+	if unattempted > 0 {
+		printWarning("Deadline reached; %d assignment(s) not attempted and still queued", unattempted)
+	}
+	if len(remaining) > 0 {
+		printHint("Failed assignments stay queued; fix them and run 'assignment-toolkit queue sync' again")
+		return errFailed
+	}
+	return nil
+}
+
+// syncQueueEntry validates and uploads one queued assignment, reporting
+// the outcome when verbose. Unless force is set, an assignment the LMS
+// already has with the same source hash is skipped.
+func syncQueueEntry(client *toolkit.LMSClient, entry queueEntry, verbose, force bool) (*toolkit.ImportResult, error) {
+	pkg, err := toolkit.LoadPackage(entry.File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load: %v", err)
+	}
+	if pkg.Metadata.Archived {
+		if verbose {
+			printMessage(iconNote, "%s is archived; skipped and removed from the queue", entry.File)
+		}
+		return &toolkit.ImportResult{Status: "skipped", Message: "archived; removed from the queue"}, nil
+	}
+
+	if validation := toolkit.ValidateAssignmentPackage(pkg); !validation.IsValid {
+		return nil, fmt.Errorf("invalid assignment (%d error(s)); run 'assignment-toolkit validate %s'", len(validation.Errors), entry.File)
+	}
+
+	if !force {
+		existing, err := client.ExistingAssignment(pkg)
+		if err != nil {
+			return nil, fmt.Errorf("could not check the LMS for an existing copy: %v", err)
+		}
+		if existing != nil {
+			if verbose {
+				printMessage(iconNote, "%s is already on the LMS as %s; skipped and removed from the queue", entry.File, existing.AssignmentID)
+			}
+			return &toolkit.ImportResult{
+				AssignmentID: existing.AssignmentID,
+				Status:       "skipped",
+				Message:      "already on the LMS with the same content; removed from the queue",
+			}, nil
+		}
+	}
+
+	if verbose {
+		warnMediaResources(&pkg)
+	} else {
+		toolkit.HydrateAudioMetadata(&pkg)
+	}
+	result, err := client.SyncAssignment(pkg)
+	if err != nil {
+		return result, err
+	}
+	if result.AssignmentID != "" {
+		recordLMSID(entry.File, result.AssignmentID)
+	}
+
+	if !verbose {
+		if err := rememberFailedResources(entry.File, result); err != nil {
+			result.Message += fmt.Sprintf(" Failed to record the resources to retry: %v", err)
+		}
+		return result, nil
+	}
+	if result.Status == "partial" {
+		printWarning("%s: %s", entry.File, result.Message)
+		recordFailedResources(entry.File, result)
+	} else {
+		printSuccess("%s synced (Assignment ID: %s)", entry.File, result.AssignmentID)
+	}
+	if len(result.Conflicts) > 0 {
+		printConflicts(result)
+	}
+	return result, nil
+}
+
+// queueResult labels a sync result with the queued file it belongs to
+func queueResult(entry queueEntry, result toolkit.ImportResult) toolkit.ImportResult {
+	metadata := map[string]string{"file": entry.File}
+	for key, value := range result.Metadata {
+		metadata[key] = value
+	}
+	result.Metadata = metadata
+	return result
+}
+
+// printBatchFailures lists the queued assignments that failed or synced
+// without all of their resources
+func printBatchFailures(batch *toolkit.BatchImportResult) {
+	fmt.Printf("   %d succeeded, %d failed, %d skipped\n", batch.SuccessCount, batch.FailureCount, batch.SkippedCount)
+	partial := false
+	for _, result := range batch.Results {
+		switch result.Status {
+		case "success", "skipped":
+		case "partial":
+			partial = true
+			printBullet("%s: synced, but %s failed to upload", result.Metadata["file"], strings.Join(result.FailedResources, ", "))
+		default:
+			printBullet("%s: %s", result.Metadata["file"], result.Message)
+		}
+	}
+	if partial {
+		printHint("Run 'assignment-toolkit sync --resources-only <file>' to retry the resources that failed")
+	}
+}
+
+// printBatchJSON prints the result of a batch sync as JSON; summaryOnly
+// leaves out the assignments that synced cleanly
+func printBatchJSON(batch *toolkit.BatchImportResult, summaryOnly bool) error {
+	if summaryOnly {
+		compact := *batch
+		compact.Results = make([]toolkit.ImportResult, 0, len(batch.Results))
+		for _, result := range batch.Results {
+			if result.Status != "success" {
+				compact.Results = append(compact.Results, result)
+			}
+		}
+		batch = &compact
+	}
+
+	data, err := json.MarshalIndent(batch, "", "  ")
+	if err != nil {
+		return failf("Failed to encode the sync result: %v", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func queueIndex(queue []queueEntry, file string) int {
+	for i, entry := range queue {
+		if entry.File == filepath.Clean(file) {
+			return i
+		}
+	}
+	return -1
+}
+
+func loadQueue() ([]queueEntry, error) {
+	data, err := ioutil.ReadFile(queueFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var queue []queueEntry
+	if err := yaml.Unmarshal(data, &queue); err != nil {
+		return nil, fmt.Errorf("%s is corrupt: %v", queueFile, err)
+	}
+	return queue, nil
+}
+
+// saveQueue writes the queue, removing the file once it is empty
+func saveQueue(queue []queueEntry) error {
+	if len(queue) == 0 {
+		if err := os.Remove(queueFile); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := yaml.Marshal(queue)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(queueFile, data, 0644)
+}
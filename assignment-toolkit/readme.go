@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"text/template"
+
+	"assignment-toolkit/pkg/toolkit"
+)
+
+// defaultReadmeTemplate renders the README.md that 'package' writes when
+// no readme_template is configured. Templates receive the
+// toolkit.AssignmentPackage, so every metadata field and resource is
+// available, and the audio function describes an audio resource's length
+// and format.
+const defaultReadmeTemplate = `# {{.Assignment.Title}}
+
+{{.Assignment.Description}}
+
+## Assignment Details
+- **Type**: {{.Assignment.Type}}
+- **Version**: {{.Metadata.Version}}
+- **Author**: {{.Metadata.Author}}
+- **Created**: {{.Metadata.Created.Format "2006-01-02"}}
+
+## Installation
+1. Import assignment.yaml into your LMS
+2. Upload resources from the resources/ directory if present
+
+## Resources
+{{range .Resources}}- {{.Title}} ({{.Type}}{{with audio .}}, {{.}}{{end}})
+{{end}}`
+
+// renderReadme renders a package README from the template file at path,
+// or from defaultReadmeTemplate when path is empty
+func renderReadme(pkg toolkit.AssignmentPackage, path string) ([]byte, error) {
+	text := defaultReadmeTemplate
+	name := "README"
+	if path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		text, name = string(data), path
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").
+		Funcs(template.FuncMap{"audio": toolkit.AudioSummary}).
+		Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, pkg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"assignment-toolkit/pkg/toolkit"
+)
+
+func TestRenderReadmeDefault(t *testing.T) {
+	pkg := toolkit.AssignmentPackage{
+		Metadata:   toolkit.PackageMetadata{Version: "1.0.0", Author: "Ms. Lee", Created: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		Assignment: toolkit.Assignment{Title: "Fractions", Type: "multiple-choice"},
+		Resources:  []toolkit.Resource{{Title: "Number line", Type: "image"}},
+	}
+
+	readme, err := renderReadme(pkg, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# Fractions", "- **Created**: 2024-03-01", "- Number line (image)\n"} {
+		if !strings.Contains(string(readme), want) {
+			t.Errorf("README is missing %q:\n%s", want, readme)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	reindexCmd.Flags().Bool("all", false, "Reindex every assignment file in the current directory")
+	reindexCmd.Flags().Bool("dry-run", false, "Show which files are stale without rewriting them")
+	rootCmd.AddCommand(reindexCmd)
+}
+
+// Reindex command
+var reindexCmd = &cobra.Command{
+	Use:   "reindex [file]",
+	Short: "Recompute source hashes after hand-editing files",
+	Long: `Recompute the source hash of assignments edited by hand and stamp the
+modified time of those whose content changed, rewriting them in place. The
+recorded type mapping (metadata.type_mapping) is refreshed when the type or
+the workspace mappings changed. Files that are already up to date are left
+untouched.`,
+	Args:              fileOrAllArgs,
+	ValidArgsFunction: completeAssignmentFile,
+	RunE:              runReindex,
+}
+
+func runReindex(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	files := args
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		var err error
+		if files, err = findWorkspaceAssignmentFiles(); err != nil {
+			return failf("Error listing files: %v", err)
+		}
+	}
+
+	stale, reindexed, failed := 0, 0, 0
+	for _, file := range files {
+		pkg, err := toolkit.LoadPackage(file)
+		if err != nil {
+			printError("%s: failed to load: %v", file, err)
+			failed++
+			continue
+		}
+
+		hash := toolkit.CalculateHash(pkg)
+		hashStale := hash != pkg.Metadata.SourceHash
+
+		// A recorded type mapping goes stale when the type is edited or the
+		// workspace mappings change
+		mapping, _ := toolkit.ResolveTypeMapping(pkg.Assignment.Type)
+		recorded := pkg.Metadata.TypeMapping
+		mappingStale := recorded != nil && mapping != nil && *recorded != *mapping
+
+		if !hashStale && !mappingStale {
+			continue
+		}
+		stale++
+
+		var what []string
+		if hashStale {
+			what = append(what, "source hash")
+		}
+		if mappingStale {
+			what = append(what, "type mapping")
+		}
+
+		if dryRun {
+			printMessage(iconSync, "%s: %s stale (would update)", file, strings.Join(what, " and "))
+			continue
+		}
+
+		pkg.Metadata.SourceHash = hash
+		if mappingStale {
+			pkg.Metadata.TypeMapping = mapping
+		}
+		pkg.Metadata.Modified = toolkit.Now()
+		if err := toolkit.SavePackage(pkg, file); err != nil {
+			printError("%s: failed to save: %v", file, err)
+			failed++
+			continue
+		}
+		printMessage(iconSync, "%s: %s updated", file, strings.Join(what, " and "))
+		reindexed++
+	}
+
+	fmt.Println()
+	if dryRun {
+		fmt.Printf("%d of %d assignment(s) need reindexing\n", stale, len(files))
+	} else {
+		fmt.Printf("Reindexed %d of %d assignment(s)\n", reindexed, len(files))
+	}
+	if failed > 0 {
+		return errFailed
+	}
+	return nil
+}
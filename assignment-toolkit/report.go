@@ -0,0 +1,134 @@
+package main
+
+import (
+	"html/template"
+	"os"
+	"time"
+
+	"assignment-toolkit/pkg/toolkit"
+)
+
+// validationReportEntry is the validation outcome for one assignment file
+type validationReportEntry struct {
+	File       string
+	Title      string
+	Type       string
+	LoadError  string
+	Validation toolkit.ValidationInfo
+
+	// AssumedType is set when Type came from --assume-type
+	AssumedType bool
+}
+
+// Status returns a short label used for the report's color coding
+func (e validationReportEntry) Status() string {
+	switch {
+	case e.LoadError != "" || !e.Validation.IsValid:
+		return "invalid"
+	case len(e.Validation.Warnings) > 0:
+		return "warning"
+	default:
+		return "valid"
+	}
+}
+
+// validateFile loads and validates one assignment for a report, recording
+// a load failure instead of returning it. A file with no type is validated
+// as assumed when that is set.
+func validateFile(file, assumed string) validationReportEntry {
+	entry := validationReportEntry{File: file}
+
+	pkg, err := toolkit.LoadPackage(file)
+	if err != nil {
+		entry.LoadError = err.Error()
+	} else {
+		entry.AssumedType = assumeType(&pkg, assumed)
+		entry.Title = pkg.Assignment.Title
+		entry.Type = pkg.Assignment.Type
+		entry.Validation = toolkit.ValidateAssignmentPackage(pkg)
+		if urlChecker != nil {
+			urlChecker.CheckPackage(pkg, &entry.Validation)
+		}
+		if checkResources {
+			toolkit.CheckResourceFiles(pkg, &entry.Validation)
+		}
+	}
+	return entry
+}
+
+var validationReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Assignment Validation Report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ccc; padding: 0.5em; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; }
+tr.valid td.status { background: #d4edda; }
+tr.warning td.status { background: #fff3cd; }
+tr.invalid td.status { background: #f8d7da; }
+ul { margin: 0; padding-left: 1.2em; }
+.summary span { margin-right: 1.5em; }
+</style>
+</head>
+<body>
+<h1>Assignment Validation Report</h1>
+<p>Generated {{.Generated.Format "2006-01-02 15:04"}}</p>
+<p class="summary">
+<span>Total: {{.Total}}</span>
+<span>Valid: {{.Valid}}</span>
+<span>With warnings: {{.Warnings}}</span>
+<span>Invalid: {{.Invalid}}</span>
+</p>
+<table>
+<tr><th>Status</th><th>File</th><th>Title</th><th>Type</th><th>Score</th><th>Errors</th><th>Warnings</th></tr>
+{{range .Entries}}<tr class="{{.Status}}">
+<td class="status">{{.Status}}</td>
+<td>{{.File}}</td>
+<td>{{.Title}}</td>
+<td>{{.Type}}</td>
+<td>{{if .LoadError}}-{{else}}{{.Validation.Score}}/100{{end}}</td>
+<td>{{if .LoadError}}<ul><li>Failed to load: {{.LoadError}}</li></ul>{{else if .Validation.Errors}}<ul>{{range .Validation.Errors}}<li>{{.}}</li>{{end}}</ul>{{end}}</td>
+<td>{{if .Validation.Warnings}}<ul>{{range .Validation.Warnings}}<li>{{.}}</li>{{end}}</ul>{{end}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// writeValidationReport renders the entries as a standalone HTML page
+func writeValidationReport(path string, entries []validationReportEntry) error {
+	data := struct {
+		Generated time.Time
+		Entries   []validationReportEntry
+		Total     int
+		Valid     int
+		Warnings  int
+		Invalid   int
+	}{
+		Generated: toolkit.Now(),
+		Entries:   entries,
+		Total:     len(entries),
+	}
+
+	for _, entry := range entries {
+		switch entry.Status() {
+		case "valid":
+			data.Valid++
+		case "warning":
+			data.Warnings++
+		default:
+			data.Invalid++
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return validationReportTemplate.Execute(file, data)
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// pendingResourcesFile records resources that failed to upload during a
+// sync, so 'sync --resources-only' can retry them without creating the
+// assignment again
+const pendingResourcesFile = ".assignment-pending-resources.yaml"
+
+// pendingResources is an assignment whose resources didn't all upload
+type pendingResources struct {
+	File         string    `yaml:"file"`
+	AssignmentID string    `yaml:"assignment_id"`
+	Resources    []string  `yaml:"resources"`
+	FailedAt     time.Time `yaml:"failed_at"`
+	LastError    string    `yaml:"last_error,omitempty"`
+}
+
+// recordFailedResources remembers the resources a partial sync of file
+// failed to upload and tells the user how to retry them
+func recordFailedResources(file string, result *toolkit.ImportResult) {
+	if len(result.FailedResources) == 0 || result.AssignmentID == "" {
+		return
+	}
+	if err := rememberFailedResources(file, result); err != nil {
+		printWarning("Failed to record the resources to retry: %v", err)
+		return
+	}
+	printHint("Run 'assignment-toolkit sync --resources-only %s' to retry %d failed resource(s) without re-creating the assignment", file, len(result.FailedResources))
+}
+
+// rememberFailedResources records the failed resources of a partial sync
+// of file, replacing any earlier record for it
+func rememberFailedResources(file string, result *toolkit.ImportResult) error {
+	if len(result.FailedResources) == 0 || result.AssignmentID == "" {
+		return nil
+	}
+
+	pending, err := loadPendingResources()
+	if err != nil {
+		return err
+	}
+	entry := pendingResources{
+		File:         filepath.Clean(file),
+		AssignmentID: result.AssignmentID,
+		Resources:    result.FailedResources,
+		FailedAt:     toolkit.Now(),
+		LastError:    result.Message,
+	}
+	if i := pendingIndex(pending, file); i >= 0 {
+		pending[i] = entry
+	} else {
+		pending = append(pending, entry)
+	}
+	return savePendingResources(pending)
+}
+
+// runResourcesOnlySync retries the resources that failed in the last sync
+// of a file, package directory, or package .zip, uploading them to the
+// assignment that sync created
+func runResourcesOnlySync(cmd *cobra.Command, config toolkit.Config, args []string) error {
+	if len(args) != 1 {
+		return usageErrorf("--resources-only needs the assignment file or package that was synced")
+	}
+	file := args[0]
+
+	pending, err := loadPendingResources()
+	if err != nil {
+		return failf("Failed to read %s: %v", pendingResourcesFile, err)
+	}
+	i := pendingIndex(pending, file)
+	if i < 0 {
+		printSuccess("No failed resource uploads are recorded for %s", file)
+		return nil
+	}
+	entry := pending[i]
+
+	var pkg toolkit.AssignmentPackage
+	if isPackageDirOrZip(file) {
+		var cleanup func()
+		pkg, cleanup, err = loadPackageDirOrZip(file)
+		defer cleanup()
+	} else {
+		pkg, err = toolkit.LoadPackage(file)
+	}
+	if err != nil {
+		return failf("Failed to load assignment: %v", err)
+	}
+
+	if err := confirmSync(cmd, config); err != nil {
+		return err
+	}
+	client, err := newLMSClientFromConfig(config)
+	if err != nil {
+		return failf("Invalid sync configuration: %v", err)
+	}
+	client.OnlyChangedResources, _ = cmd.Flags().GetBool("only-changed-resources")
+	client.ResourceTimeout, _ = cmd.Flags().GetDuration("timeout-per-resource")
+
+	printMessage(iconSync, "Retrying %d resource(s) for assignment %s: %s", len(entry.Resources), entry.AssignmentID, strings.Join(entry.Resources, ", "))
+	result, err := client.UploadResources(pkg, entry.AssignmentID, entry.Resources)
+	if err != nil {
+		return failf("Not retrying: %v", err)
+	}
+
+	if result.Status == "partial" {
+		entry.Resources = result.FailedResources
+		entry.FailedAt = toolkit.Now()
+		entry.LastError = result.Message
+		pending[i] = entry
+	} else {
+		pending = append(pending[:i], pending[i+1:]...)
+	}
+	if err := savePendingResources(pending); err != nil {
+		return failf("Failed to save %s: %v", pendingResourcesFile, err)
+	}
+
+	if result.Status == "partial" {
+		printWarning("%s", result.Message)
+		printHint("%d resource(s) still failed; run the same command again to retry them", len(result.FailedResources))
+		return errNetwork
+	}
+	printSuccess("Uploaded %d resource(s) to assignment %s", len(result.ResourceIDs), entry.AssignmentID)
+	if result.Message != "" {
+		printWarning("%s", result.Message)
+	}
+	return nil
+}
+
+func pendingIndex(pending []pendingResources, file string) int {
+	for i, entry := range pending {
+		if entry.File == filepath.Clean(file) {
+			return i
+		}
+	}
+	return -1
+}
+
+func loadPendingResources() ([]pendingResources, error) {
+	data, err := ioutil.ReadFile(pendingResourcesFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []pendingResources
+	if err := yaml.Unmarshal(data, &pending); err != nil {
+		return nil, fmt.Errorf("%s is corrupt: %v", pendingResourcesFile, err)
+	}
+	return pending, nil
+}
+
+// savePendingResources writes the pending uploads, removing the file once
+// nothing is left to retry
+func savePendingResources(pending []pendingResources) error {
+	if len(pending) == 0 {
+		if err := os.Remove(pendingResourcesFile); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := yaml.Marshal(pending)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(pendingResourcesFile, data, 0644)
+}
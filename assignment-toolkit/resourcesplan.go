@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	resourcesPlanCmd.Flags().String("resource-dir", "", "Resolve relative resource paths from this directory instead of the current one")
+	resourcesPlanCmd.MarkFlagDirname("resource-dir")
+
+	resourcesCmd.AddCommand(resourcesPlanCmd)
+	rootCmd.AddCommand(resourcesCmd)
+}
+
+// Resources command
+var resourcesCmd = &cobra.Command{
+	Use:   "resources",
+	Short: "Inspect the resources of an assignment",
+}
+
+var resourcesPlanCmd = &cobra.Command{
+	Use:   "plan [file]",
+	Short: "List the files package and sync would copy or upload, without doing it",
+	Long: `List each resource and question image or audio file of an assignment with
+its resolved path, whether it exists, and its size. Resources that only
+have a URL are shown with the URL the LMS will receive (after url_rewrites).
+Nothing is copied or uploaded; the command fails if a local file is missing.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAssignmentFile,
+	RunE:              runResourcesPlan,
+}
+
+func runResourcesPlan(cmd *cobra.Command, args []string) error {
+	filename := args[0]
+	resourceDir, _ := cmd.Flags().GetString("resource-dir")
+
+	pkg, err := toolkit.LoadPackage(filename)
+	if err != nil {
+		return failf("Failed to load assignment: %v", err)
+	}
+
+	plans := toolkit.PlanResources(pkg, resourceDir)
+	if len(plans) == 0 {
+		fmt.Printf("%s has no resources.\n", filename)
+		return nil
+	}
+
+	printMessage(iconInfo, "Resources of %s", filename)
+	fmt.Println()
+	fmt.Printf("%-25s %-10s %-7s %-10s %s\n", "TITLE", "KIND", "SOURCE", "SIZE", "LOCATION")
+	fmt.Println(strings.Repeat("-", 75))
+
+	var missing int
+	var local int
+	var uploadSize int64
+	for _, plan := range plans {
+		source, size, location := "url", "-", plan.URL
+		if plan.Local() {
+			source, location = "local", plan.Path
+			local++
+			if plan.Exists {
+				size = formatSize(plan.Size)
+				uploadSize += plan.Size
+			} else {
+				size = "MISSING"
+				location += " (" + plan.Error + ")"
+				missing++
+			}
+		}
+		title := plan.Title
+		if len(title) > 22 {
+			title = title[:22] + "..."
+		}
+		fmt.Printf("%-25s %-10s %-7s %-10s %s\n", title, plan.Kind, source, size, location)
+	}
+
+	fmt.Println()
+	fmt.Printf("%d resource(s): %d local file(s), %s to copy and upload, %d link(s)\n",
+		len(plans), local, formatSize(uploadSize), len(plans)-local)
+	if missing > 0 {
+		printError("%d local file(s) missing", missing)
+		if resourceDir == "" {
+			printHint("Paths are relative to the current directory; use --resource-dir if they live elsewhere")
+		}
+		return errFailed
+	}
+	printSuccess("All local files found")
+	return nil
+}
+
+// formatSize shows a byte count in B, KB, or MB
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
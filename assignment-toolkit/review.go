@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// reviewEntries shows the entries collected by a wizard and lets the user
+// move, edit, or remove them before they are saved. Each entry is a list
+// of field values named by fields, e.g. {"Left", "Right"} for a matching
+// pair; editing re-prompts every field with its current value as default.
+func reviewEntries(fields []string, entries [][]string) [][]string {
+	for {
+		if len(entries) == 0 {
+			return entries
+		}
+
+		fmt.Println()
+		for i, entry := range entries {
+			fmt.Printf("  %d. %s\n", i+1, strings.Join(entry, " "+icon(iconArrow)+" "))
+		}
+
+		action := promptString("Done, (m)ove, (e)dit or (r)emove an entry?", "done")
+		switch strings.ToLower(action)[0] {
+		case 'm':
+			from, ok := promptEntryNumber("Move entry number:", len(entries))
+			if !ok {
+				continue
+			}
+			to, ok := promptEntryNumber("To position:", len(entries))
+			if !ok {
+				continue
+			}
+			entry := entries[from]
+			entries = append(entries[:from], entries[from+1:]...)
+			entries = append(entries[:to], append([][]string{entry}, entries[to:]...)...)
+		case 'e':
+			index, ok := promptEntryNumber("Edit entry number:", len(entries))
+			if !ok {
+				continue
+			}
+			for i, field := range fields {
+				entries[index][i] = promptString(field+":", entries[index][i])
+			}
+		case 'r':
+			index, ok := promptEntryNumber("Remove entry number:", len(entries))
+			if !ok {
+				continue
+			}
+			entries = append(entries[:index], entries[index+1:]...)
+		case 'd':
+			return entries
+		default:
+			printWarning("Unknown choice %q", action)
+		}
+	}
+}
+
+// promptEntryNumber reads a 1-based entry number and returns its index
+func promptEntryNumber(prompt string, count int) (int, bool) {
+	number, err := strconv.Atoi(promptString(prompt, ""))
+	if err != nil || number < 1 || number > count {
+		printWarning("Enter a number from 1 to %d", count)
+		return 0, false
+	}
+	return number - 1, true
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReviewEntries(t *testing.T) {
+	previous := stdinReader
+	t.Cleanup(func() { stdinReader = previous })
+
+	// Move 3 to the top, edit the new second entry, remove the last, finish
+	stdinReader = bufio.NewReader(strings.NewReader("m\n3\n1\ne\n2\nSpain\n\nr\n3\n\n"))
+
+	entries := [][]string{{"France", "Paris"}, {"Germany", "Berlin"}, {"Italy", "Rome"}}
+	got := reviewEntries([]string{"Left item", "Right item"}, entries)
+
+	want := [][]string{{"Italy", "Rome"}, {"Spain", "Paris"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("entries = %v, want %v", got, want)
+	}
+}
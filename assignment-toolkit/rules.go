@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+// validateArgs requires a file unless --all or --list-rules is given
+func validateArgs(cmd *cobra.Command, args []string) error {
+	if listRules, _ := cmd.Flags().GetBool("list-rules"); listRules {
+		return cobra.NoArgs(cmd, args)
+	}
+	return fileOrAllArgs(cmd, args)
+}
+
+// runListRules prints the validation rule catalog
+func runListRules() error {
+	printMessage(iconInfo, "Validation Rules")
+	fmt.Println("=" + strings.Repeat("=", 50))
+	fmt.Println("Besides these, every assignment needs a title, a type, and (for")
+	fmt.Println("multiple-choice and matching) questions.")
+	fmt.Println()
+
+	for _, rule := range toolkit.ValidationRules() {
+		types := "all types"
+		if len(rule.Types) > 0 {
+			types = strings.Join(rule.Types, ", ")
+		}
+		status := ""
+		if toolkit.RuleDisabled(rule.ID) {
+			status = " (disabled)"
+		}
+		fmt.Printf("%s%s\n", rule.ID, status)
+		fmt.Printf("   %s, -%d per finding, %s\n", rule.Severity, rule.Penalty, types)
+		fmt.Printf("   %s\n", rule.Description)
+	}
+
+	fmt.Println()
+	printHint("Turn rules off with 'validate --disable <id>' or disabled_rules in the config")
+	return nil
+}
+
+// disableRules turns off the validation rules named by --disable for this
+// run, on top of disabled_rules in the config
+func disableRules(cmd *cobra.Command) error {
+	ids, _ := cmd.Flags().GetStringSlice("disable")
+	if len(ids) == 0 {
+		return nil
+	}
+
+	known := make(map[string]bool)
+	for _, rule := range toolkit.ValidationRules() {
+		known[rule.ID] = true
+	}
+	for _, id := range ids {
+		if !known[id] {
+			return usageErrorf("Unknown validation rule %q; run 'assignment-toolkit validate --list-rules' to see them", id)
+		}
+	}
+
+	config := getConfig()
+	config.DisabledRules = append(config.DisabledRules, ids...)
+	toolkit.UseConfig(config)
+	return nil
+}
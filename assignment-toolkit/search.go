@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	searchCmd.Flags().BoolP("recursive", "r", false, "Search subdirectories too")
+	searchCmd.Flags().String("type", "", "Only search assignments of this type")
+	searchCmd.Flags().String("tag", "", "Only search assignments with this tag")
+	searchCmd.Flags().Bool("regex", false, "Treat the query as a regular expression")
+	searchCmd.RegisterFlagCompletionFunc("type", completeTypes)
+	rootCmd.AddCommand(searchCmd)
+}
+
+// Search command
+var searchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search assignment content",
+	Long: `Search assignment titles, descriptions, instructions, and question text.
+Matching is case-insensitive.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSearch,
+}
+
+// searchMatch is a single field that matched the query
+type searchMatch struct {
+	Field   string
+	Snippet string
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	recursive, _ := cmd.Flags().GetBool("recursive")
+	typeFilter, _ := cmd.Flags().GetString("type")
+	tagFilter, _ := cmd.Flags().GetString("tag")
+	useRegex, _ := cmd.Flags().GetBool("regex")
+
+	pattern := regexp.QuoteMeta(args[0])
+	if useRegex {
+		pattern = args[0]
+	}
+	matcher, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return failf("Invalid regular expression: %v", err)
+	}
+
+	var files []string
+	if recursive {
+		files, err = findAssignmentFilesRecursive(".")
+	} else {
+		files, err = findWorkspaceAssignmentFiles()
+	}
+	if err != nil {
+		return failf("Error listing files: %v", err)
+	}
+
+	found := 0
+	for _, file := range files {
+		pkg, err := toolkit.LoadPackage(file)
+		if err != nil {
+			continue
+		}
+		if typeFilter != "" && !sameAssignmentType(pkg.Assignment.Type, typeFilter) {
+			continue
+		}
+		if tagFilter != "" && !hasTag(pkg, tagFilter) {
+			continue
+		}
+
+		matches := searchPackage(pkg, matcher)
+		if len(matches) == 0 {
+			continue
+		}
+
+		found++
+		printMessage(iconNote, "%s (%s)", file, pkg.Assignment.Title)
+		for _, match := range matches {
+			printBullet("%s: %s", match.Field, match.Snippet)
+		}
+	}
+
+	if found == 0 {
+		fmt.Printf("No assignments match %q\n", args[0])
+		return nil
+	}
+	fmt.Println()
+	fmt.Printf("Found %d matching assignment(s)\n", found)
+	return nil
+}
+
+// searchPackage returns every searchable field that matches
+func searchPackage(pkg toolkit.AssignmentPackage, matcher *regexp.Regexp) []searchMatch {
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"title", pkg.Assignment.Title},
+		{"description", pkg.Assignment.Description},
+		{"instructions", pkg.Assignment.Instructions},
+	}
+
+	var matches []searchMatch
+	for _, field := range fields {
+		if snippet, ok := matchSnippet(field.value, matcher); ok {
+			matches = append(matches, searchMatch{field.name, snippet})
+		}
+	}
+
+	for _, text := range collectStrings(pkg.Assignment.Questions) {
+		if snippet, ok := matchSnippet(text, matcher); ok {
+			matches = append(matches, searchMatch{"questions", snippet})
+		}
+	}
+
+	return matches
+}
+
+// matchSnippet returns the matched text with a little surrounding context
+func matchSnippet(text string, matcher *regexp.Regexp) (string, bool) {
+	loc := matcher.FindStringIndex(text)
+	if loc == nil {
+		return "", false
+	}
+
+	const context = 30
+	start, end := loc[0]-context, loc[1]+context
+	prefix, suffix := "...", "..."
+	if start <= 0 {
+		start, prefix = 0, ""
+	}
+	if end >= len(text) {
+		end, suffix = len(text), ""
+	}
+
+	// Avoid cutting through a multi-byte character
+	for start > 0 && !isRuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !isRuneStart(text[end]) {
+		end++
+	}
+
+	snippet := strings.Join(strings.Fields(text[start:end]), " ")
+	return prefix + snippet + suffix, true
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// collectStrings gathers all string values from a free-form question value
+func collectStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		var result []string
+		for _, item := range v {
+			result = append(result, collectStrings(item)...)
+		}
+		return result
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var result []string
+		for _, key := range keys {
+			result = append(result, collectStrings(v[key])...)
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// sameAssignmentType compares types after resolving aliases
+func sameAssignmentType(a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	typeManager := toolkit.GetTypeManager()
+	mappingA, errA := typeManager.ResolveType(a)
+	mappingB, errB := typeManager.ResolveType(b)
+	return errA == nil && errB == nil && mappingA.PortableType == mappingB.PortableType
+}
+
+// hasTag reports whether the assignment or its package carries the tag
+func hasTag(pkg toolkit.AssignmentPackage, tag string) bool {
+	for _, tags := range [][]string{pkg.Assignment.Tags, pkg.Metadata.Tags} {
+		for _, t := range tags {
+			if strings.EqualFold(t, tag) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultUpdateURL is the release feed checked unless updateURLEnv is set
+const defaultUpdateURL = "https://api.github.com/repos/PeterNoelEvans/LMS-assignment-toolkit/releases/latest"
+
+// updateURLEnv overrides the release feed, e.g. for a school mirror. It is
+// read from the environment only: a workspace config can come from a shared
+// file, and must not be able to point self-update somewhere else.
+const updateURLEnv = "ASSIGNMENT_TOOLKIT_UPDATE_URL"
+
+// releaseChecksums is the release asset listing the SHA-256 of every
+// binary, in sha256sum format
+const releaseChecksums = "checksums.txt"
+
+// releaseSignature is the release asset holding the base64 Ed25519
+// signature of releaseChecksums
+const releaseSignature = releaseChecksums + ".sig"
+
+// updatePublicKey is the base64 Ed25519 key release checksums are signed
+// with, set at build time with
+// -ldflags "-X main.updatePublicKey=..."
+// Builds without it refuse to self-update.
+var updatePublicKey = ""
+
+func init() {
+	selfUpdateCmd.Flags().Bool("check-only", false, "Only report whether a newer version is available")
+	selfUpdateCmd.Flags().Bool("force", false, "Update even when running a development build")
+	rootCmd.AddCommand(selfUpdateCmd)
+}
+
+// Self-update command
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update assignment-toolkit to the latest release",
+	Long: `Check the release feed (GitHub releases, or $ASSIGNMENT_TOOLKIT_UPDATE_URL)
+for a newer version, download the binary for this platform, verify it
+against the release's checksums.txt and that file's signature
+(checksums.txt.sig, checked with the key built into this binary), and
+replace the running executable.
+
+The update_url setting in .assignment-config.yaml is ignored.`,
+	Args: cobra.NoArgs,
+	RunE: runSelfUpdate,
+}
+
+// release is the part of a GitHub release the updater reads
+type release struct {
+	TagName string         `json:"tag_name"`
+	Assets  []releaseAsset `json:"assets"`
+}
+
+type releaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+func runSelfUpdate(cmd *cobra.Command, args []string) error {
+	checkOnly, _ := cmd.Flags().GetBool("check-only")
+	force, _ := cmd.Flags().GetBool("force")
+
+	updateURL := os.Getenv(updateURLEnv)
+	if updateURL == "" {
+		updateURL = defaultUpdateURL
+	}
+	if getConfig().UpdateURL != "" {
+		printWarning("Ignoring update_url in .assignment-config.yaml; set %s to use another release feed", updateURLEnv)
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	latest, err := fetchLatestRelease(client, updateURL)
+	if err != nil {
+		return networkErrorf("Failed to check for updates: %v", err)
+	}
+
+	if version == "dev" {
+		printMessage(iconInfo, "Running a development build; latest release is %s", latest.TagName)
+		if !force {
+			printHint("Pass --force to replace this build with the release")
+			return nil
+		}
+	} else if !isNewerVersion(latest.TagName, version) {
+		printSuccess("assignment-toolkit %s is up to date", version)
+		return nil
+	} else {
+		printMessage(iconInfo, "Version %s is available (you have %s)", latest.TagName, version)
+	}
+	if checkOnly {
+		printHint("Run 'assignment-toolkit self-update' to install it")
+		return nil
+	}
+
+	key, err := parseUpdatePublicKey(updatePublicKey)
+	if err != nil {
+		return failf("Can't verify updates: %v", err)
+	}
+
+	name := releaseAssetName(runtime.GOOS, runtime.GOARCH)
+	binary, checksums, signature := latest.asset(name), latest.asset(releaseChecksums), latest.asset(releaseSignature)
+	if binary == nil {
+		return failf("Release %s has no binary for %s/%s (expected %s)", latest.TagName, runtime.GOOS, runtime.GOARCH, name)
+	}
+	if checksums == nil {
+		return failf("Release %s has no %s; refusing to install an unverified binary", latest.TagName, releaseChecksums)
+	}
+	if signature == nil {
+		return failf("Release %s has no %s; refusing to install an unverified binary", latest.TagName, releaseSignature)
+	}
+
+	printMessage(iconSync, "Downloading %s...", name)
+	data, err := downloadVerified(client, key, binary, checksums, signature)
+	if err != nil {
+		return failf("Update failed: %v", err)
+	}
+
+	if err := replaceExecutable(data); err != nil {
+		return failf("Failed to install the update: %v", err)
+	}
+	printSuccess("Updated to %s", latest.TagName)
+	return nil
+}
+
+func fetchLatestRelease(client *http.Client, url string) (*release, error) {
+	data, err := download(client, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest release
+	if err := json.Unmarshal(data, &latest); err != nil {
+		return nil, fmt.Errorf("unexpected response from %s: %v", url, err)
+	}
+	if latest.TagName == "" {
+		return nil, fmt.Errorf("no release found at %s", url)
+	}
+	return &latest, nil
+}
+
+func (r *release) asset(name string) *releaseAsset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// releaseAssetName is the file name a release uses for a platform's binary
+func releaseAssetName(goos, goarch string) string {
+	name := fmt.Sprintf("assignment-toolkit-%s-%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// isNewerVersion compares dotted versions such as v1.4.0, ignoring any
+// leading "v" and pre-release suffix
+func isNewerVersion(latest, current string) bool {
+	parse := func(v string) []int {
+		v = strings.TrimPrefix(v, "v")
+		if i := strings.IndexAny(v, "-+"); i >= 0 {
+			v = v[:i]
+		}
+		var parts []int
+		for _, part := range strings.Split(v, ".") {
+			n, _ := strconv.Atoi(part)
+			parts = append(parts, n)
+		}
+		return parts
+	}
+
+	l, c := parse(latest), parse(current)
+	for i := 0; i < len(l) || i < len(c); i++ {
+		var a, b int
+		if i < len(l) {
+			a = l[i]
+		}
+		if i < len(c) {
+			b = c[i]
+		}
+		if a != b {
+			return a > b
+		}
+	}
+	return false
+}
+
+// parseUpdatePublicKey decodes the key embedded with updatePublicKey
+func parseUpdatePublicKey(encoded string) (ed25519.PublicKey, error) {
+	if encoded == "" {
+		return nil, fmt.Errorf("this build has no release signing key; download the release manually")
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("this build's release signing key is not a base64 Ed25519 public key")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// downloadVerified checks the checksums file against its signature, then
+// downloads binary and checks it against its entry in the checksums file
+func downloadVerified(client *http.Client, key ed25519.PublicKey, binary, checksums, signature *releaseAsset) ([]byte, error) {
+	sums, err := download(client, checksums.URL)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := download(client, signature.URL)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil || !ed25519.Verify(key, sums, sig) {
+		return nil, fmt.Errorf("bad signature: %s doesn't match %s", signature.Name, checksums.Name)
+	}
+
+	expected := ""
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == binary.Name {
+			expected = fields[0]
+		}
+	}
+	if expected == "" {
+		return nil, fmt.Errorf("%s has no entry for %s", checksums.Name, binary.Name)
+	}
+
+	data, err := download(client, binary.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, expected) {
+		return nil, fmt.Errorf("checksum mismatch for %s (expected %s, got %s)", binary.Name, expected, actual)
+	}
+	return data, nil
+}
+
+func download(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// replaceExecutable swaps the running binary for data. The old binary is
+// moved aside first because Windows can't overwrite a running executable.
+func replaceExecutable(data []byte) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if executable, err = filepath.EvalSymlinks(executable); err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(executable)
+	tmp, err := ioutil.TempFile(dir, ".assignment-toolkit-update-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return err
+	}
+
+	old := executable + ".old"
+	os.Remove(old)
+	if err := os.Rename(executable, old); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), executable); err != nil {
+		os.Rename(old, executable)
+		return err
+	}
+	os.Remove(old) // fails harmlessly on Windows while the old binary runs
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIsNewerVersion(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"v1.2.0", "v1.1.9", true},
+		{"v1.10.0", "v1.9.0", true},
+		{"v1.2", "1.2.0", false},
+		{"v1.2.0", "v1.2.0-rc1", false},
+		{"v1.1.0", "v1.2.0", false},
+	}
+	for _, tt := range tests {
+		if got := isNewerVersion(tt.latest, tt.current); got != tt.want {
+			t.Errorf("isNewerVersion(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestDownloadVerifiedChecksChecksum(t *testing.T) {
+	binary := []byte("new binary")
+	sum := sha256.Sum256(binary)
+	name := releaseAssetName("linux", "amd64")
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checksums := fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), name)
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(private, []byte(checksums)))
+	forged := fmt.Sprintf("%s  %s\n", strings.Repeat("0", 64), name)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/checksums.txt":
+			fmt.Fprint(w, checksums)
+		case "/checksums.txt.sig":
+			fmt.Fprintln(w, signature)
+		case "/forged.txt":
+			fmt.Fprint(w, forged)
+		case "/bin":
+			w.Write(binary)
+		case "/tampered":
+			w.Write([]byte("tampered binary"))
+		}
+	}))
+	defer server.Close()
+
+	sums := &releaseAsset{Name: releaseChecksums, URL: server.URL + "/checksums.txt"}
+	sig := &releaseAsset{Name: releaseSignature, URL: server.URL + "/checksums.txt.sig"}
+
+	data, err := downloadVerified(server.Client(), public, &releaseAsset{Name: name, URL: server.URL + "/bin"}, sums, sig)
+	if err != nil || string(data) != string(binary) {
+		t.Fatalf("download = %q, %v; want the binary", data, err)
+	}
+
+	_, err = downloadVerified(server.Client(), public, &releaseAsset{Name: name, URL: server.URL + "/tampered"}, sums, sig)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("err = %v, want a checksum mismatch", err)
+	}
+
+	forgedSums := &releaseAsset{Name: releaseChecksums, URL: server.URL + "/forged.txt"}
+	_, err = downloadVerified(server.Client(), public, &releaseAsset{Name: name, URL: server.URL + "/bin"}, forgedSums, sig)
+	if err == nil || !strings.Contains(err.Error(), "bad signature") {
+		t.Errorf("err = %v, want a bad signature", err)
+	}
+
+	other, _, _ := ed25519.GenerateKey(rand.Reader)
+	_, err = downloadVerified(server.Client(), other, &releaseAsset{Name: name, URL: server.URL + "/bin"}, sums, sig)
+	if err == nil || !strings.Contains(err.Error(), "bad signature") {
+		t.Errorf("err = %v, want a bad signature for another key", err)
+	}
+}
+
+func TestParseUpdatePublicKey(t *testing.T) {
+	public, _, _ := ed25519.GenerateKey(rand.Reader)
+	if _, err := parseUpdatePublicKey(base64.StdEncoding.EncodeToString(public)); err != nil {
+		t.Errorf("valid key: %v", err)
+	}
+	for _, encoded := range []string{"", "not base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		if _, err := parseUpdatePublicKey(encoded); err == nil {
+			t.Errorf("parseUpdatePublicKey(%q) succeeded, want an error", encoded)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	snapshotCmd.Flags().String("label", "", "Add a label to the snapshot name, e.g. before-reindex")
+	restoreCmd.Flags().Bool("no-backup", false, "Don't snapshot the current files before restoring")
+	snapshotCmd.AddCommand(snapshotListCmd)
+	rootCmd.AddCommand(snapshotCmd)
+	rootCmd.AddCommand(restoreCmd)
+}
+
+// Snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Archive the workspace's assignments and config before a risky change",
+	Long: `Save every assignment file in the workspace (including output_dir) and
+.assignment-config.yaml to a timestamped zip in .snapshots/. Take one
+before bulk commands such as reindex or normalize-types, and roll back with
+'restore' if the result isn't what you wanted.`,
+	Args: cobra.NoArgs,
+	RunE: runSnapshot,
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List workspace snapshots, newest first",
+	Args:  cobra.NoArgs,
+	RunE:  runSnapshotList,
+}
+
+// Restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore [snapshot]",
+	Short: "Roll the workspace back to a snapshot",
+	Long: `Write every file in a snapshot back to its place, replacing the current
+copies. The snapshot can be named as shown by 'snapshot list' or given as a
+path. Files created since the snapshot are kept. The current files are
+snapshotted first (labelled before-restore) unless --no-backup is given, so
+a restore can itself be undone.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSnapshot,
+	RunE:              runRestore,
+}
+
+func runSnapshot(cmd *cobra.Command, args []string) error {
+	label, _ := cmd.Flags().GetString("label")
+	path, count, err := takeSnapshot(label)
+	if err != nil {
+		return failf("Failed to take snapshot: %v", err)
+	}
+	printSuccess("Saved %d file(s) to %s", count, path)
+	printHint("Run 'assignment-toolkit restore %s' to roll back to it", snapshotName(path))
+	return nil
+}
+
+// takeSnapshot archives the workspace's assignment files and config
+func takeSnapshot(label string) (string, int, error) {
+	files, err := findWorkspaceAssignmentFiles()
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err := os.Stat(".assignment-config.yaml"); err == nil {
+		files = append(files, ".assignment-config.yaml")
+	}
+	if len(files) == 0 {
+		return "", 0, fmt.Errorf("no assignment files or config to save")
+	}
+
+	path := toolkit.SnapshotPath(toolkit.SnapshotDir, label)
+	if err := toolkit.WriteSnapshot(path, files); err != nil {
+		return "", 0, err
+	}
+	return path, len(files), nil
+}
+
+func runSnapshotList(cmd *cobra.Command, args []string) error {
+	snapshots, err := toolkit.ListSnapshots(toolkit.SnapshotDir)
+	if err != nil {
+		return failf("Failed to read %s: %v", toolkit.SnapshotDir, err)
+	}
+	if len(snapshots) == 0 {
+		fmt.Println("No snapshots yet. Run 'assignment-toolkit snapshot' to take one.")
+		return nil
+	}
+
+	fmt.Printf("%-45s %-17s %6s %10s\n", "SNAPSHOT", "TAKEN", "FILES", "SIZE")
+	fmt.Println("-------------------------------------------------------------------------------------")
+	for _, snapshot := range snapshots {
+		fmt.Printf("%-45s %-17s %6d %10s\n", snapshot.Name, snapshot.Created.Format("2006-01-02 15:04"), snapshot.Files, formatSize(snapshot.Size))
+	}
+	return nil
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	path, ok := resolveSnapshot(args[0])
+	if !ok {
+		printError("No snapshot named %s", args[0])
+		printHint("Run 'assignment-toolkit snapshot list' to see them")
+		return errFailed
+	}
+
+	if noBackup, _ := cmd.Flags().GetBool("no-backup"); !noBackup {
+		backup, _, err := takeSnapshot("before-restore")
+		if err != nil {
+			printError("Failed to snapshot the current files; nothing was restored: %v", err)
+			printHint("Pass --no-backup to restore without one")
+			return errFailed
+		}
+		printMessage(iconNote, "Current files saved to %s", backup)
+	}
+
+	restored, err := toolkit.RestoreSnapshot(path, ".")
+	if err != nil {
+		return failf("Failed to restore %s after %d file(s): %v", path, len(restored), err)
+	}
+	printSuccess("Restored %d file(s) from %s", len(restored), snapshotName(path))
+	return nil
+}
+
+// resolveSnapshot finds a snapshot given by path or by its name in the
+// snapshot directory, with or without .zip
+func resolveSnapshot(name string) (string, bool) {
+	candidates := []string{name}
+	if !strings.ContainsAny(name, `/\`) {
+		candidates = append(candidates, filepath.Join(toolkit.SnapshotDir, name), filepath.Join(toolkit.SnapshotDir, name+".zip"))
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// snapshotName is how a snapshot is shown and passed to restore
+func snapshotName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), ".zip")
+}
+
+// completeSnapshot suggests the names of the workspace's snapshots
+func completeSnapshot(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	snapshots, _ := toolkit.ListSnapshots(toolkit.SnapshotDir)
+	var names []string
+	for _, snapshot := range snapshots {
+		names = append(names, snapshot.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	statsCmd.Flags().BoolP("recursive", "r", false, "Count assignments in subdirectories too")
+	statsCmd.Flags().Bool("remote", false, "Compare local counts with the assignments in the LMS")
+	rootCmd.AddCommand(statsCmd)
+}
+
+// Stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats [file]",
+	Short: "Count assignments by type, or the questions of one assignment",
+	Long: `Count the assignments in the current directory by type.
+
+With --remote, the counts are compared with the LMS side by side, grouped by
+LMS type, and any type whose counts differ is flagged.
+
+Given a file, report its questions instead: how many there are, the total
+points and how they are spread across questions, the average number of
+options per multiple-choice question, and the pairs of matching questions.
+Questions without their own points value get an even share of the rest.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeAssignmentFile,
+	RunE:              runStats,
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	recursive, _ := cmd.Flags().GetBool("recursive")
+	remote, _ := cmd.Flags().GetBool("remote")
+	if len(args) == 1 {
+		if recursive || remote {
+			return usageErrorf("--recursive and --remote count a directory and cannot be used with a file")
+		}
+		return runFileStats(args[0])
+	}
+
+	var files []string
+	var err error
+	if recursive {
+		files, err = findAssignmentFilesRecursive(".")
+	} else {
+		files, err = findWorkspaceAssignmentFiles()
+	}
+	if err != nil {
+		return failf("Error listing files: %v", err)
+	}
+
+	var packages []toolkit.AssignmentPackage
+	unreadable := 0
+	for _, file := range files {
+		pkg, err := toolkit.LoadPackage(file)
+		if err != nil {
+			unreadable++
+			continue
+		}
+		packages = append(packages, pkg)
+	}
+
+	if remote {
+		err = compareRemoteCounts(packages)
+	} else {
+		printLocalCounts(packages)
+	}
+
+	if unreadable > 0 {
+		printWarning("%d file(s) could not be read and were not counted", unreadable)
+	}
+	return err
+}
+
+func printLocalCounts(packages []toolkit.AssignmentPackage) {
+	counts := make(map[string]int)
+	for _, pkg := range packages {
+		counts[pkg.Assignment.Type]++
+	}
+
+	fmt.Printf("%-25s %s\n", "TYPE", "COUNT")
+	fmt.Println(strings.Repeat("-", 32))
+	for _, assignmentType := range sortedKeys(counts) {
+		fmt.Printf("%-25s %d\n", assignmentType, counts[assignmentType])
+	}
+	fmt.Println(strings.Repeat("-", 32))
+	fmt.Printf("%-25s %d\n", "Total", len(packages))
+}
+
+// compareRemoteCounts prints local and LMS counts per LMS type, since the
+// LMS only knows its own type names
+func compareRemoteCounts(packages []toolkit.AssignmentPackage) error {
+	config := getConfig()
+	if config.LMSEndpoint == "" {
+		return failf("LMS endpoint not configured. Run 'assignment-toolkit config set lms-endpoint <url>'")
+	}
+	client, err := newLMSClientFromConfig(config)
+	if err != nil {
+		return failf("Invalid sync configuration: %v", err)
+	}
+
+	remote, err := client.CountAssignments()
+	if err != nil {
+		return networkErrorf("Failed to get assignment counts from the LMS: %v", err)
+	}
+
+	typeManager := toolkit.GetTypeManager()
+	local := make(map[string]int)
+	for _, pkg := range packages {
+		lmsType, _, err := typeManager.ConvertToLMSFormat(pkg.Assignment.Type)
+		if err != nil {
+			lmsType = "(unknown: " + pkg.Assignment.Type + ")"
+		}
+		local[lmsType]++
+	}
+
+	types := make(map[string]int)
+	for lmsType := range local {
+		types[lmsType]++
+	}
+	for lmsType := range remote.ByType {
+		types[lmsType]++
+	}
+
+	fmt.Printf("%-25s %7s %7s\n", "LMS TYPE", "LOCAL", "REMOTE")
+	fmt.Println(strings.Repeat("-", 41))
+	drift := false
+	for _, lmsType := range sortedKeys(types) {
+		line := fmt.Sprintf("%-25s %7d %7d", lmsType, local[lmsType], remote.ByType[lmsType])
+		if local[lmsType] != remote.ByType[lmsType] {
+			line += "  " + icon(iconWarning)
+			drift = true
+		}
+		fmt.Println(line)
+	}
+	fmt.Println(strings.Repeat("-", 41))
+	totalLine := fmt.Sprintf("%-25s %7d %7d", "Total", len(packages), remote.Total)
+	if len(packages) != remote.Total {
+		totalLine += "  " + icon(iconWarning)
+		drift = true
+	}
+	fmt.Println(totalLine)
+
+	fmt.Println()
+	if drift {
+		printWarning("Local and LMS counts differ; some assignments exist in only one place")
+		printHint("Sync local-only assignments with 'assignment-toolkit sync'")
+	} else {
+		printSuccess("Local and LMS counts match")
+	}
+	return nil
+}
+
+// runFileStats prints the question stats of one assignment
+func runFileStats(filename string) error {
+	pkg, err := toolkit.LoadPackage(filename)
+	if err != nil {
+		return failf("Failed to load assignment: %v", err)
+	}
+
+	stats := toolkit.AssignmentQuestionStats(pkg.Assignment)
+	printMessage(iconInfo, "%s (%s)", pkg.Assignment.Title, pkg.Assignment.Type)
+	fmt.Println()
+	fmt.Printf("%-22s %d\n", "Questions:", stats.Questions)
+	fmt.Printf("%-22s %d\n", "Total points:", stats.TotalPoints)
+	if stats.Questions == 0 {
+		return nil
+	}
+
+	distribution := stats.PointDistribution()
+	values := make([]float64, 0, len(distribution))
+	for points := range distribution {
+		values = append(values, points)
+	}
+	sort.Float64s(values)
+	var spread []string
+	for _, points := range values {
+		spread = append(spread, fmt.Sprintf("%s × %d", formatPoints(points), distribution[points]))
+	}
+	source := "shared evenly"
+	switch stats.ExplicitPoints {
+	case stats.Questions:
+		source = "set per question"
+	case 0:
+	default:
+		source = fmt.Sprintf("%d set per question, the rest shared", stats.ExplicitPoints)
+	}
+	fmt.Printf("%-22s %s (%s)\n", "Points per question:", strings.Join(spread, ", "), source)
+
+	if stats.WithOptions > 0 {
+		fmt.Printf("%-22s %.1f on average (%d question(s) with options)\n", "Options:", stats.AverageOptions(), stats.WithOptions)
+	}
+	if stats.Matching > 0 {
+		fmt.Printf("%-22s %d across %d matching question(s)\n", "Pairs:", stats.Pairs, stats.Matching)
+	}
+
+	var sum float64
+	for _, points := range stats.Points {
+		sum += points
+	}
+	if stats.ExplicitPoints > 0 && sum != float64(stats.TotalPoints) {
+		fmt.Println()
+		printWarning("Question points add up to %s but the assignment is worth %d", formatPoints(sum), stats.TotalPoints)
+	}
+	return nil
+}
+
+// formatPoints shows whole points without decimals
+func formatPoints(points float64) string {
+	unit := "pts"
+	if points == 1 {
+		unit = "pt"
+	}
+	if points == float64(int(points)) {
+		return fmt.Sprintf("%d %s", int(points), unit)
+	}
+	return fmt.Sprintf("%.2f %s", points, unit)
+}
+
+func sortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
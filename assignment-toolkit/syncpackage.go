@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	syncPackageCmd.Flags().Bool("strict-types", false, strictTypesUsage)
+	syncPackageCmd.Flags().Bool("only-changed-resources", false, "Link resources the LMS already has (same checksum) instead of re-uploading them")
+	syncPackageCmd.Flags().Duration("timeout-per-resource", 0, timeoutPerResourceUsage)
+	syncPackageCmd.Flags().BoolP("yes", "y", false, yesUsage)
+	rootCmd.AddCommand(syncPackageCmd)
+}
+
+// Sync-package command
+var syncPackageCmd = &cobra.Command{
+	Use:   "sync-package [dir-or-zip]",
+	Short: "Sync a pre-built package with the LMS",
+	Long: `Sync a directory created by 'package' (or a .zip of one) without rebuilding it.
+Resource files are read from the package's resources/ folder and checked
+against their recorded checksums before anything is uploaded.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeZipFile,
+	RunE:              runSyncPackage,
+}
+
+func runSyncPackage(cmd *cobra.Command, args []string) error {
+	config := getConfig()
+	applyStrictTypesFlag(cmd, &config)
+	if config.LMSEndpoint == "" {
+		return failf("LMS endpoint not configured. Run 'assignment-toolkit config set lms-endpoint <url>'")
+	}
+
+	pkg, cleanup, err := loadPackageDirOrZip(args[0])
+	if err != nil {
+		return failf("Failed to load package: %v", err)
+	}
+	defer cleanup()
+
+	if problems := toolkit.VerifyResourceFiles(pkg); len(problems) > 0 {
+		printError("Package resources failed verification; not syncing")
+		for _, problem := range problems {
+			printBullet("%s", problem)
+		}
+		return errFailed
+	}
+
+	if err := confirmSync(cmd, config); err != nil {
+		return err
+	}
+
+	client, err := newLMSClientFromConfig(config)
+	if err != nil {
+		return failf("Invalid sync configuration: %v", err)
+	}
+	client.OnlyChangedResources, _ = cmd.Flags().GetBool("only-changed-resources")
+	client.ResourceTimeout, _ = cmd.Flags().GetDuration("timeout-per-resource")
+
+	printMessage(iconSync, "Syncing package %s with %s...", args[0], config.LMSEndpoint)
+	reportTypeMapping(pkg)
+	warnMediaResources(&pkg)
+
+	result, err := client.SyncAssignment(pkg)
+	if err != nil {
+		if result != nil && len(result.Conflicts) > 0 {
+			printError("Sync rejected: the assignment conflicts with existing LMS content")
+			printConflicts(result)
+			if result.AssignmentID != "" {
+				fmt.Printf("   Existing assignment ID: %s\n", result.AssignmentID)
+			}
+			return errFailed
+		}
+		return networkErrorf("Sync failed: %v", err)
+	}
+
+	if result.Status == "partial" {
+		printWarning("%s", result.Message)
+		recordFailedResources(args[0], result)
+	} else {
+		printSuccess("Package synced successfully!")
+	}
+	fmt.Printf("   Assignment ID: %s\n", result.AssignmentID)
+	if len(result.ResourceIDs) > 0 {
+		fmt.Printf("   Resources uploaded: %d\n", len(result.ResourceIDs))
+	}
+	if len(result.Conflicts) > 0 {
+		printWarning("The LMS reported conflicts:")
+		printConflicts(result)
+	}
+	return nil
+}
+
+// isPackageDirOrZip reports whether path names a directory or a .zip, as
+// made by 'package', rather than an assignment file
+func isPackageDirOrZip(path string) bool {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return true
+	}
+	return strings.HasSuffix(strings.ToLower(path), ".zip")
+}
+
+// loadPackageDirOrZip loads a directory created by 'package', extracting it
+// first when path is a .zip. cleanup removes the extracted copy.
+func loadPackageDirOrZip(path string) (toolkit.AssignmentPackage, func(), error) {
+	cleanup := func() {}
+	dir := path
+	if strings.HasSuffix(strings.ToLower(path), ".zip") {
+		tempDir, err := ioutil.TempDir("", "assignment-package-")
+		if err != nil {
+			return toolkit.AssignmentPackage{}, cleanup, fmt.Errorf("failed to create temporary directory: %v", err)
+		}
+		cleanup = func() { os.RemoveAll(tempDir) }
+
+		if dir, err = toolkit.ExtractZip(path, tempDir); err != nil {
+			cleanup()
+			return toolkit.AssignmentPackage{}, func() {}, fmt.Errorf("failed to extract %s: %v", path, err)
+		}
+	}
+
+	pkg, err := toolkit.LoadPackageDir(dir)
+	if err != nil {
+		cleanup()
+		return pkg, func() {}, err
+	}
+	return pkg, cleanup, nil
+}
+
+// printConflicts lists the conflicts the LMS reported for a sync
+func printConflicts(result *toolkit.ImportResult) {
+	for _, conflict := range result.Conflicts {
+		printBullet("%s", conflict)
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{tagAddCmd, tagRemoveCmd, metaSetCmd, metaUnsetCmd} {
+		cmd.Flags().Bool("all", false, "Edit every assignment file in the workspace")
+		cmd.Flags().String("filter-type", "", "With --all, only edit assignments of this type")
+		cmd.Flags().Bool("dry-run", false, "Show which files would change without rewriting them")
+		cmd.RegisterFlagCompletionFunc("filter-type", completeTypes)
+		cmd.ValidArgsFunction = completeAssignmentFiles
+	}
+	tagCmd.AddCommand(tagAddCmd)
+	tagCmd.AddCommand(tagRemoveCmd)
+	metaCmd.AddCommand(metaSetCmd)
+	metaCmd.AddCommand(metaUnsetCmd)
+	rootCmd.AddCommand(tagCmd)
+	rootCmd.AddCommand(metaCmd)
+}
+
+// Tag commands
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Add or remove a tag across assignments",
+	Long: `Edit assignment tags (assignment.tags, the tags sent to the LMS) in one
+pass. Name the files to edit, or pass --all for the whole workspace,
+narrowed with --filter-type. --dry-run shows what would change.`,
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add [tag] [file...]",
+	Short: "Add a tag to assignments that don't have it",
+	Args:  bulkEditArgs(1),
+	RunE:  runTagAdd,
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove [tag] [file...]",
+	Short: "Remove a tag from assignments",
+	Args:  bulkEditArgs(1),
+	RunE:  runTagRemove,
+}
+
+// Meta commands
+var metaCmd = &cobra.Command{
+	Use:   "meta",
+	Short: "Set or unset a custom metadata key across assignments",
+	Long: `Edit metadata.custom keys in one pass. Name the files to edit, or pass
+--all for the whole workspace, narrowed with --filter-type. --dry-run shows
+what would change. lms_id is recorded by sync; setting it points the next
+sync at that LMS assignment, and unsetting it makes the next sync create a
+new one.`,
+}
+
+var metaSetCmd = &cobra.Command{
+	Use:   "set [key] [value] [file...]",
+	Short: "Set a custom metadata key",
+	Args:  bulkEditArgs(2),
+	RunE:  runMetaSet,
+}
+
+var metaUnsetCmd = &cobra.Command{
+	Use:   "unset [key] [file...]",
+	Short: "Remove a custom metadata key",
+	Args:  bulkEditArgs(1),
+	RunE:  runMetaUnset,
+}
+
+// bulkEditArgs requires n leading arguments followed by files, or no files
+// with --all
+func bulkEditArgs(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		filterType, _ := cmd.Flags().GetString("filter-type")
+		switch {
+		case len(args) < n:
+			return cobra.MinimumNArgs(n)(cmd, args)
+		case all && len(args) > n:
+			return fmt.Errorf("name files or pass --all, not both")
+		case !all && len(args) == n:
+			return fmt.Errorf("name the files to edit or pass --all")
+		case filterType != "" && !all:
+			return fmt.Errorf("--filter-type requires --all")
+		}
+		return nil
+	}
+}
+
+func runTagAdd(cmd *cobra.Command, args []string) error {
+	tag := strings.TrimSpace(args[0])
+	if tag == "" {
+		return usageErrorf("The tag can't be empty")
+	}
+	return bulkEdit(cmd, args[1:], "tag "+tag+" added", func(pkg *toolkit.AssignmentPackage) bool {
+		for _, t := range pkg.Assignment.Tags {
+			if strings.EqualFold(t, tag) {
+				return false
+			}
+		}
+		pkg.Assignment.Tags = append(pkg.Assignment.Tags, tag)
+		return true
+	})
+}
+
+func runTagRemove(cmd *cobra.Command, args []string) error {
+	tag := args[0]
+	return bulkEdit(cmd, args[1:], "tag "+tag+" removed", func(pkg *toolkit.AssignmentPackage) bool {
+		var kept []string
+		for _, t := range pkg.Assignment.Tags {
+			if !strings.EqualFold(t, tag) {
+				kept = append(kept, t)
+			}
+		}
+		if len(kept) == len(pkg.Assignment.Tags) {
+			return false
+		}
+		pkg.Assignment.Tags = kept
+		return true
+	})
+}
+
+func runMetaSet(cmd *cobra.Command, args []string) error {
+	key, value := args[0], args[1]
+	if err := checkCustomKey(key, false); err != nil {
+		return err
+	}
+	return bulkEdit(cmd, args[2:], key+" set to "+value, func(pkg *toolkit.AssignmentPackage) bool {
+		if current, ok := pkg.Metadata.Custom[key]; ok && current == value {
+			return false
+		}
+		if pkg.Metadata.Custom == nil {
+			pkg.Metadata.Custom = make(map[string]string)
+		}
+		pkg.Metadata.Custom[key] = value
+		return true
+	})
+}
+
+func runMetaUnset(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	if err := checkCustomKey(key, true); err != nil {
+		return err
+	}
+	return bulkEdit(cmd, args[1:], key+" removed", func(pkg *toolkit.AssignmentPackage) bool {
+		if _, ok := pkg.Metadata.Custom[key]; !ok {
+			return false
+		}
+		delete(pkg.Metadata.Custom, key)
+		return true
+	})
+}
+
+// checkCustomKey rejects keys that can't be edited in bulk and warns
+// before lms_id, which decides where sync sends an assignment, is edited
+func checkCustomKey(key string, unset bool) error {
+	switch {
+	case strings.TrimSpace(key) == "":
+		return usageErrorf("The key can't be empty")
+	case key == toolkit.LMSIDKey && unset:
+		printWarning("%s is recorded by sync; without it the next sync creates a new LMS assignment", toolkit.LMSIDKey)
+	case key == toolkit.LMSIDKey:
+		printWarning("%s is recorded by sync; the next sync will update that LMS assignment instead", toolkit.LMSIDKey)
+	}
+	return nil
+}
+
+// bulkEdit applies edit to each file, or with --all to every workspace
+// assignment matching --filter-type, saving those it changes. edit reports
+// whether it changed the package; change describes the edit in the output.
+func bulkEdit(cmd *cobra.Command, files []string, change string, edit func(*toolkit.AssignmentPackage) bool) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	filterType, _ := cmd.Flags().GetString("filter-type")
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		var err error
+		if files, err = findWorkspaceAssignmentFiles(); err != nil {
+			return failf("Error listing files: %v", err)
+		}
+	}
+
+	matched, changed, failed := 0, 0, 0
+	for _, file := range files {
+		pkg, err := toolkit.LoadPackage(file)
+		if err != nil {
+			printError("%s: failed to load: %v", file, err)
+			failed++
+			continue
+		}
+		if filterType != "" && !sameAssignmentType(pkg.Assignment.Type, filterType) {
+			continue
+		}
+		matched++
+
+		hashed := pkg.Metadata.SourceHash != "" && pkg.Metadata.SourceHash == toolkit.CalculateHash(pkg)
+		if !edit(&pkg) {
+			continue
+		}
+		changed++
+
+		if dryRun {
+			printMessage(iconSettings, "%s: would have %s", file, change)
+			continue
+		}
+
+		if hashed {
+			pkg.Metadata.SourceHash = toolkit.CalculateHash(pkg)
+		}
+		pkg.Metadata.Modified = toolkit.Now()
+		if err := toolkit.SavePackage(pkg, file); err != nil {
+			printError("%s: failed to save: %v", file, err)
+			changed--
+			failed++
+			continue
+		}
+		printMessage(iconSettings, "%s: %s", file, change)
+	}
+
+	fmt.Println()
+	if dryRun {
+		fmt.Printf("%d of %d assignment(s) would change\n", changed, matched)
+	} else {
+		fmt.Printf("Changed %d of %d assignment(s)\n", changed, matched)
+	}
+	if failed > 0 {
+		return errFailed
+	}
+	return nil
+}
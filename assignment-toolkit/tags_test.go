@@ -0,0 +1,61 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"assignment-toolkit/pkg/toolkit"
+)
+
+func TestTagAddAndRemove(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "quiz.yaml")
+	pkg := toolkit.AssignmentPackage{Assignment: toolkit.Assignment{Title: "Quiz", Type: "essay", Tags: []string{"algebra"}}}
+	pkg.Metadata.SourceHash = toolkit.CalculateHash(pkg)
+	if err := toolkit.SavePackage(pkg, file); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tag := range []string{"CCSS.7.EE", "ccss.7.ee"} {
+		if err := runTagAdd(tagAddCmd, []string{tag, file}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pkg, err := toolkit.LoadPackage(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"algebra", "CCSS.7.EE"}; !reflect.DeepEqual(pkg.Assignment.Tags, want) {
+		t.Errorf("tags = %v, want %v", pkg.Assignment.Tags, want)
+	}
+	if pkg.Metadata.SourceHash != toolkit.CalculateHash(pkg) {
+		t.Error("source hash not updated after adding a tag")
+	}
+
+	if err := runTagRemove(tagRemoveCmd, []string{"algebra", file}); err != nil {
+		t.Fatal(err)
+	}
+	if pkg, _ = toolkit.LoadPackage(file); !reflect.DeepEqual(pkg.Assignment.Tags, []string{"CCSS.7.EE"}) {
+		t.Errorf("tags after remove = %v, want [CCSS.7.EE]", pkg.Assignment.Tags)
+	}
+}
+
+func TestMetaUnsetLMSID(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "quiz.yaml")
+	pkg := toolkit.AssignmentPackage{Assignment: toolkit.Assignment{Title: "Quiz", Type: "essay"}}
+	pkg.Metadata.Custom = map[string]string{toolkit.LMSIDKey: "asg-1", "unit": "3"}
+	if err := toolkit.SavePackage(pkg, file); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runMetaUnset(metaUnsetCmd, []string{toolkit.LMSIDKey, file}); err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := toolkit.LoadPackage(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"unit": "3"}; !reflect.DeepEqual(pkg.Metadata.Custom, want) {
+		t.Errorf("custom = %v, want %v", pkg.Metadata.Custom, want)
+	}
+}
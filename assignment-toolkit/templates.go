@@ -0,0 +1,495 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	templateListCmd.Flags().String("dir", "templates", "List the templates in this directory")
+	templateUseCmd.Flags().String("output-dir", "", outputDirUsage)
+	templateUseCmd.Flags().String("layout", "", layoutUsage)
+	templateUseCmd.Flags().Bool("compress", false, "Save the assignment gzip-compressed (.yaml.gz)")
+	templateUseCmd.Flags().Bool("force", false, "Overwrite an existing file")
+	templateCmd.AddCommand(templateListCmd)
+	templateCmd.AddCommand(templateUseCmd)
+}
+
+var templateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the templates in templates/",
+	Long: `List every .yaml template in the templates directory (or --dir) with its
+name, type, category, and number of configurable fields. The file name
+without .yaml is what 'create --template' takes.`,
+	Args: cobra.NoArgs,
+	RunE: runTemplateList,
+}
+
+var templateUseCmd = &cobra.Command{
+	Use:   "use [name]",
+	Short: "Create an assignment from a template",
+	Long: `Ask for each of the template's fields, then save a new assignment built
+from the template. A field named after an assignment setting (such as
+points or due_date) sets it, and {{name}} in the template's text is
+replaced by the answer. Required fields are asked again until answered.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTemplates,
+	RunE:              runTemplateUse,
+}
+
+// defaultAssignment is the starting point of every new assignment before a
+// template or the wizard changes it
+func defaultAssignment() toolkit.Assignment {
+	return toolkit.Assignment{
+		Points:           1,
+		AutoGrade:        true,
+		ShowFeedback:     true,
+		ShuffleQuestions: false,
+		AllowReview:      true,
+		TrackAttempts:    true,
+		TrackConfidence:  true,
+		TrackTimeSpent:   true,
+		Published:        true,
+		Quarter:          "Q1",
+	}
+}
+
+// defaultPointsFor returns the points a new assignment of the given type
+// starts with: default_points for the type (or the type an alias names),
+// then defaults.points, then fallback
+func defaultPointsFor(config toolkit.Config, assignmentType string, fallback int) int {
+	if points, ok := config.DefaultPoints[assignmentType]; ok {
+		return points
+	}
+	if canonical, ok := toolkit.GetTypeManager().CanonicalType(assignmentType); ok {
+		if points, ok := config.DefaultPoints[canonical]; ok {
+			return points
+		}
+	}
+	if points, err := strconv.Atoi(config.Defaults["points"]); err == nil {
+		return points
+	}
+	return fallback
+}
+
+// resolveTemplatePath finds a template by its name in the config's
+// templates map, as templates/<name>.yaml, or as a file path
+func resolveTemplatePath(name string, config toolkit.Config) (string, error) {
+	if path, ok := config.Templates[name]; ok {
+		return path, nil
+	}
+	candidate := filepath.Join("templates", name+".yaml")
+	if _, err := os.Stat(candidate); err == nil {
+		return candidate, nil
+	}
+	if _, err := os.Stat(name); err == nil {
+		return name, nil
+	}
+	return "", fmt.Errorf("template %q not found in config templates, templates/, or as a file", name)
+}
+
+// loadTemplate reads a template on top of the default assignment, so only
+// the settings the template file actually lists override the defaults
+func loadTemplate(name string, config toolkit.Config) (*toolkit.Template, error) {
+	path, err := resolveTemplatePath(name, config)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	template := toolkit.Template{Template: defaultAssignment()}
+	if err := yaml.Unmarshal(data, &template); err != nil {
+		return nil, fmt.Errorf("%s is not a valid template: %v", path, err)
+	}
+	return &template, nil
+}
+
+// templateFile is a template file and what it held
+type templateFile struct {
+	Path     string
+	Template toolkit.Template
+	Err      error
+}
+
+// readTemplateDir reads every .yaml template in dir, keeping files that
+// fail to parse with their error
+func readTemplateDir(dir string) ([]templateFile, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []templateFile
+	for _, path := range paths {
+		file := templateFile{Path: path}
+		data, err := ioutil.ReadFile(path)
+		if err == nil {
+			err = yaml.Unmarshal(data, &file.Template)
+		}
+		file.Err = err
+		templates = append(templates, file)
+	}
+	return templates, nil
+}
+
+func runTemplateList(cmd *cobra.Command, args []string) error {
+	dir, _ := cmd.Flags().GetString("dir")
+	templates, err := readTemplateDir(dir)
+	if os.IsNotExist(err) {
+		printError("No template directory %s", dir)
+		printHint("Run 'assignment-toolkit init' to create templates/, or pass --dir")
+		return errFailed
+	}
+	if err != nil {
+		return failf("Error listing templates: %v", err)
+	}
+	if len(templates) == 0 {
+		fmt.Printf("No templates found in %s.\n", dir)
+		return nil
+	}
+
+	fmt.Printf("Found %d template(s) in %s:\n\n", len(templates), dir)
+	fmt.Printf("%-20s %-30s %-15s %-12s %-6s\n", "FILE", "NAME", "TYPE", "CATEGORY", "FIELDS")
+	fmt.Println(strings.Repeat("-", 87))
+
+	for _, file := range templates {
+		name := strings.TrimSuffix(filepath.Base(file.Path), ".yaml")
+		if file.Err != nil {
+			fmt.Printf("%-20s %-30s %-15s %-12s %-6s\n", name, "ERROR", "-", "-", "-")
+			continue
+		}
+
+		template := file.Template
+		title := fitColumn(template.Name, 30)
+		category := template.Category
+		if category == "" {
+			category = "-"
+		}
+		fmt.Printf("%-20s %-30s %-15s %-12s %-6d\n", name, title, template.Type, category, len(template.Fields))
+	}
+	return nil
+}
+
+func runTemplateUse(cmd *cobra.Command, args []string) error {
+	config := getConfig()
+	template, err := loadTemplate(args[0], config)
+	if err != nil {
+		return failf("Failed to load template: %v", err)
+	}
+	if license := template.Metadata["license"]; license != "" {
+		config.License = license
+	}
+	if language := template.Metadata["language"]; language != "" {
+		config.Language = language
+	}
+
+	seed := template.Template
+	if seed.Type == "" {
+		seed.Type = template.Type
+	}
+	assignmentType, ok := toolkit.GetTypeManager().CanonicalType(seed.Type)
+	if !ok {
+		return failf("Template %s has unknown assignment type %q", args[0], seed.Type)
+	}
+	seed.Type = assignmentType
+	if seed.Points == defaultAssignment().Points {
+		seed.Points = defaultPointsFor(config, assignmentType, seed.Points)
+	}
+
+	name := template.Name
+	if name == "" {
+		name = args[0]
+	}
+	fmt.Printf("Creating a %s assignment from %s...\n\n", assignmentType, name)
+
+	answers := make(map[string]interface{})
+	for _, field := range template.Fields {
+		value, err := promptTemplateField(field)
+		if err != nil {
+			return failf("%v", err)
+		}
+		answers[field.Name] = value
+	}
+	assignment, err := applyTemplateFields(seed, answers)
+	if err != nil {
+		return failf("Failed to fill in template %s: %v", args[0], err)
+	}
+	for assignment.Title == "" {
+		title, more := promptAnswer("Assignment title", "")
+		if title == "" {
+			if !more {
+				return failf("An assignment title is required")
+			}
+			printWarning("An assignment title is required")
+		}
+		assignment.Title = title
+	}
+
+	pkg := newAssignmentPackage(cmd, config, assignment, nil)
+	filename, err := outputPath(cmd, config, assignment)
+	if err != nil {
+		return failf("%v", err)
+	}
+	if force, _ := cmd.Flags().GetBool("force"); !force {
+		if _, err := os.Stat(filename); err == nil {
+			return failf("%s already exists (use --force to overwrite)", filename)
+		}
+	}
+	if err := saveNewPackage(pkg, filename); err != nil {
+		return failf("Failed to save assignment: %v", err)
+	}
+
+	printSuccess("Assignment created successfully: %s", filename)
+	printCreatedValidation(filename)
+	return nil
+}
+
+// promptTemplateField asks for one template field until it gets a valid
+// answer. An optional field left empty gives nil; a required one is asked
+// again, failing only when input runs out.
+func promptTemplateField(field toolkit.TemplateField) (interface{}, error) {
+	label := field.Label
+	if label == "" {
+		label = field.Name
+	}
+	if field.Description != "" {
+		printMessage(iconInfo, "%s", field.Description)
+	}
+
+	if field.Type == "select" {
+		if len(field.Options) == 0 {
+			return nil, fmt.Errorf("template field %s is a select with no options", field.Name)
+		}
+		return promptSelect(label+":", field.Options), nil
+	}
+
+	prompt := label
+	if field.Type == "multiselect" && len(field.Options) > 0 {
+		prompt += fmt.Sprintf(" (comma-separated: %s)", strings.Join(field.Options, ", "))
+	}
+	defaultValue := ""
+	if field.Default != nil {
+		defaultValue = templateText(field.Default)
+	}
+
+	for {
+		answer, more := promptAnswer(prompt, defaultValue)
+		if answer == "" {
+			if !field.Required {
+				return nil, nil
+			}
+			if !more {
+				return nil, fmt.Errorf("%s is required", label)
+			}
+			printWarning("%s is required", label)
+			continue
+		}
+
+		value, err := parseTemplateAnswer(field, answer)
+		if err == nil {
+			return value, nil
+		}
+		if !more {
+			return nil, fmt.Errorf("%s: %v", label, err)
+		}
+		printWarning("%v", err)
+	}
+}
+
+// parseTemplateAnswer converts an answer to the field's type
+func parseTemplateAnswer(field toolkit.TemplateField, answer string) (interface{}, error) {
+	switch field.Type {
+	case "int":
+		value, err := strconv.Atoi(answer)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a whole number", answer)
+		}
+		return value, nil
+	case "bool":
+		switch strings.ToLower(answer) {
+		case "y", "yes", "true":
+			return true, nil
+		case "n", "no", "false":
+			return false, nil
+		}
+		return nil, fmt.Errorf("answer y or n, not %q", answer)
+	case "multiselect":
+		var values []string
+		for _, value := range strings.Split(answer, ",") {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			if len(field.Options) > 0 && !containsString(field.Options, value) {
+				return nil, fmt.Errorf("%q is not one of %s", value, strings.Join(field.Options, ", "))
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	}
+
+	if field.Validation != "" {
+		pattern, err := regexp.Compile(field.Validation)
+		if err != nil {
+			return nil, fmt.Errorf("template field %s has an invalid validation pattern: %v", field.Name, err)
+		}
+		if !pattern.MatchString(answer) {
+			return nil, fmt.Errorf("%q doesn't match %s", answer, field.Validation)
+		}
+	}
+	return answer, nil
+}
+
+// applyTemplateFields fills a template's assignment with the answers to
+// its fields: {{name}} in any text is replaced by the answer (or removed
+// when the field was left empty), then answers to fields named after an
+// assignment setting, such as points or due_date, set it
+func applyTemplateFields(seed toolkit.Assignment, answers map[string]interface{}) (toolkit.Assignment, error) {
+	data, err := yaml.Marshal(seed)
+	if err != nil {
+		return seed, err
+	}
+	var doc map[interface{}]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return seed, err
+	}
+
+	filled := fillPlaceholders(doc, answers).(map[interface{}]interface{})
+	settings := assignmentSettings()
+	for name, value := range answers {
+		setting, ok := settings[name]
+		if value == nil || !ok {
+			continue
+		}
+		if filled[name], err = settingValue(setting, value); err != nil {
+			return seed, fmt.Errorf("%s: %v", name, err)
+		}
+	}
+
+	if data, err = yaml.Marshal(filled); err != nil {
+		return seed, err
+	}
+	var assignment toolkit.Assignment
+	if err := yaml.Unmarshal(data, &assignment); err != nil {
+		return seed, fmt.Errorf("the answers don't fit the assignment: %v", err)
+	}
+	return assignment, nil
+}
+
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*([\w-]+)\s*\}\}`)
+
+// fillPlaceholders replaces {{name}} in every string of a decoded YAML
+// value. A string that is just one placeholder takes the answer as is, so
+// numbers and lists keep their type.
+func fillPlaceholders(value interface{}, answers map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if match := templatePlaceholder.FindStringSubmatch(v); match != nil && match[0] == v {
+			if answer, ok := answers[match[1]]; ok {
+				if answer == nil {
+					return ""
+				}
+				return answer
+			}
+		}
+		return templatePlaceholder.ReplaceAllStringFunc(v, func(placeholder string) string {
+			name := templatePlaceholder.FindStringSubmatch(placeholder)[1]
+			answer, ok := answers[name]
+			if !ok {
+				return placeholder
+			}
+			return templateText(answer)
+		})
+	case map[interface{}]interface{}:
+		for key, item := range v {
+			v[key] = fillPlaceholders(item, answers)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = fillPlaceholders(item, answers)
+		}
+	}
+	return value
+}
+
+// templateText is how an answer or default reads inside text
+func templateText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []string:
+		return strings.Join(v, ", ")
+	case []interface{}:
+		var items []string
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, ", ")
+	case bool:
+		if v {
+			return "y"
+		}
+		return "n"
+	}
+	return fmt.Sprint(value)
+}
+
+// assignmentSettings maps the YAML keys of an assignment to their types
+func assignmentSettings() map[string]reflect.Type {
+	settings := make(map[string]reflect.Type)
+	assignmentType := reflect.TypeOf(toolkit.Assignment{})
+	for i := 0; i < assignmentType.NumField(); i++ {
+		field := assignmentType.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name != "" && name != "-" {
+			settings[name] = field.Type
+		}
+	}
+	return settings
+}
+
+// settingValue converts a text answer for a number or date setting, such
+// as a points field declared as a string or a due_date given as YYYY-MM-DD
+func settingValue(setting reflect.Type, value interface{}) (interface{}, error) {
+	text, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+	if setting.Kind() == reflect.Ptr {
+		setting = setting.Elem()
+	}
+
+	switch {
+	case setting == reflect.TypeOf(time.Time{}):
+		for _, layout := range []string{"2006-01-02", time.RFC3339} {
+			if t, err := time.Parse(layout, text); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("%q is not a date (YYYY-MM-DD)", text)
+	case setting.Kind() == reflect.Int:
+		number, err := strconv.Atoi(text)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a whole number", text)
+		}
+		return number, nil
+	}
+	return value, nil
+}
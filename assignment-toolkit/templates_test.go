@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bufio"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"assignment-toolkit/pkg/toolkit"
+)
+
+func TestLoadTemplateKeepsUnsetDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dept.yaml")
+	data := "type: writing\ntemplate:\n  points: 10\n  quarter: Q3\n  track_confidence: false\n"
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	template, err := loadTemplate("dept", toolkit.Config{Templates: map[string]string{"dept": path}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed := template.Template
+	if seed.Points != 10 || seed.Quarter != "Q3" || seed.TrackConfidence {
+		t.Errorf("template settings not applied: %+v", seed)
+	}
+	if !seed.TrackAttempts || !seed.Published || !seed.AutoGrade {
+		t.Errorf("defaults the template doesn't list were lost: %+v", seed)
+	}
+
+	if _, err := loadTemplate("missing", toolkit.Config{}); err == nil {
+		t.Error("expected an error for a missing template")
+	}
+}
+
+func TestDefaultPointsFor(t *testing.T) {
+	config := toolkit.Config{
+		DefaultPoints: map[string]int{"essay": 20, "multiple-choice": 2},
+		Defaults:      map[string]string{"points": "5"},
+	}
+
+	for assignmentType, want := range map[string]int{"essay": 20, "mcq": 2, "matching": 5} {
+		if got := defaultPointsFor(config, assignmentType, 1); got != want {
+			t.Errorf("defaultPointsFor(%q) = %d, want %d", assignmentType, got, want)
+		}
+	}
+	if got := defaultPointsFor(toolkit.Config{}, "matching", 1); got != 1 {
+		t.Errorf("without config got %d, want the fallback 1", got)
+	}
+}
+
+func TestReadTemplateDirKeepsMalformedFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"quiz.yaml":   "name: Quiz\ntype: multiple-choice\ncategory: assessment\nfields:\n  - name: topic\n  - name: count\n",
+		"broken.yaml": "name: [unclosed\n",
+		"notes.txt":   "not a template",
+	}
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	templates, err := readTemplateDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 2 {
+		t.Fatalf("got %d templates, want the two .yaml files", len(templates))
+	}
+	broken, quiz := templates[0], templates[1]
+	if broken.Err == nil {
+		t.Error("malformed template read without an error")
+	}
+	if quiz.Err != nil || quiz.Template.Name != "Quiz" || len(quiz.Template.Fields) != 2 {
+		t.Errorf("quiz = %+v, want Quiz with two fields", quiz)
+	}
+}
+
+func TestTemplateUseFillsFields(t *testing.T) {
+	previous := stdinReader
+	t.Cleanup(func() { stdinReader = previous })
+
+	fields := []toolkit.TemplateField{
+		{Name: "topic", Label: "Topic", Required: true},
+		{Name: "points", Label: "Points", Type: "int", Default: 10},
+		{Name: "due_date", Label: "Due date"},
+		{Name: "tags", Label: "Tags", Type: "multiselect", Options: []string{"algebra", "geometry"}},
+	}
+	// An empty required answer and an unknown option are both asked again
+	stdinReader = bufio.NewReader(strings.NewReader("\nFractions\n\n2024-05-01\ncalculus\nalgebra, geometry\n"))
+
+	answers := make(map[string]interface{})
+	for _, field := range fields {
+		value, err := promptTemplateField(field)
+		if err != nil {
+			t.Fatal(err)
+		}
+		answers[field.Name] = value
+	}
+
+	seed := defaultAssignment()
+	seed.Title = "{{topic}} quiz"
+	seed.Instructions = "Answer every question about {{ topic }}."
+	assignment, err := applyTemplateFields(seed, answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assignment.Title != "Fractions quiz" || assignment.Instructions != "Answer every question about Fractions." {
+		t.Errorf("placeholders not filled: %q, %q", assignment.Title, assignment.Instructions)
+	}
+	if assignment.Points != 10 || assignment.DueDate == nil || assignment.DueDate.Format("2006-01-02") != "2024-05-01" {
+		t.Errorf("points = %d, due date = %v; want 10 and 2024-05-01", assignment.Points, assignment.DueDate)
+	}
+	if !reflect.DeepEqual(assignment.Tags, []string{"algebra", "geometry"}) {
+		t.Errorf("tags = %v, want [algebra geometry]", assignment.Tags)
+	}
+
+	stdinReader = bufio.NewReader(strings.NewReader(""))
+	if _, err := promptTemplateField(fields[0]); err == nil {
+		t.Error("expected an error for a required field once input runs out")
+	}
+}
+
+func TestFitColumnCutsByRune(t *testing.T) {
+	name := strings.Repeat("แบบฝึกหัด", 5) // Thai, 3 bytes per rune
+	got := fitColumn(name, 30)
+	if n := len([]rune(got)); n != 30 || !strings.HasSuffix(got, "...") || !utf8.ValidString(got) {
+		t.Errorf("fitColumn = %q (%d runes), want 30 valid runes ending in ...", got, n)
+	}
+	if got := fitColumn("Short name", 30); got != "Short name" {
+		t.Errorf("fitColumn changed a short name to %q", got)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"assignment-toolkit/pkg/toolkit"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	typesAddCmd.Flags().String("subtype", "", "LMS subtype the type maps to")
+	typesAddCmd.Flags().String("desc", "", "Description shown by 'types'")
+	typesCmd.AddCommand(typesAddCmd)
+	typesCmd.AddCommand(typesRemoveCmd)
+}
+
+var typesAddCmd = &cobra.Command{
+	Use:   "add [portable-type] [lms-type]",
+	Short: "Add a custom assignment type to this workspace",
+	Long: `Map a new portable type name to an LMS type and save it in the config's
+custom_types, so 'create', 'validate', and 'sync' accept it in this workspace.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTypesAdd,
+}
+
+var typesRemoveCmd = &cobra.Command{
+	Use:               "remove [portable-type]",
+	Short:             "Remove a custom assignment type from this workspace",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeCustomTypes,
+	RunE:              runTypesRemove,
+}
+
+func runTypesAdd(cmd *cobra.Command, args []string) error {
+	subtype, _ := cmd.Flags().GetString("subtype")
+	description, _ := cmd.Flags().GetString("desc")
+	mapping := toolkit.TypeMapping{
+		PortableType: strings.ToLower(strings.TrimSpace(args[0])),
+		LMSType:      args[1],
+		LMSSubtype:   subtype,
+		Description:  description,
+	}
+	if mapping.Description == "" {
+		mapping.Description = fmt.Sprintf("Custom %s type", mapping.LMSType)
+	}
+
+	err := updateConfigFile(func(config *toolkit.Config) error {
+		for _, existing := range config.CustomTypes {
+			if strings.EqualFold(existing.PortableType, mapping.PortableType) {
+				return fmt.Errorf("%s is already a custom type; remove it first to change it", mapping.PortableType)
+			}
+		}
+
+		// Check against a fresh manager so the built-in names are the only
+		// ones that can clash
+		customTypes := append(append([]toolkit.TypeMapping{}, config.CustomTypes...), mapping)
+		if err := toolkit.NewAssignmentTypeManager().SetCustomMappings(customTypes); err != nil {
+			return err
+		}
+		config.CustomTypes = customTypes
+		return nil
+	})
+	if err != nil {
+		return failf("Failed to add type: %v", err)
+	}
+
+	lmsInfo := mapping.LMSType
+	if mapping.LMSSubtype != "" {
+		lmsInfo += " (" + mapping.LMSSubtype + ")"
+	}
+	printSuccess("Added custom type %s %s %s", mapping.PortableType, icon(iconArrow), lmsInfo)
+	if !containsString(toolkit.GetTypeManager().GetLMSTypes(), mapping.LMSType) {
+		printWarning("%s is not an LMS type the built-in types use; check that your LMS accepts it", mapping.LMSType)
+	}
+	return nil
+}
+
+func runTypesRemove(cmd *cobra.Command, args []string) error {
+	portableType := strings.ToLower(strings.TrimSpace(args[0]))
+
+	err := updateConfigFile(func(config *toolkit.Config) error {
+		for i, existing := range config.CustomTypes {
+			if strings.EqualFold(existing.PortableType, portableType) {
+				config.CustomTypes = append(config.CustomTypes[:i], config.CustomTypes[i+1:]...)
+				return nil
+			}
+		}
+		if toolkit.GetTypeManager().ValidatePortableType(portableType) {
+			return fmt.Errorf("%s is a built-in type and can't be removed", portableType)
+		}
+		return fmt.Errorf("%s is not a custom type in this workspace", portableType)
+	})
+	if err != nil {
+		return failf("Failed to remove type: %v", err)
+	}
+
+	printSuccess("Removed custom type %s", portableType)
+	printHint("Assignments that still use %s will fail validation until their type is changed", portableType)
+	return nil
+}